@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"sync/atomic"
+	"tmp/pragmatic-cases/health"
 
 	"cloud.google.com/go/pubsub"
 )
@@ -17,27 +18,32 @@ const port = "8080"
 func main() {
 	projectID := os.Getenv("PUBSUB_PROJECT_ID")
 	subID := os.Getenv("PUBSUB_SUBSCRIPTION_ID")
+	var receiving atomic.Bool
 	go func() {
-		if err := pullMsgs(os.Stdout, projectID, subID); err != nil {
+		if err := pullMsgs(os.Stdout, projectID, subID, &receiving); err != nil {
 			log.Fatalf("pullMsgs failed: %v", err)
 			os.Exit(1)
 		}
 	}()
 
 	// this is necessary to run on Cloud Run
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		if _, err := w.Write([]byte("OK")); err != nil {
-			log.Printf("Error writing response: %v", err)
+	h := health.New()
+	h.Readiness.Register("subscription", health.CheckFunc(func(context.Context) error {
+		if !receiving.Load() {
+			return fmt.Errorf("not receiving from %s", subID)
 		}
-	})
+		return nil
+	}), health.WithCacheTTL(0))
+	h.Mount(http.DefaultServeMux)
 	log.Printf("Listening on port %s", port)
 	if err := http.ListenAndServe(":"+port, nil); err != nil {
 		log.Fatal(err)
 	}
 }
 
-func pullMsgs(w io.Writer, projectID, subID string) error {
+// pullMsgs sets receiving once the subscription is known to exist and Receive
+// is started, and clears it when Receive returns.
+func pullMsgs(w io.Writer, projectID, subID string, receiving *atomic.Bool) error {
 	// projectID := "my-project-id"
 	// subID := "my-sub"
 	ctx := context.Background()
@@ -48,8 +54,17 @@ func pullMsgs(w io.Writer, projectID, subID string) error {
 	defer client.Close()
 
 	sub := client.Subscription(subID)
+	ok, err := sub.Exists(ctx)
+	if err != nil {
+		return fmt.Errorf("sub.Exists: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("subscription %s does not exist", subID)
+	}
 
 	var received int32
+	receiving.Store(true)
+	defer receiving.Store(false)
 	err = sub.Receive(ctx, func(_ context.Context, msg *pubsub.Message) {
 		fmt.Fprintf(w, "Got message: %q\n", string(msg.Data))
 		atomic.AddInt32(&received, 1)
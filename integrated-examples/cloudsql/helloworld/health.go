@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// This module is built on its own, so instead of importing
+// tmp/pragmatic-cases/health it answers the same JSON report for its one
+// check, a ping of the database.
+
+type healthResult struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Latency   string    `json:"latency"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type healthReport struct {
+	Status string                  `json:"status"`
+	Checks map[string]healthResult `json:"checks,omitempty"`
+}
+
+func livez(w http.ResponseWriter, r *http.Request) {
+	writeReport(w, healthReport{Status: "up"})
+}
+
+// readyz is down while the database does not answer a ping within a second.
+func (s *server) readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	err := s.db.PingContext(ctx)
+	result := healthResult{Status: "up", Latency: time.Since(start).String(), CheckedAt: start}
+	if err != nil {
+		result.Status, result.Error = "down", err.Error()
+	}
+	writeReport(w, healthReport{Status: result.Status, Checks: map[string]healthResult{"db": result}})
+}
+
+func writeReport(w http.ResponseWriter, report healthReport) {
+	w.Header().Set("content-type", "application/json")
+	w.Header().Set("cache-control", "no-store")
+	if report.Status != "up" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
@@ -37,6 +37,8 @@ func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	router.Handle("/", http.HandlerFunc(handler))
 	router.Handle("/get", http.HandlerFunc(s.getHandler))
+	router.Handle("/livez", http.HandlerFunc(livez))
+	router.Handle("/readyz", http.HandlerFunc(s.readyz))
 	router.ServeHTTP(w, r)
 }
 
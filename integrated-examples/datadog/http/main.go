@@ -2,6 +2,7 @@ package main
 
 import (
 	"net/http"
+	"tmp/pragmatic-cases/health"
 
 	httptrace "gopkg.in/DataDog/dd-trace-go.v1/contrib/net/http"
 	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
@@ -15,6 +16,7 @@ func main() {
 		// nolint
 		w.Write([]byte("Hello World!\n"))
 	})
+	health.New().Mount(mux)
 	// nolint
 	http.ListenAndServe(":8080", mux)
 }
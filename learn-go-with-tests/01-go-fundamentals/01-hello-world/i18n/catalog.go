@@ -0,0 +1,217 @@
+// Package i18n is a message catalog keyed by BCP 47 language tags.
+//
+// Messages are looked up along a fallback chain, "pt-BR" then "pt" then the
+// fallback language of the catalog, and may have CLDR plural forms:
+//
+//	{
+//		"hello": "Olá, {name}!",
+//		"apples": {"one": "{count} maçã", "other": "{count} maçãs"}
+//	}
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+var ErrMissingMessage = errors.New("i18n: missing message")
+
+// Message is a text, or its plural forms keyed by CLDR category ("zero",
+// "one", "two", "few", "many", "other"). Plural messages need "other".
+type Message struct {
+	Text  string
+	Forms map[string]string
+}
+
+func (m *Message) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &m.Text); err == nil {
+		return nil
+	}
+	if err := json.Unmarshal(b, &m.Forms); err != nil {
+		return errors.New("a message is a string or an object of plural forms")
+	}
+	if _, ok := m.Forms["other"]; !ok {
+		return errors.New(`plural forms need an "other" form`)
+	}
+	return nil
+}
+
+// Vars fill the {placeholders} of a message.
+type Vars map[string]any
+
+// Catalog holds messages per language. Add languages before using it; it is
+// safe for concurrent lookups, not for concurrent changes.
+type Catalog struct {
+	fallback language.Tag
+	tags     []language.Tag // the fallback first, as language.NewMatcher wants
+	messages map[language.Tag]map[string]Message
+	matcher  language.Matcher
+}
+
+// New returns an empty catalog falling back to fallback.
+func New(fallback language.Tag) *Catalog {
+	c := &Catalog{fallback: fallback, messages: map[language.Tag]map[string]Message{}}
+	c.Add(fallback, nil)
+	return c
+}
+
+// Load reads every <tag>.json file of dir in fsys, e.g. "en.json" and
+// "pt-BR.json".
+func Load(fsys fs.FS, dir string, fallback language.Tag) (*Catalog, error) {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	// "pt" before "pt-BR": the matcher prefers the first of equal matches
+	sort.Slice(files, func(i, j int) bool {
+		return strings.TrimSuffix(files[i], ".json") < strings.TrimSuffix(files[j], ".json")
+	})
+	c := New(fallback)
+	for _, file := range files {
+		tag, err := language.Parse(strings.TrimSuffix(path.Base(file), ".json"))
+		if err != nil {
+			return nil, fmt.Errorf("catalog %s is not named after a language: %w", file, err)
+		}
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		var messages map[string]Message
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("catalog %s: %w", file, err)
+		}
+		c.Add(tag, messages)
+	}
+	return c, nil
+}
+
+// Add merges messages into the catalog of tag, replacing existing keys.
+func (c *Catalog) Add(tag language.Tag, messages map[string]Message) {
+	if _, ok := c.messages[tag]; !ok {
+		c.messages[tag] = map[string]Message{}
+		c.tags = append(c.tags, tag)
+		c.matcher = language.NewMatcher(c.tags)
+	}
+	for key, m := range messages {
+		c.messages[tag][key] = m
+	}
+}
+
+// Languages returns the languages of the catalog, the fallback first.
+func (c *Catalog) Languages() []language.Tag {
+	return append([]language.Tag(nil), c.tags...)
+}
+
+// Match returns the language of the catalog that best serves a user
+// preferring the given languages, or the fallback.
+func (c *Catalog) Match(preferred ...language.Tag) language.Tag {
+	_, i, confidence := c.matcher.Match(preferred...)
+	if confidence == language.No {
+		return c.fallback
+	}
+	return c.tags[i]
+}
+
+// MatchAcceptLanguage is Match for an Accept-Language header like
+// "fr-CH, fr;q=0.9, en;q=0.8". Invalid headers get the fallback.
+func (c *Catalog) MatchAcceptLanguage(header string) language.Tag {
+	preferred, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return c.fallback
+	}
+	return c.Match(preferred...)
+}
+
+// Translate returns the message key in tag with vars filled in, looking up
+// the parents of tag and then the fallback when tag does not have it.
+func (c *Catalog) Translate(tag language.Tag, key string, vars Vars) (string, error) {
+	m, _, err := c.lookup(tag, key)
+	if err != nil {
+		return "", err
+	}
+	text := m.Text
+	if m.Forms != nil {
+		text = m.Forms["other"]
+	}
+	return fill(text, vars), nil
+}
+
+// TranslatePlural picks the plural form of key for count, following the
+// plural rules of the language the message was found in. {count} is count.
+func (c *Catalog) TranslatePlural(tag language.Tag, key string, count int, vars Vars) (string, error) {
+	m, found, err := c.lookup(tag, key)
+	if err != nil {
+		return "", err
+	}
+	text := m.Text
+	if m.Forms != nil {
+		form, ok := m.Forms[formOf(found, count)]
+		if !ok {
+			form = m.Forms["other"]
+		}
+		text = form
+	}
+	withCount := Vars{"count": count}
+	for k, v := range vars {
+		withCount[k] = v
+	}
+	return fill(text, withCount), nil
+}
+
+// Fallbacks returns the chain of languages looked up for tag: "pt-BR", "pt"
+// and the fallback.
+func (c *Catalog) Fallbacks(tag language.Tag) []language.Tag {
+	var chain []language.Tag
+	for t := tag; t != language.Und; t = t.Parent() {
+		chain = append(chain, t)
+	}
+	if len(chain) == 0 || chain[len(chain)-1] != c.fallback {
+		chain = append(chain, c.fallback)
+	}
+	return chain
+}
+
+func (c *Catalog) lookup(tag language.Tag, key string) (Message, language.Tag, error) {
+	for _, t := range c.Fallbacks(tag) {
+		if m, ok := c.messages[t][key]; ok {
+			return m, t, nil
+		}
+	}
+	return Message{}, language.Und, fmt.Errorf("%w %q for %s", ErrMissingMessage, key, tag)
+}
+
+var forms = map[plural.Form]string{
+	plural.Zero:  "zero",
+	plural.One:   "one",
+	plural.Two:   "two",
+	plural.Few:   "few",
+	plural.Many:  "many",
+	plural.Other: "other",
+}
+
+func formOf(tag language.Tag, count int) string {
+	n := count
+	if n < 0 {
+		n = -n
+	}
+	return forms[plural.Cardinal.MatchPlural(tag, n, 0, 0, 0, 0)]
+}
+
+func fill(text string, vars Vars) string {
+	if len(vars) == 0 {
+		return text
+	}
+	pairs := make([]string, 0, 2*len(vars))
+	for k, v := range vars {
+		pairs = append(pairs, "{"+k+"}", fmt.Sprint(v))
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
@@ -0,0 +1,177 @@
+package i18n
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/language"
+)
+
+var testFS = fstest.MapFS{
+	"messages/en.json": {Data: []byte(`{
+		"hello": "Hello, {name}!",
+		"bye": "Bye",
+		"apples": {"one": "{count} apple", "other": "{count} apples"}
+	}`)},
+	"messages/fr.json": {Data: []byte(`{
+		"hello": "Bonjour, {name} !",
+		"apples": {"one": "{count} pomme", "other": "{count} pommes"}
+	}`)},
+	"messages/pt.json":    {Data: []byte(`{"hello": "Olá, {name}!", "bye": "Tchau"}`)},
+	"messages/pt-BR.json": {Data: []byte(`{"hello": "Oi, {name}!"}`)},
+	"messages/ja.json":    {Data: []byte(`{"apples": {"other": "りんご{count}個"}}`)},
+	"messages/README.md":  {Data: []byte("not a catalog")},
+}
+
+func TestMatchAcceptLanguage(t *testing.T) {
+	c := loadTestCatalog(t)
+
+	cases := []struct {
+		header string
+		want   language.Tag
+	}{
+		{"fr-CH, fr;q=0.9, en;q=0.8", language.French},
+		{"pt-BR", language.BrazilianPortuguese},
+		{"pt-PT", language.Portuguese},
+		{"de;q=0.1, pt;q=0.5, ja", language.Japanese},
+		{"de", language.English},
+		{"", language.English},
+		{"not a ;; header", language.English},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := c.MatchAcceptLanguage(tt.header); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	c := loadTestCatalog(t)
+
+	cases := []struct {
+		tag  language.Tag
+		key  string
+		want string
+	}{
+		{language.BrazilianPortuguese, "hello", "Oi, Chris!"},
+		{language.BrazilianPortuguese, "bye", "Tchau"},
+		{language.French, "bye", "Bye"},
+		{language.MustParse("fr-CA"), "hello", "Bonjour, Chris !"},
+		{language.German, "hello", "Hello, Chris!"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.tag.String()+" "+tt.key, func(t *testing.T) {
+			got, err := c.Translate(tt.tag, tt.key, Vars{"name": "Chris"})
+
+			assertNoError(t, err)
+			assertText(t, got, tt.want)
+		})
+	}
+
+	t.Run("missing everywhere", func(t *testing.T) {
+		_, err := c.Translate(language.French, "nope", nil)
+
+		if !errors.Is(err, ErrMissingMessage) {
+			t.Errorf("got %v, want %v", err, ErrMissingMessage)
+		}
+	})
+
+	t.Run("fallback chain", func(t *testing.T) {
+		got := c.Fallbacks(language.BrazilianPortuguese)
+		want := []language.Tag{language.BrazilianPortuguese, language.Portuguese, language.English}
+
+		if len(got) != len(want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		}
+	})
+}
+
+func TestTranslatePlural(t *testing.T) {
+	c := loadTestCatalog(t)
+
+	cases := []struct {
+		tag   language.Tag
+		count int
+		want  string
+	}{
+		{language.English, 1, "1 apple"},
+		{language.English, 0, "0 apples"},
+		{language.English, 2, "2 apples"},
+		// French counts 0 and 1 as singular
+		{language.French, 0, "0 pomme"},
+		{language.French, 1, "1 pomme"},
+		{language.French, 2, "2 pommes"},
+		// Japanese has no plural
+		{language.Japanese, 1, "りんご1個"},
+		// Portuguese has no apples, the English rules apply to the English text
+		{language.Portuguese, 1, "1 apple"},
+	}
+
+	for _, tt := range cases {
+		got, err := c.TranslatePlural(tt.tag, "apples", tt.count, nil)
+
+		assertNoError(t, err)
+		assertText(t, got, tt.want)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	bad := map[string]fstest.MapFS{
+		"not a language": {"m/english.json": {Data: []byte(`{}`)}},
+		"not json":       {"m/en.json": {Data: []byte(`hello`)}},
+		"not a message":  {"m/en.json": {Data: []byte(`{"hello": 42}`)}},
+		"no other form":  {"m/en.json": {Data: []byte(`{"apples": {"one": "an apple"}}`)}},
+	}
+
+	for name, fsys := range bad {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(fsys, "m", language.English); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	t.Run("the embedded locales", func(t *testing.T) {
+		c, err := Load(Locales, "locales", language.English)
+		assertNoError(t, err)
+
+		for _, tag := range c.Languages() {
+			for _, key := range []string{"hello", "greeting", "world"} {
+				if _, err := c.Translate(tag, key, nil); err != nil {
+					t.Errorf("%s: %v", tag, err)
+				}
+			}
+		}
+	})
+}
+
+func loadTestCatalog(t testing.TB) *Catalog {
+	t.Helper()
+	c, err := Load(testFS, "messages", language.English)
+	assertNoError(t, err)
+	return c
+}
+
+func assertText(t testing.TB, got, want string) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
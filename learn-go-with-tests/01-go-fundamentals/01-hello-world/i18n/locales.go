@@ -0,0 +1,27 @@
+package i18n
+
+import (
+	"embed"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// Locales are the messages of the hello world examples, one file per
+// language.
+//
+//go:embed locales/*.json
+var Locales embed.FS
+
+var loadDefault = sync.OnceValue(func() *Catalog {
+	c, err := Load(Locales, "locales", language.English)
+	if err != nil {
+		panic(err) // the embedded catalogs are broken, tests catch it
+	}
+	return c
+})
+
+// Default returns the catalog of Locales, falling back to English.
+func Default() *Catalog {
+	return loadDefault()
+}
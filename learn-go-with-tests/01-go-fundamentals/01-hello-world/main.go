@@ -1,29 +1,48 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
 
-const helloEnglish = "Hello"
-const helloSpanish = "Ola"
-const frenchHello = "Oi"
-const defaultNameEnglish = "World"
+	"golang.org/x/text/language"
+
+	"tmp/learn-go-with-tests/01-go-fundamentals/01-hello-world/i18n"
+)
+
+// languages are the names Hello has always accepted. Anything else is read
+// as a BCP 47 tag like "pt-BR".
+var languages = map[string]language.Tag{
+	"English":    language.English,
+	"Spanish":    language.Spanish,
+	"French":     language.French,
+	"Portuguese": language.Portuguese,
+	"German":     language.German,
+}
 
 func Hello(name string, language string) string {
+	tag := tagOf(language)
 	if len(name) == 0 {
-		name = defaultNameEnglish
+		name, _ = i18n.Default().Translate(tag, "world", nil)
 	}
-	hello := GetHello(language)
-	return hello + ", " + name + "!"
+	hello, _ := i18n.Default().Translate(tag, "greeting", i18n.Vars{"name": name})
+	return hello
 }
 
 func GetHello(language string) string {
-	switch language {
-	case "French":
-		return frenchHello
-	case "Spanish":
-		return helloSpanish
-	default:
-		return helloEnglish
+	hello, _ := i18n.Default().Translate(tagOf(language), "hello", nil)
+	return hello
+}
+
+// tagOf returns the closest language of the catalog, English when there is
+// none.
+func tagOf(name string) language.Tag {
+	tag, ok := languages[name]
+	if !ok {
+		var err error
+		if tag, err = language.Parse(name); err != nil {
+			return language.English
+		}
 	}
+	return i18n.Default().Match(tag)
 }
 
 func main() {
@@ -23,7 +23,40 @@ func TestHello(t *testing.T) {
 
 	t.Run("In Spanish", func(t *testing.T) {
 		got := Hello("Elodie", "Spanish")
-		want := "Ola, Elodie!"
+		want := "Hola, Elodie!"
 		assertCorrectMessage(t, got, want)
 	})
+
+	t.Run("In French", func(t *testing.T) {
+		got := Hello("Elodie", "French")
+		want := "Bonjour, Elodie !"
+		assertCorrectMessage(t, got, want)
+	})
+
+	t.Run("with a language tag", func(t *testing.T) {
+		got := Hello("", "pt-BR")
+		want := "Oi, Mundo!"
+		assertCorrectMessage(t, got, want)
+	})
+
+	t.Run("unknown languages are English", func(t *testing.T) {
+		got := Hello("Naka", "Klingon")
+		want := "Hello, Naka!"
+		assertCorrectMessage(t, got, want)
+	})
+}
+
+func TestGetHello(t *testing.T) {
+	cases := map[string]string{
+		"":        "Hello",
+		"English": "Hello",
+		"Spanish": "Hola",
+		"French":  "Bonjour",
+		"de-AT":   "Hallo",
+	}
+	for language, want := range cases {
+		if got := GetHello(language); got != want {
+			t.Errorf("GetHello(%q) = %q, want %q", language, got, want)
+		}
+	}
 }
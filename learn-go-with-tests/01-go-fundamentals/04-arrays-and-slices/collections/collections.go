@@ -0,0 +1,104 @@
+// Package collections is Sum, SumAll and SumAllTails grown up: generic
+// helpers over slices, with lazy iter.Seq variants in seq.go.
+package collections
+
+// Pair is an element of Zip.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Map returns f applied to every element of xs.
+func Map[T, U any](xs []T, f func(T) U) []U {
+	out := make([]U, 0, len(xs))
+	for _, x := range xs {
+		out = append(out, f(x))
+	}
+	return out
+}
+
+// Filter returns the elements of xs for which keep is true, in order.
+func Filter[T any](xs []T, keep func(T) bool) []T {
+	var out []T
+	for _, x := range xs {
+		if keep(x) {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+// Reduce folds xs from the left, starting from initial:
+// Reduce([]int{1, 2, 3}, 0, add) is add(add(add(0, 1), 2), 3).
+func Reduce[T, U any](xs []T, initial U, f func(U, T) U) U {
+	acc := initial
+	for _, x := range xs {
+		acc = f(acc, x)
+	}
+	return acc
+}
+
+// GroupBy groups the elements of xs by key, keeping their order in each
+// group.
+func GroupBy[T any, K comparable](xs []T, key func(T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for _, x := range xs {
+		k := key(x)
+		groups[k] = append(groups[k], x)
+	}
+	return groups
+}
+
+// Partition splits xs into the elements for which pred is true and the rest.
+func Partition[T any](xs []T, pred func(T) bool) (yes, no []T) {
+	for _, x := range xs {
+		if pred(x) {
+			yes = append(yes, x)
+		} else {
+			no = append(no, x)
+		}
+	}
+	return yes, no
+}
+
+// Chunk splits xs into consecutive slices of size elements, the last one
+// possibly shorter. The chunks share the memory of xs but cannot append
+// into each other. It panics if size < 1.
+func Chunk[T any](xs []T, size int) [][]T {
+	if size < 1 {
+		panic("collections: chunk size must be at least 1")
+	}
+	chunks := make([][]T, 0, (len(xs)+size-1)/size)
+	for i := 0; i < len(xs); i += size {
+		end := min(i+size, len(xs))
+		chunks = append(chunks, xs[i:end:end])
+	}
+	return chunks
+}
+
+// Window returns every run of size consecutive elements of xs, sliding by
+// one: Window([]int{1, 2, 3}, 2) is [[1 2] [2 3]]. Like Chunk, windows share
+// the memory of xs. It panics if size < 1.
+func Window[T any](xs []T, size int) [][]T {
+	if size < 1 {
+		panic("collections: window size must be at least 1")
+	}
+	if len(xs) < size {
+		return nil
+	}
+	windows := make([][]T, 0, len(xs)-size+1)
+	for i := 0; i+size <= len(xs); i++ {
+		windows = append(windows, xs[i:i+size:i+size])
+	}
+	return windows
+}
+
+// Zip pairs the elements of as and bs until the shorter one runs out.
+func Zip[A, B any](as []A, bs []B) []Pair[A, B] {
+	n := min(len(as), len(bs))
+	pairs := make([]Pair[A, B], n)
+	for i := range n {
+		pairs[i] = Pair[A, B]{as[i], bs[i]}
+	}
+	return pairs
+}
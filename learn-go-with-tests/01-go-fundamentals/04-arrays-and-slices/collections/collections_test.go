@@ -0,0 +1,138 @@
+package collections
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+	"testing/quick"
+)
+
+func isEven(n int) bool { return n%2 == 0 }
+
+func TestCollections(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5}
+
+	t.Run("Map", func(t *testing.T) {
+		assertEqual(t, Map(numbers, strconv.Itoa), []string{"1", "2", "3", "4", "5"})
+	})
+	t.Run("Filter", func(t *testing.T) {
+		assertEqual(t, Filter(numbers, isEven), []int{2, 4})
+	})
+	t.Run("Reduce", func(t *testing.T) {
+		got := Reduce(numbers, "", func(acc string, n int) string { return acc + strconv.Itoa(n) })
+		assertEqual(t, got, "12345")
+	})
+	t.Run("GroupBy", func(t *testing.T) {
+		got := GroupBy([]string{"apple", "avocado", "banana", "blueberry", "cherry"}, func(s string) byte { return s[0] })
+		assertEqual(t, got, map[byte][]string{'a': {"apple", "avocado"}, 'b': {"banana", "blueberry"}, 'c': {"cherry"}})
+	})
+	t.Run("Partition", func(t *testing.T) {
+		even, odd := Partition(numbers, isEven)
+		assertEqual(t, even, []int{2, 4})
+		assertEqual(t, odd, []int{1, 3, 5})
+	})
+	t.Run("Chunk", func(t *testing.T) {
+		assertEqual(t, Chunk(numbers, 2), [][]int{{1, 2}, {3, 4}, {5}})
+		assertEqual(t, Chunk([]int{}, 2), [][]int{})
+	})
+	t.Run("chunks do not overwrite each other", func(t *testing.T) {
+		chunks := Chunk([]int{1, 2, 3, 4}, 2)
+		chunks[0] = append(chunks[0], 99)
+		assertEqual(t, chunks[1], []int{3, 4})
+	})
+	t.Run("Window", func(t *testing.T) {
+		assertEqual(t, Window(numbers, 3), [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}})
+		assertEqual(t, Window(numbers, 6), [][]int(nil))
+	})
+	t.Run("Zip", func(t *testing.T) {
+		got := Zip(numbers, []string{"one", "two"})
+		assertEqual(t, got, []Pair[int, string]{{1, "one"}, {2, "two"}})
+	})
+	t.Run("sizes below 1 panic", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected a panic")
+			}
+		}()
+		Chunk(numbers, 0)
+	})
+}
+
+func TestPropertiesOfCollections(t *testing.T) {
+	properties := map[string]any{
+		"Map keeps the length and order": func(xs []int) bool {
+			return slices.Equal(Map(xs, func(x int) int { return x }), xs)
+		},
+		"Partition is Filter and its complement": func(xs []int) bool {
+			yes, no := Partition(xs, isEven)
+			odd := func(x int) bool { return !isEven(x) }
+			return slices.Equal(yes, Filter(xs, isEven)) && slices.Equal(no, Filter(xs, odd))
+		},
+		"Reduce with + is Sum": func(xs []int) bool {
+			return Reduce(xs, 0, func(a, b int) int { return a + b }) == Sum(xs)
+		},
+		"GroupBy loses no element": func(xs []int) bool {
+			total := 0
+			for k, group := range GroupBy(xs, func(x int) int { return x % 3 }) {
+				for _, x := range group {
+					if x%3 != k {
+						return false
+					}
+				}
+				total += len(group)
+			}
+			return total == len(xs)
+		},
+		"Chunk concatenates back to the input": func(xs []int, size uint8) bool {
+			n := int(size%16) + 1
+			chunks := Chunk(xs, n)
+			for i, c := range chunks {
+				if len(c) > n || (i < len(chunks)-1 && len(c) != n) {
+					return false
+				}
+			}
+			return slices.Equal(slices.Concat(chunks...), xs)
+		},
+		"Window slides by one": func(xs []int, size uint8) bool {
+			n := int(size%16) + 1
+			windows := Window(xs, n)
+			if len(windows) != max(0, len(xs)-n+1) {
+				return false
+			}
+			for i, w := range windows {
+				if !slices.Equal(w, xs[i:i+n]) {
+					return false
+				}
+			}
+			return true
+		},
+		"Zip stops at the shorter slice": func(as []int, bs []string) bool {
+			pairs := Zip(as, bs)
+			if len(pairs) != min(len(as), len(bs)) {
+				return false
+			}
+			for i, p := range pairs {
+				if p.First != as[i] || p.Second != bs[i] {
+					return false
+				}
+			}
+			return true
+		},
+	}
+
+	for name, property := range properties {
+		t.Run(name, func(t *testing.T) {
+			if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func assertEqual(t testing.TB, got, want any) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
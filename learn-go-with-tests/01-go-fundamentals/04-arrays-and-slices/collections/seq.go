@@ -0,0 +1,93 @@
+package collections
+
+import "iter"
+
+// The Seq variants are lazy: nothing runs until the result is ranged over,
+// and stopping the range stops the source.
+
+func MapSeq[T, U any](seq iter.Seq[T], f func(T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for x := range seq {
+			if !yield(f(x)) {
+				return
+			}
+		}
+	}
+}
+
+func FilterSeq[T any](seq iter.Seq[T], keep func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for x := range seq {
+			if keep(x) && !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+// ReduceSeq consumes seq, so it is not lazy.
+func ReduceSeq[T, U any](seq iter.Seq[T], initial U, f func(U, T) U) U {
+	acc := initial
+	for x := range seq {
+		acc = f(acc, x)
+	}
+	return acc
+}
+
+// ChunkSeq yields chunks of size elements, the last one possibly shorter.
+// Every chunk is a new slice. It panics if size < 1.
+func ChunkSeq[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	if size < 1 {
+		panic("collections: chunk size must be at least 1")
+	}
+	return func(yield func([]T) bool) {
+		chunk := make([]T, 0, size)
+		for x := range seq {
+			chunk = append(chunk, x)
+			if len(chunk) == size {
+				if !yield(chunk) {
+					return
+				}
+				chunk = make([]T, 0, size)
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// WindowSeq yields every run of size consecutive elements, sliding by one.
+// Every window is a new slice, so it keeps only size elements of an endless
+// seq in memory. It panics if size < 1.
+func WindowSeq[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	if size < 1 {
+		panic("collections: window size must be at least 1")
+	}
+	return func(yield func([]T) bool) {
+		window := make([]T, 0, size)
+		for x := range seq {
+			if len(window) == size {
+				window = append(window[:0:0], window[1:]...)
+			}
+			window = append(window, x)
+			if len(window) == size && !yield(window) {
+				return
+			}
+		}
+	}
+}
+
+// ZipSeq pairs the elements of as and bs until the shorter one runs out.
+func ZipSeq[A, B any](as iter.Seq[A], bs iter.Seq[B]) iter.Seq[Pair[A, B]] {
+	return func(yield func(Pair[A, B]) bool) {
+		nextB, stop := iter.Pull(bs)
+		defer stop()
+		for a := range as {
+			b, ok := nextB()
+			if !ok || !yield(Pair[A, B]{a, b}) {
+				return
+			}
+		}
+	}
+}
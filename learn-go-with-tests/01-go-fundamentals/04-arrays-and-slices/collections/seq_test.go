@@ -0,0 +1,102 @@
+package collections
+
+import (
+	"iter"
+	"slices"
+	"testing"
+	"testing/quick"
+)
+
+// naturals is endless, so only lazy code can use it.
+func naturals() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for n := 0; yield(n); n++ {
+		}
+	}
+}
+
+func take[T any](seq iter.Seq[T], n int) []T {
+	var out []T
+	for x := range seq {
+		if len(out) == n {
+			break
+		}
+		out = append(out, x)
+	}
+	return out
+}
+
+func TestSeq(t *testing.T) {
+	t.Run("works on endless sequences", func(t *testing.T) {
+		squares := MapSeq(FilterSeq(naturals(), isEven), func(n int) int { return n * n })
+		assertEqual(t, take(squares, 4), []int{0, 4, 16, 36})
+
+		assertEqual(t, take(ChunkSeq(naturals(), 2), 2), [][]int{{0, 1}, {2, 3}})
+		assertEqual(t, take(WindowSeq(naturals(), 3), 2), [][]int{{0, 1, 2}, {1, 2, 3}})
+
+		letters := slices.Values([]string{"a", "b"})
+		assertEqual(t, slices.Collect(ZipSeq(naturals(), letters)), []Pair[int, string]{{0, "a"}, {1, "b"}})
+	})
+
+	t.Run("is lazy", func(t *testing.T) {
+		calls := 0
+		seq := MapSeq(naturals(), func(n int) int {
+			calls++
+			return n
+		})
+		if calls != 0 {
+			t.Fatalf("MapSeq called f %d times before ranging", calls)
+		}
+
+		take(seq, 3)
+
+		// the 4th element is computed to find out it is not needed
+		if calls != 4 {
+			t.Errorf("got %d calls, want 4", calls)
+		}
+	})
+
+	t.Run("windows can be kept", func(t *testing.T) {
+		windows := slices.Collect(WindowSeq(slices.Values([]int{1, 2, 3, 4}), 2))
+		assertEqual(t, windows, [][]int{{1, 2}, {2, 3}, {3, 4}})
+	})
+}
+
+func TestPropertiesOfSeq(t *testing.T) {
+	properties := map[string]any{
+		"MapSeq is Map": func(xs []int) bool {
+			double := func(x int) int { return 2 * x }
+			return slices.Equal(slices.Collect(MapSeq(slices.Values(xs), double)), Map(xs, double))
+		},
+		"FilterSeq is Filter": func(xs []int) bool {
+			return slices.Equal(slices.Collect(FilterSeq(slices.Values(xs), isEven)), Filter(xs, isEven))
+		},
+		"ReduceSeq is Reduce": func(xs []int) bool {
+			add := func(a, b int) int { return a + b }
+			return ReduceSeq(slices.Values(xs), 0, add) == Reduce(xs, 0, add)
+		},
+		"ChunkSeq is Chunk": func(xs []int, size uint8) bool {
+			n := int(size%16) + 1
+			return equalSlices(slices.Collect(ChunkSeq(slices.Values(xs), n)), Chunk(xs, n))
+		},
+		"WindowSeq is Window": func(xs []int, size uint8) bool {
+			n := int(size%16) + 1
+			return equalSlices(slices.Collect(WindowSeq(slices.Values(xs), n)), Window(xs, n))
+		},
+		"ZipSeq is Zip": func(as, bs []int) bool {
+			return slices.Equal(slices.Collect(ZipSeq(slices.Values(as), slices.Values(bs))), Zip(as, bs))
+		},
+	}
+
+	for name, property := range properties {
+		t.Run(name, func(t *testing.T) {
+			if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func equalSlices(a, b [][]int) bool {
+	return slices.EqualFunc(a, b, slices.Equal[[]int])
+}
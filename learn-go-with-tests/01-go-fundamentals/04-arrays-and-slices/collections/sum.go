@@ -0,0 +1,80 @@
+package collections
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrOverflow = errors.New("collections: integer overflow")
+
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+type Float interface {
+	~float32 | ~float64
+}
+
+type Number interface {
+	Integer | Float
+}
+
+// Sum adds up xs. Integers wrap around on overflow and floats lose precision
+// like a plain loop does; see SumChecked and SumFloat.
+func Sum[T Number](xs []T) T {
+	var total T
+	for _, x := range xs {
+		total += x
+	}
+	return total
+}
+
+// SumAll returns the Sum of every slice.
+func SumAll[T Number](all ...[]T) []T {
+	return Map(all, Sum[T])
+}
+
+// SumAllTails returns the Sum of every slice but its first element.
+func SumAllTails[T Number](all ...[]T) []T {
+	return Map(all, func(xs []T) T {
+		if len(xs) == 0 {
+			return 0
+		}
+		return Sum(xs[1:])
+	})
+}
+
+// SumChecked adds up xs, failing with ErrOverflow instead of wrapping around.
+func SumChecked[T Integer](xs []T) (T, error) {
+	var total T
+	for i, x := range xs {
+		next := total + x
+		// adding a positive number must not decrease the total, and the
+		// other way around; x < 0 is false for unsigned types
+		if (x > 0 && next < total) || (x < 0 && next > total) {
+			return total, fmt.Errorf("%w: adding %v (element %d) to %v", ErrOverflow, x, i, total)
+		}
+		total = next
+	}
+	return total, nil
+}
+
+// SumFloat adds up xs with Kahan-Babuška (Neumaier) compensated summation,
+// keeping the low-order bits a plain loop drops: the sum of
+// []float64{1e100, 1, -1e100} is 1, not 0.
+func SumFloat[T Float](xs []T) T {
+	var sum, compensation float64
+	for _, x := range xs {
+		v := float64(x)
+		t := sum + v
+		if math.Abs(sum) >= math.Abs(v) {
+			compensation += (sum - t) + v
+		} else {
+			compensation += (v - t) + sum
+		}
+		sum = t
+	}
+	return T(sum + compensation)
+}
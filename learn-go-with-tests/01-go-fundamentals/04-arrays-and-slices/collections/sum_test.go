@@ -0,0 +1,111 @@
+package collections
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"testing/quick"
+)
+
+func TestSum(t *testing.T) {
+	assertEqual(t, Sum([]int{1, 2, 3}), 6)
+	assertEqual(t, Sum([]float32{0.5, 0.25}), float32(0.75))
+	assertEqual(t, SumAll([]int{1, 2}, []int{0, 9}), []int{3, 9})
+	assertEqual(t, SumAllTails([]int{}, []int{3, 4, 5}), []int{0, 9})
+}
+
+func TestSumChecked(t *testing.T) {
+	t.Run("sums", func(t *testing.T) {
+		got, err := SumChecked([]int8{100, 27, -120})
+		assertEqual(t, err, nil)
+		assertEqual(t, got, int8(7))
+	})
+
+	cases := map[string]func() error{
+		"signed overflow": func() error {
+			_, err := SumChecked([]int8{100, 28})
+			return err
+		},
+		"signed underflow": func() error {
+			_, err := SumChecked([]int64{math.MinInt64, -1})
+			return err
+		},
+		"unsigned overflow": func() error {
+			_, err := SumChecked([]uint8{200, 56})
+			return err
+		},
+	}
+	for name, sum := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := sum(); !errors.Is(err, ErrOverflow) {
+				t.Errorf("got %v want %v", err, ErrOverflow)
+			}
+		})
+	}
+
+	t.Run("fails exactly when a partial sum is out of range", func(t *testing.T) {
+		assertion := func(xs []int8) bool {
+			inRange := true
+			var exact int64
+			for _, x := range xs {
+				exact += int64(x)
+				if exact < math.MinInt8 || exact > math.MaxInt8 {
+					inRange = false
+					break
+				}
+			}
+			got, err := SumChecked(xs)
+			if inRange {
+				return err == nil && int64(got) == exact
+			}
+			return errors.Is(err, ErrOverflow)
+		}
+		if err := quick.Check(assertion, &quick.Config{MaxCount: 1000}); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestSumFloat(t *testing.T) {
+	t.Run("keeps the low-order bits", func(t *testing.T) {
+		xs := []float64{1e100, 1, -1e100}
+		assertEqual(t, Sum(xs), 0.0)
+		assertEqual(t, SumFloat(xs), 1.0)
+	})
+
+	t.Run("adds up ten million tenths", func(t *testing.T) {
+		xs := make([]float64, 10_000_000)
+		for i := range xs {
+			xs[i] = 0.1
+		}
+		if got := SumFloat(xs); got != 1e6 {
+			t.Errorf("got %v want %v", got, 1e6)
+		}
+	})
+
+	t.Run("is within the error bound of compensated summation", func(t *testing.T) {
+		// |error| <= eps|sum| + n eps² sum(|x|), with eps = 2^-53. float32
+		// inputs cannot overflow a float64 sum.
+		assertion := func(small []float32) bool {
+			xs := Map(small, func(x float32) float64 { return float64(x) })
+			exact, magnitude := newBig(), newBig()
+			for _, x := range xs {
+				exact.Add(exact, big.NewFloat(x))
+				magnitude.Add(magnitude, big.NewFloat(math.Abs(x)))
+			}
+			bound := newBig().Mul(newBig().Abs(exact), big.NewFloat(0x1p-53))
+			bound.Add(bound, magnitude.Mul(magnitude, big.NewFloat(float64(len(xs))*0x1p-106)))
+
+			distance := newBig().Sub(big.NewFloat(SumFloat(xs)), exact)
+			return distance.Abs(distance).Cmp(bound) <= 0
+		}
+		if err := quick.Check(assertion, &quick.Config{MaxCount: 1000}); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func newBig() *big.Float {
+	return new(big.Float).SetPrec(2048)
+}
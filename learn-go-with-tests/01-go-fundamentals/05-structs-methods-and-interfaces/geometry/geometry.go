@@ -0,0 +1,93 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+
+	shapes "tmp/learn-go-with-tests/01-go-fundamentals/05-structs-methods-and-interfaces"
+	clockface "tmp/learn-go-with-tests/01-go-fundamentals/16-math"
+)
+
+// Point uses the clockface convention: Cartesian coordinates with Y pointing up.
+// Renderers flip Y when converting to screen coordinates.
+type Point = clockface.Point
+
+// Shape is a shapes.Shape placed on the plane. Transformations return a new
+// Shape and never modify the receiver. Angles are in radians, counter-clockwise.
+type Shape interface {
+	shapes.Shape
+	Kind() string
+	Perimeter() float64
+	BoundingBox() Box
+	Contains(p Point) bool
+	Translate(dx, dy float64) Shape
+	Scale(about Point, factor float64) Shape
+	Rotate(about Point, angle float64) Shape
+}
+
+// Box is an axis-aligned bounding box.
+type Box struct {
+	Min Point `json:"min"`
+	Max Point `json:"max"`
+}
+
+func (b Box) Width() float64  { return b.Max.X - b.Min.X }
+func (b Box) Height() float64 { return b.Max.Y - b.Min.Y }
+
+// Union returns the smallest box containing both b and other.
+func (b Box) Union(other Box) Box {
+	return Box{
+		Point{X: math.Min(b.Min.X, other.Min.X), Y: math.Min(b.Min.Y, other.Min.Y)},
+		Point{X: math.Max(b.Max.X, other.Max.X), Y: math.Max(b.Max.Y, other.Max.Y)},
+	}
+}
+
+func boxOf(points []Point) Box {
+	if len(points) == 0 {
+		return Box{}
+	}
+	b := Box{points[0], points[0]}
+	for _, p := range points[1:] {
+		b = b.Union(Box{p, p})
+	}
+	return b
+}
+
+// FromShape places a shapes.Shape at the origin: rectangles and circles are centred
+// on it, and a Triangle becomes the isosceles triangle with its base on the X axis.
+func FromShape(s shapes.Shape) (Shape, error) {
+	switch s := s.(type) {
+	case shapes.Rectangle:
+		return Rectangle{Width: s.Width, Height: s.Height}, nil
+	case shapes.Circle:
+		return Circle{Radius: s.Radius}, nil
+	case shapes.Triangle:
+		return Triangle{
+			A: Point{X: -s.Base / 2},
+			B: Point{X: s.Base / 2},
+			C: Point{Y: s.Height},
+		}, nil
+	case Shape:
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unsupported shape %T", s)
+	}
+}
+
+func translate(p Point, dx, dy float64) Point {
+	return Point{X: p.X + dx, Y: p.Y + dy}
+}
+
+func scale(p, about Point, factor float64) Point {
+	return Point{X: about.X + (p.X-about.X)*factor, Y: about.Y + (p.Y-about.Y)*factor}
+}
+
+func rotate(p, about Point, angle float64) Point {
+	sin, cos := math.Sincos(angle)
+	x, y := p.X-about.X, p.Y-about.Y
+	return Point{X: about.X + x*cos - y*sin, Y: about.Y + x*sin + y*cos}
+}
+
+func distance(a, b Point) float64 {
+	return math.Hypot(b.X-a.X, b.Y-a.Y)
+}
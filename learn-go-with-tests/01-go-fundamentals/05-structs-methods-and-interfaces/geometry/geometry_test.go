@@ -0,0 +1,155 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	shapes "tmp/learn-go-with-tests/01-go-fundamentals/05-structs-methods-and-interfaces"
+)
+
+const tolerance = 1e-9
+
+var square = Polygon{[]Point{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}}}
+
+func TestMeasurements(t *testing.T) {
+	cases := []struct {
+		name         string
+		shape        Shape
+		hasArea      float64
+		hasPerimeter float64
+		hasBox       Box
+	}{
+		{"Circle", Circle{Point{X: 1, Y: 1}, 2}, 4 * math.Pi, 4 * math.Pi, Box{Point{X: -1, Y: -1}, Point{X: 3, Y: 3}}},
+		{"Ellipse", Ellipse{RX: 3, RY: 3}, 9 * math.Pi, 6 * math.Pi, Box{Point{X: -3, Y: -3}, Point{X: 3, Y: 3}}},
+		{"Flat ellipse", Ellipse{RX: 2, RY: 1}, 2 * math.Pi, 9.688448216130086, Box{Point{X: -2, Y: -1}, Point{X: 2, Y: 1}}},
+		{"Rectangle", Rectangle{Width: 4, Height: 2}, 8, 12, Box{Point{X: -2, Y: -1}, Point{X: 2, Y: 1}}},
+		{"Triangle", Triangle{Point{}, Point{X: 3}, Point{Y: 4}}, 6, 12, Box{Point{}, Point{X: 3, Y: 4}}},
+		{"Polygon", square, 4, 8, Box{Point{}, Point{X: 2, Y: 2}}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assertFloat(t, "area", tt.shape.Area(), tt.hasArea)
+			assertFloat(t, "perimeter", tt.shape.Perimeter(), tt.hasPerimeter)
+			assertBox(t, tt.shape.BoundingBox(), tt.hasBox)
+		})
+	}
+}
+
+func TestContains(t *testing.T) {
+	cases := []struct {
+		name    string
+		shape   Shape
+		inside  []Point
+		outside []Point
+	}{
+		{"Circle", Circle{Radius: 1}, []Point{{}, {X: 1}}, []Point{{X: 1, Y: 1}}},
+		{"Ellipse", Ellipse{RX: 2, RY: 1}, []Point{{X: 1.9}}, []Point{{Y: 1.1}}},
+		{"Rotated ellipse", Ellipse{RX: 2, RY: 1, Angle: math.Pi / 2}, []Point{{Y: 1.9}}, []Point{{X: 1.1}}},
+		{"Rectangle", Rectangle{Width: 4, Height: 2}, []Point{{X: 2, Y: 1}}, []Point{{X: 2.1}}},
+		{"Rotated rectangle", Rectangle{Width: 2, Height: 2, Angle: math.Pi / 4}, []Point{{X: 1.4}}, []Point{{X: 0.9, Y: 0.9}}},
+		{"Triangle", Triangle{Point{}, Point{X: 3}, Point{Y: 4}}, []Point{{X: 1, Y: 1}, {X: 1.5, Y: 2}}, []Point{{X: 2, Y: 2}}},
+		{"Concave polygon", Polygon{[]Point{{}, {X: 4}, {X: 4, Y: 4}, {X: 2, Y: 1}, {Y: 4}}}, []Point{{X: 1, Y: 1}, {X: 3.5, Y: 3}}, []Point{{X: 2, Y: 3}, {X: 3, Y: 3}}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, p := range tt.inside {
+				if !tt.shape.Contains(p) {
+					t.Errorf("%v should contain %v", tt.shape, p)
+				}
+			}
+			for _, p := range tt.outside {
+				if tt.shape.Contains(p) {
+					t.Errorf("%v should not contain %v", tt.shape, p)
+				}
+			}
+		})
+	}
+}
+
+func TestTransformations(t *testing.T) {
+	all := []Shape{
+		Circle{Point{X: 1, Y: 2}, 1},
+		Ellipse{Point{X: 1, Y: 2}, 2, 1, 0.3},
+		Rectangle{Point{X: 1, Y: 2}, 4, 2, 0.3},
+		Triangle{Point{}, Point{X: 3}, Point{Y: 4}},
+		square,
+	}
+	about := Point{X: -1, Y: 3}
+
+	for _, s := range all {
+		t.Run(s.Kind(), func(t *testing.T) {
+			moved := s.Translate(3, -1)
+			assertFloat(t, "translated area", moved.Area(), s.Area())
+			assertFloat(t, "translated min x", moved.BoundingBox().Min.X, s.BoundingBox().Min.X+3)
+
+			scaled := s.Scale(about, 2)
+			assertFloat(t, "scaled area", scaled.Area(), 4*s.Area())
+			assertFloat(t, "scaled perimeter", scaled.Perimeter(), 2*s.Perimeter())
+
+			rotated := s.Rotate(about, math.Pi/3)
+			assertFloat(t, "rotated area", rotated.Area(), s.Area())
+			assertFloat(t, "rotated perimeter", rotated.Perimeter(), s.Perimeter())
+			back := rotated.Rotate(about, -math.Pi/3)
+			assertBox(t, back.BoundingBox(), s.BoundingBox())
+		})
+	}
+
+	t.Run("rotating a square by a quarter turn", func(t *testing.T) {
+		got := square.Rotate(Point{}, math.Pi/2).BoundingBox()
+		assertBox(t, got, Box{Point{X: -2}, Point{Y: 2}})
+	})
+
+	t.Run("transformations do not modify the receiver", func(t *testing.T) {
+		p := Polygon{[]Point{{}, {X: 1}, {Y: 1}}}
+		p.Translate(5, 5)
+		if p.Points[0] != (Point{}) {
+			t.Errorf("receiver was modified: %v", p)
+		}
+	})
+}
+
+func TestFromShape(t *testing.T) {
+	cases := []struct {
+		name  string
+		shape shapes.Shape
+	}{
+		{"Rectangle", shapes.Rectangle{Width: 12, Height: 6}},
+		{"Circle", shapes.Circle{Radius: 10}},
+		{"Triangle", shapes.Triangle{Base: 12, Height: 6}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromShape(tt.shape)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertFloat(t, "area", got.Area(), tt.shape.Area())
+			if got.Perimeter() <= 0 {
+				t.Errorf("%v should have a perimeter", got)
+			}
+		})
+	}
+
+	t.Run("Triangle perimeter", func(t *testing.T) {
+		got, _ := FromShape(shapes.Triangle{Base: 6, Height: 4})
+		assertFloat(t, "perimeter", got.Perimeter(), 16)
+	})
+}
+
+func assertFloat(t testing.TB, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > tolerance {
+		t.Errorf("%s: got %g want %g", name, got, want)
+	}
+}
+
+func assertBox(t testing.TB, got, want Box) {
+	t.Helper()
+	assertFloat(t, "min x", got.Min.X, want.Min.X)
+	assertFloat(t, "min y", got.Min.Y, want.Min.Y)
+	assertFloat(t, "max x", got.Max.X, want.Max.X)
+	assertFloat(t, "max y", got.Max.Y, want.Max.Y)
+}
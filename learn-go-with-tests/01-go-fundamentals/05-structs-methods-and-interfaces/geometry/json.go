@@ -0,0 +1,73 @@
+package geometry
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Shapes is a heterogeneous list of shapes that (un)marshals as JSON objects
+// carrying their Kind in a "type" field, e.g. {"type":"circle","center":{"X":0,"Y":0},"radius":1}.
+type Shapes []Shape
+
+var decoders = map[string]func([]byte) (Shape, error){
+	Circle{}.Kind():    decode[Circle],
+	Ellipse{}.Kind():   decode[Ellipse],
+	Rectangle{}.Kind(): decode[Rectangle],
+	Triangle{}.Kind():  decode[Triangle],
+	Polygon{}.Kind():   decode[Polygon],
+}
+
+func decode[T Shape](data []byte) (Shape, error) {
+	var s T
+	err := json.Unmarshal(data, &s)
+	return s, err
+}
+
+func (s Shapes) MarshalJSON() ([]byte, error) {
+	out := make([]map[string]json.RawMessage, len(s))
+	for i, shape := range s {
+		if shape == nil {
+			return nil, fmt.Errorf("shape %d is nil", i)
+		}
+		data, err := json.Marshal(shape)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &out[i]); err != nil {
+			return nil, fmt.Errorf("shape %d (%s) must marshal to a JSON object: %v", i, shape.Kind(), err)
+		}
+		if out[i] == nil {
+			// a nil pointer marshals to null
+			return nil, fmt.Errorf("shape %d is nil", i)
+		}
+		out[i]["type"], _ = json.Marshal(shape.Kind())
+	}
+	return json.Marshal(out)
+}
+
+func (s *Shapes) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	shapes := make(Shapes, len(raw))
+	for i, r := range raw {
+		var discriminator struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(r, &discriminator); err != nil {
+			return fmt.Errorf("shape %d: %v", i, err)
+		}
+		decode, ok := decoders[discriminator.Type]
+		if !ok {
+			return fmt.Errorf("shape %d: unknown type %q", i, discriminator.Type)
+		}
+		shape, err := decode(r)
+		if err != nil {
+			return fmt.Errorf("shape %d (%s): %v", i, discriminator.Type, err)
+		}
+		shapes[i] = shape
+	}
+	*s = shapes
+	return nil
+}
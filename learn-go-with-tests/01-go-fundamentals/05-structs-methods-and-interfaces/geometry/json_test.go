@@ -0,0 +1,80 @@
+package geometry
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestShapesJSON(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		want := Shapes{
+			Circle{Point{X: 1, Y: 2}, 3},
+			Ellipse{Point{}, 2, 1, 0.5},
+			Rectangle{Point{X: 1}, 4, 2, 0},
+			Triangle{Point{}, Point{X: 3}, Point{Y: 4}},
+			square,
+		}
+
+		data, err := json.Marshal(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Shapes
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %#v, want %#v", got, want)
+		}
+	})
+
+	t.Run("type discriminator", func(t *testing.T) {
+		data, _ := json.Marshal(Shapes{Circle{Radius: 1}})
+		want := `[{"center":{"X":0,"Y":0},"radius":1,"type":"circle"}]`
+		if string(data) != want {
+			t.Errorf("got %s, want %s", data, want)
+		}
+	})
+
+	t.Run("inside a struct", func(t *testing.T) {
+		var drawing struct {
+			Name   string `json:"name"`
+			Shapes Shapes `json:"shapes"`
+		}
+		err := json.Unmarshal([]byte(`{"name": "d", "shapes": [{"type": "rectangle", "width": 2, "height": 1}]}`), &drawing)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := Shapes{Rectangle{Width: 2, Height: 1}}
+		if !reflect.DeepEqual(drawing.Shapes, want) {
+			t.Errorf("got %#v, want %#v", drawing.Shapes, want)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		cases := map[string]string{
+			`[{"type": "hexagon"}]`:               `unknown type "hexagon"`,
+			`[{"radius": 1}]`:                     `unknown type ""`,
+			`[{"type": "circle", "radius": "x"}]`: "shape 0 (circle)",
+			`{"type": "circle"}`:                  "cannot unmarshal object",
+		}
+		for input, want := range cases {
+			var got Shapes
+			err := json.Unmarshal([]byte(input), &got)
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: got %v, want an error containing %q", input, err, want)
+			}
+		}
+	})
+
+	t.Run("nil shapes", func(t *testing.T) {
+		for _, shapes := range []Shapes{{square, nil}, {square, (*Circle)(nil)}} {
+			_, err := json.Marshal(shapes)
+			if err == nil || !strings.Contains(err.Error(), "shape 1 is nil") {
+				t.Errorf("got %v, want an error containing %q", err, "shape 1 is nil")
+			}
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package geometry
+
+import "math"
+
+type Circle struct {
+	Center Point   `json:"center"`
+	Radius float64 `json:"radius"`
+}
+
+func (c Circle) Kind() string       { return "circle" }
+func (c Circle) Area() float64      { return math.Pi * c.Radius * c.Radius }
+func (c Circle) Perimeter() float64 { return 2 * math.Pi * c.Radius }
+
+func (c Circle) BoundingBox() Box {
+	return Box{translate(c.Center, -c.Radius, -c.Radius), translate(c.Center, c.Radius, c.Radius)}
+}
+
+func (c Circle) Contains(p Point) bool {
+	return distance(c.Center, p) <= c.Radius
+}
+
+func (c Circle) Translate(dx, dy float64) Shape {
+	return Circle{translate(c.Center, dx, dy), c.Radius}
+}
+
+func (c Circle) Scale(about Point, factor float64) Shape {
+	return Circle{scale(c.Center, about, factor), c.Radius * math.Abs(factor)}
+}
+
+func (c Circle) Rotate(about Point, angle float64) Shape {
+	return Circle{rotate(c.Center, about, angle), c.Radius}
+}
+
+// Ellipse has semi-axes RX and RY, rotated by Angle around its centre.
+type Ellipse struct {
+	Center Point   `json:"center"`
+	RX     float64 `json:"rx"`
+	RY     float64 `json:"ry"`
+	Angle  float64 `json:"angle"`
+}
+
+func (e Ellipse) Kind() string  { return "ellipse" }
+func (e Ellipse) Area() float64 { return math.Pi * e.RX * e.RY }
+
+// Perimeter uses Ramanujan's second approximation, exact for circles.
+func (e Ellipse) Perimeter() float64 {
+	a, b := e.RX, e.RY
+	if a+b == 0 {
+		return 0
+	}
+	h := (a - b) * (a - b) / ((a + b) * (a + b))
+	return math.Pi * (a + b) * (1 + 3*h/(10+math.Sqrt(4-3*h)))
+}
+
+func (e Ellipse) BoundingBox() Box {
+	sin, cos := math.Sincos(e.Angle)
+	w := math.Hypot(e.RX*cos, e.RY*sin)
+	h := math.Hypot(e.RX*sin, e.RY*cos)
+	return Box{translate(e.Center, -w, -h), translate(e.Center, w, h)}
+}
+
+func (e Ellipse) Contains(p Point) bool {
+	if e.RX == 0 || e.RY == 0 {
+		return false
+	}
+	local := rotate(p, e.Center, -e.Angle)
+	x, y := (local.X-e.Center.X)/e.RX, (local.Y-e.Center.Y)/e.RY
+	return x*x+y*y <= 1
+}
+
+func (e Ellipse) Translate(dx, dy float64) Shape {
+	e.Center = translate(e.Center, dx, dy)
+	return e
+}
+
+func (e Ellipse) Scale(about Point, factor float64) Shape {
+	e.Center = scale(e.Center, about, factor)
+	e.RX *= math.Abs(factor)
+	e.RY *= math.Abs(factor)
+	return e
+}
+
+func (e Ellipse) Rotate(about Point, angle float64) Shape {
+	e.Center = rotate(e.Center, about, angle)
+	e.Angle += angle
+	return e
+}
+
+// Rectangle is centred on Center and rotated by Angle around it.
+type Rectangle struct {
+	Center Point   `json:"center"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	Angle  float64 `json:"angle"`
+}
+
+func (r Rectangle) Kind() string       { return "rectangle" }
+func (r Rectangle) Area() float64      { return r.Width * r.Height }
+func (r Rectangle) Perimeter() float64 { return 2 * (r.Width + r.Height) }
+
+// Corners returns the vertices counter-clockwise, starting bottom left before rotation.
+func (r Rectangle) Corners() []Point {
+	w, h := r.Width/2, r.Height/2
+	corners := []Point{
+		translate(r.Center, -w, -h),
+		translate(r.Center, w, -h),
+		translate(r.Center, w, h),
+		translate(r.Center, -w, h),
+	}
+	for i, c := range corners {
+		corners[i] = rotate(c, r.Center, r.Angle)
+	}
+	return corners
+}
+
+func (r Rectangle) BoundingBox() Box {
+	return boxOf(r.Corners())
+}
+
+func (r Rectangle) Contains(p Point) bool {
+	local := rotate(p, r.Center, -r.Angle)
+	return math.Abs(local.X-r.Center.X) <= r.Width/2 && math.Abs(local.Y-r.Center.Y) <= r.Height/2
+}
+
+func (r Rectangle) Translate(dx, dy float64) Shape {
+	r.Center = translate(r.Center, dx, dy)
+	return r
+}
+
+func (r Rectangle) Scale(about Point, factor float64) Shape {
+	r.Center = scale(r.Center, about, factor)
+	r.Width *= math.Abs(factor)
+	r.Height *= math.Abs(factor)
+	return r
+}
+
+func (r Rectangle) Rotate(about Point, angle float64) Shape {
+	r.Center = rotate(r.Center, about, angle)
+	r.Angle += angle
+	return r
+}
+
+type Triangle struct {
+	A Point `json:"a"`
+	B Point `json:"b"`
+	C Point `json:"c"`
+}
+
+func (t Triangle) polygon() Polygon               { return Polygon{[]Point{t.A, t.B, t.C}} }
+func (t Triangle) Kind() string                   { return "triangle" }
+func (t Triangle) Area() float64                  { return t.polygon().Area() }
+func (t Triangle) Perimeter() float64             { return t.polygon().Perimeter() }
+func (t Triangle) BoundingBox() Box               { return t.polygon().BoundingBox() }
+func (t Triangle) Contains(p Point) bool          { return t.polygon().Contains(p) }
+func (t Triangle) Translate(dx, dy float64) Shape { return triangle(t.polygon().Translate(dx, dy)) }
+
+func (t Triangle) Scale(about Point, factor float64) Shape {
+	return triangle(t.polygon().Scale(about, factor))
+}
+
+func (t Triangle) Rotate(about Point, angle float64) Shape {
+	return triangle(t.polygon().Rotate(about, angle))
+}
+
+func triangle(s Shape) Triangle {
+	p := s.(Polygon).Points
+	return Triangle{p[0], p[1], p[2]}
+}
+
+// Polygon is a simple (non self-intersecting) polygon. The last point connects back to the first.
+type Polygon struct {
+	Points []Point `json:"points"`
+}
+
+func (p Polygon) Kind() string { return "polygon" }
+
+// Area uses the shoelace formula.
+func (p Polygon) Area() float64 {
+	var sum float64
+	for i, a := range p.Points {
+		b := p.Points[(i+1)%len(p.Points)]
+		sum += a.X*b.Y - b.X*a.Y
+	}
+	return math.Abs(sum) / 2
+}
+
+func (p Polygon) Perimeter() float64 {
+	var sum float64
+	for i, a := range p.Points {
+		sum += distance(a, p.Points[(i+1)%len(p.Points)])
+	}
+	return sum
+}
+
+func (p Polygon) BoundingBox() Box {
+	return boxOf(p.Points)
+}
+
+// Contains uses ray casting; points on an edge count as inside.
+func (p Polygon) Contains(pt Point) bool {
+	inside := false
+	for i, a := range p.Points {
+		b := p.Points[(i+1)%len(p.Points)]
+		if onSegment(pt, a, b) {
+			return true
+		}
+		if (a.Y > pt.Y) != (b.Y > pt.Y) && pt.X < (b.X-a.X)*(pt.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func (p Polygon) Translate(dx, dy float64) Shape {
+	return p.mapPoints(func(pt Point) Point { return translate(pt, dx, dy) })
+}
+
+func (p Polygon) Scale(about Point, factor float64) Shape {
+	return p.mapPoints(func(pt Point) Point { return scale(pt, about, factor) })
+}
+
+func (p Polygon) Rotate(about Point, angle float64) Shape {
+	return p.mapPoints(func(pt Point) Point { return rotate(pt, about, angle) })
+}
+
+func (p Polygon) mapPoints(f func(Point) Point) Polygon {
+	points := make([]Point, len(p.Points))
+	for i, pt := range p.Points {
+		points[i] = f(pt)
+	}
+	return Polygon{points}
+}
+
+func onSegment(p, a, b Point) bool {
+	const epsilon = 1e-9
+	cross := (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+	if math.Abs(cross) > epsilon {
+		return false
+	}
+	return math.Min(a.X, b.X)-epsilon <= p.X && p.X <= math.Max(a.X, b.X)+epsilon &&
+		math.Min(a.Y, b.Y)-epsilon <= p.Y && p.Y <= math.Max(a.Y, b.Y)+epsilon
+}
@@ -0,0 +1,86 @@
+package geometry
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+const svgMargin = 10
+
+// SVGWriter writes an SVG document showing shapes to w. Like clockface, shape
+// coordinates have Y pointing up, so every point is flipped and translated into
+// the SVG viewport, where Y points down.
+func SVGWriter(w io.Writer, shapes []Shape) error {
+	var box Box
+	for i, s := range shapes {
+		if i == 0 {
+			box = s.BoundingBox()
+			continue
+		}
+		box = box.Union(s.BoundingBox())
+	}
+	toSVG := func(p Point) Point {
+		p = Point{X: p.X, Y: -p.Y}                                                   // flip
+		return Point{X: p.X - box.Min.X + svgMargin, Y: p.Y + box.Max.Y + svgMargin} // translate
+	}
+
+	if _, err := fmt.Fprintf(w, svgStart, box.Width()+2*svgMargin, box.Height()+2*svgMargin); err != nil {
+		return err
+	}
+	for _, s := range shapes {
+		if _, err := io.WriteString(w, svgElement(s, toSVG)); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, svgEnd)
+	return err
+}
+
+func svgElement(s Shape, toSVG func(Point) Point) string {
+	switch s := s.(type) {
+	case Circle:
+		c := toSVG(s.Center)
+		return fmt.Sprintf(`<circle cx="%.3f" cy="%.3f" r="%.3f" style="%s"/>`, c.X, c.Y, s.Radius, svgStyle)
+	case Ellipse:
+		c := toSVG(s.Center)
+		// the flip turns counter-clockwise rotations into clockwise ones
+		deg := -s.Angle * 180 / math.Pi
+		return fmt.Sprintf(`<ellipse cx="%.3f" cy="%.3f" rx="%.3f" ry="%.3f" transform="rotate(%.3f %.3f %.3f)" style="%s"/>`,
+			c.X, c.Y, s.RX, s.RY, deg, c.X, c.Y, svgStyle)
+	case Rectangle:
+		return svgPolygon(s.Corners(), toSVG)
+	case Triangle:
+		return svgPolygon(s.polygon().Points, toSVG)
+	case Polygon:
+		return svgPolygon(s.Points, toSVG)
+	default:
+		return svgPolygon(s.BoundingBox().corners(), toSVG)
+	}
+}
+
+func svgPolygon(points []Point, toSVG func(Point) Point) string {
+	coords := make([]string, len(points))
+	for i, p := range points {
+		p = toSVG(p)
+		coords[i] = fmt.Sprintf("%.3f,%.3f", p.X, p.Y)
+	}
+	return fmt.Sprintf(`<polygon points="%s" style="%s"/>`, strings.Join(coords, " "), svgStyle)
+}
+
+func (b Box) corners() []Point {
+	return []Point{b.Min, {X: b.Max.X, Y: b.Min.Y}, b.Max, {X: b.Min.X, Y: b.Max.Y}}
+}
+
+const svgStyle = "fill:none;stroke:#000;stroke-width:1px;"
+
+const svgStart = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<!DOCTYPE svg PUBLIC "-//W3C//DTD SVG 1.1//EN" "http://www.w3.org/Graphics/SVG/1.1/DTD/svg11.dtd">
+<svg xmlns="http://www.w3.org/2000/svg"
+     width="100%%"
+     height="100%%"
+     viewBox="0 0 %.3f %.3f"
+     version="2.0">`
+
+const svgEnd = `</svg>`
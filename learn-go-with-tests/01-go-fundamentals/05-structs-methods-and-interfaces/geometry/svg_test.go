@@ -0,0 +1,68 @@
+package geometry
+
+import (
+	"bytes"
+	"encoding/xml"
+	"math"
+	"testing"
+)
+
+type SVG struct {
+	XMLName  xml.Name  `xml:"svg"`
+	ViewBox  string    `xml:"viewBox,attr"`
+	Circle   []SVGCirc `xml:"circle"`
+	Polygons []SVGPoly `xml:"polygon"`
+	Ellipses []SVGEll  `xml:"ellipse"`
+}
+
+type SVGCirc struct {
+	Cx float64 `xml:"cx,attr"`
+	Cy float64 `xml:"cy,attr"`
+	R  float64 `xml:"r,attr"`
+}
+
+type SVGPoly struct {
+	Points string `xml:"points,attr"`
+}
+
+type SVGEll struct {
+	Transform string `xml:"transform,attr"`
+}
+
+func TestSVGWriter(t *testing.T) {
+	b := bytes.Buffer{}
+	err := SVGWriter(&b, []Shape{
+		Circle{Point{X: 0, Y: 10}, 5},
+		Triangle{Point{X: 0, Y: 0}, Point{X: 10, Y: 0}, Point{X: 0, Y: 10}},
+		Ellipse{Point{}, 2, 1, math.Pi / 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svg := SVG{}
+	if err := xml.Unmarshal(b.Bytes(), &svg); err != nil {
+		t.Fatalf("invalid svg %q: %v", b.String(), err)
+	}
+
+	// the shapes span x in [-5, 10] and y in [-2, 15], plus the margin
+	if svg.ViewBox != "0 0 35.000 37.000" {
+		t.Errorf("got viewBox %q", svg.ViewBox)
+	}
+
+	// Y is flipped: the top of the drawing (y=15) is at the top of the SVG (margin)
+	want := SVGCirc{Cx: 0 + 5 + svgMargin, Cy: 15 - 10 + svgMargin, R: 5}
+	if len(svg.Circle) != 1 || svg.Circle[0] != want {
+		t.Errorf("got circles %+v, want %+v", svg.Circle, want)
+	}
+
+	wantPoints := "15.000,25.000 25.000,25.000 15.000,15.000"
+	if len(svg.Polygons) != 1 || svg.Polygons[0].Points != wantPoints {
+		t.Errorf("got polygons %+v, want points %q", svg.Polygons, wantPoints)
+	}
+
+	wantTransform := "rotate(-90.000 15.000 25.000)"
+	if len(svg.Ellipses) != 1 || svg.Ellipses[0].Transform != wantTransform {
+		t.Errorf("got ellipses %+v, want transform %q", svg.Ellipses, wantTransform)
+	}
+}
@@ -0,0 +1,56 @@
+package ledger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Satoshi is a fixed-point bitcoin amount: 1 BTC = 100,000,000 satoshis.
+type Satoshi int64
+
+const SatoshiPerBitcoin Satoshi = 100_000_000
+
+const satoshiDigits = 8
+
+// String formats s as a decimal bitcoin amount, e.g. "1.50000000 BTC".
+func (s Satoshi) String() string {
+	sign := ""
+	u := uint64(s)
+	if s < 0 {
+		sign = "-"
+		u = -u
+	}
+	per := uint64(SatoshiPerBitcoin)
+	return fmt.Sprintf("%s%d.%08d BTC", sign, u/per, u%per)
+}
+
+// ParseAmount parses a decimal bitcoin amount such as "1.5", "-0.25" or "0.00000001 BTC".
+func ParseAmount(s string) (Satoshi, error) {
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "BTC"))
+	digits, negative := strings.CutPrefix(s, "-")
+	whole, frac, _ := strings.Cut(digits, ".")
+	if whole == "" && frac == "" || !isDigits(whole) || !isDigits(frac) || len(frac) > satoshiDigits {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	frac += strings.Repeat("0", satoshiDigits-len(frac))
+
+	u, err := strconv.ParseUint("0"+whole+frac, 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q overflows", ErrInvalidAmount, s)
+	}
+	amount := Satoshi(u)
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
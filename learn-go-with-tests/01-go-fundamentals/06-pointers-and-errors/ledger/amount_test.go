@@ -0,0 +1,62 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSatoshiString(t *testing.T) {
+	cases := []struct {
+		amount Satoshi
+		want   string
+	}{
+		{0, "0.00000000 BTC"},
+		{1, "0.00000001 BTC"},
+		{150_000_000, "1.50000000 BTC"},
+		{-25_000_000, "-0.25000000 BTC"},
+		{-9223372036854775808, "-92233720368.54775808 BTC"},
+	}
+	for _, test := range cases {
+		t.Run(test.want, func(t *testing.T) {
+			if got := test.amount.String(); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	cases := []struct {
+		input string
+		want  Satoshi
+	}{
+		{"1", SatoshiPerBitcoin},
+		{"1.5", 150_000_000},
+		{".5", 50_000_000},
+		{"0.00000001", 1},
+		{"-0.25", -25_000_000},
+		{"2.10000000 BTC", 210_000_000},
+	}
+	for _, test := range cases {
+		t.Run(test.input, func(t *testing.T) {
+			got, err := ParseAmount(test.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got %d, want %d", got, test.want)
+			}
+			if back, _ := ParseAmount(got.String()); back != got {
+				t.Errorf("%s does not round trip, got %d", got, back)
+			}
+		})
+	}
+
+	for _, input := range []string{"", ".", "abc", "1.000000001", "1e5", "+1", "1.-5", "100000000000"} {
+		t.Run("invalid "+input, func(t *testing.T) {
+			if _, err := ParseAmount(input); !errors.Is(err, ErrInvalidAmount) {
+				t.Errorf("got %v, want %v", err, ErrInvalidAmount)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package ledger
+
+import "time"
+
+// Query filters History. Zero values match everything.
+type Query struct {
+	Account string    // transactions with at least one leg touching the account
+	Since   time.Time // inclusive
+	Until   time.Time // exclusive
+	AfterID int       // for paging: only transactions with a larger ID
+	Limit   int
+}
+
+// Entry is one side of a leg from the point of view of a single account:
+// negative for a debit, positive for a credit.
+type Entry struct {
+	TransactionID int
+	Account       string
+	Amount        Satoshi
+}
+
+// History returns the matching transactions, oldest first.
+func (l *Ledger) History(q Query) []Transaction {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var txs []Transaction
+	for _, tx := range l.history {
+		if tx.ID <= q.AfterID || !q.Since.IsZero() && tx.Time.Before(q.Since) || !q.Until.IsZero() && !tx.Time.Before(q.Until) {
+			continue
+		}
+		if q.Account != "" && !touches(tx, q.Account) {
+			continue
+		}
+		txs = append(txs, copyTransaction(tx))
+		if q.Limit > 0 && len(txs) == q.Limit {
+			break
+		}
+	}
+	return txs
+}
+
+// Entries returns the double-entry postings of tx: one debit and one credit per leg.
+// They always sum to zero.
+func (tx Transaction) Entries() []Entry {
+	entries := make([]Entry, 0, 2*len(tx.Legs))
+	for _, leg := range tx.Legs {
+		entries = append(entries,
+			Entry{tx.ID, leg.From, -leg.Amount},
+			Entry{tx.ID, leg.To, leg.Amount},
+		)
+	}
+	return entries
+}
+
+func touches(tx Transaction, account string) bool {
+	for _, leg := range tx.Legs {
+		if leg.From == account || leg.To == account {
+			return true
+		}
+	}
+	return false
+}
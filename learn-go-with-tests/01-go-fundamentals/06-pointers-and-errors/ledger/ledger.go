@@ -0,0 +1,176 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	wallet "tmp/learn-go-with-tests/01-go-fundamentals/06-pointers-and-errors"
+)
+
+// External is the counterparty of money entering or leaving the ledger.
+// It has no wallet and its balance may go negative.
+const External = "@external"
+
+var (
+	ErrInvalidAmount       = errors.New("amount must be positive")
+	ErrUnknownAccount      = errors.New("unknown account")
+	ErrAccountExists       = errors.New("account already exists")
+	ErrSameAccount         = errors.New("cannot transfer to the same account")
+	ErrEmptyTransaction    = errors.New("transaction has no legs")
+	ErrIdempotencyConflict = errors.New("idempotency key was already used for a different transaction")
+)
+
+// Leg moves Amount from one account to another. Every leg is recorded as a
+// debit of From and a credit of To, so the ledger always balances.
+type Leg struct {
+	From   string
+	To     string
+	Amount Satoshi
+}
+
+type Transaction struct {
+	ID   int
+	Key  string
+	Legs []Leg
+	Memo string
+	Time time.Time
+}
+
+// Ledger holds one wallet.Wallet per account. Balances are kept in satoshis,
+// i.e. a wallet.Bitcoin of 1 is one satoshi. It is safe for concurrent use.
+type Ledger struct {
+	mu       sync.RWMutex
+	accounts map[string]*wallet.Wallet
+	history  []Transaction
+	keys     map[string]int // idempotency key -> index in history
+	now      func() time.Time
+}
+
+func New() *Ledger {
+	return &Ledger{
+		accounts: map[string]*wallet.Wallet{},
+		keys:     map[string]int{},
+		now:      time.Now,
+	}
+}
+
+func (l *Ledger) Open(account string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if account == External {
+		return fmt.Errorf("%w: %s", ErrAccountExists, account)
+	}
+	if _, ok := l.accounts[account]; ok {
+		return fmt.Errorf("%w: %s", ErrAccountExists, account)
+	}
+	l.accounts[account] = &wallet.Wallet{}
+	return nil
+}
+
+func (l *Ledger) Balance(account string) (Satoshi, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	w, ok := l.accounts[account]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownAccount, account)
+	}
+	return Satoshi(w.Balance()), nil
+}
+
+// Deposit credits account from External.
+func (l *Ledger) Deposit(key, account string, amount Satoshi) (Transaction, error) {
+	return l.Apply(key, "deposit", Leg{External, account, amount})
+}
+
+// Withdraw debits account to External. It fails with wallet.ErrInsufficientFunds
+// when the balance is too low.
+func (l *Ledger) Withdraw(key, account string, amount Satoshi) (Transaction, error) {
+	return l.Apply(key, "withdraw", Leg{account, External, amount})
+}
+
+func (l *Ledger) Transfer(key, from, to string, amount Satoshi) (Transaction, error) {
+	return l.Apply(key, "transfer", Leg{from, to, amount})
+}
+
+// Apply runs every leg or none of them. A non-empty key makes the call idempotent:
+// repeating it returns the original transaction without moving money again,
+// while reusing the key for different legs fails with ErrIdempotencyConflict.
+// Failed transactions do not consume their key.
+func (l *Ledger) Apply(key, memo string, legs ...Leg) (Transaction, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if i, ok := l.keys[key]; ok && key != "" {
+		tx := l.history[i]
+		if !reflect.DeepEqual(tx.Legs, legs) {
+			return Transaction{}, fmt.Errorf("%w: %s", ErrIdempotencyConflict, key)
+		}
+		return copyTransaction(tx), nil
+	}
+	if err := l.validate(legs); err != nil {
+		return Transaction{}, err
+	}
+
+	for i, leg := range legs {
+		if err := l.move(leg); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				l.move(Leg{legs[j].To, legs[j].From, legs[j].Amount})
+			}
+			return Transaction{}, fmt.Errorf("leg %d (%s -> %s, %s): %w", i, leg.From, leg.To, leg.Amount, err)
+		}
+	}
+
+	tx := Transaction{
+		ID:   len(l.history) + 1,
+		Key:  key,
+		Legs: append([]Leg(nil), legs...),
+		Memo: memo,
+		Time: l.now(),
+	}
+	l.history = append(l.history, tx)
+	if key != "" {
+		l.keys[key] = len(l.history) - 1
+	}
+	return copyTransaction(tx), nil
+}
+
+func (l *Ledger) validate(legs []Leg) error {
+	if len(legs) == 0 {
+		return ErrEmptyTransaction
+	}
+	for i, leg := range legs {
+		if leg.Amount <= 0 {
+			return fmt.Errorf("leg %d: %w, got %s", i, ErrInvalidAmount, leg.Amount)
+		}
+		if leg.From == leg.To {
+			return fmt.Errorf("leg %d: %w", i, ErrSameAccount)
+		}
+		for _, account := range []string{leg.From, leg.To} {
+			if _, ok := l.accounts[account]; !ok && account != External {
+				return fmt.Errorf("leg %d: %w: %s", i, ErrUnknownAccount, account)
+			}
+		}
+	}
+	return nil
+}
+
+// move is called with the lock held and validated accounts.
+func (l *Ledger) move(leg Leg) error {
+	if leg.From != External {
+		if err := l.accounts[leg.From].Withdraw(wallet.Bitcoin(leg.Amount)); err != nil {
+			return err
+		}
+	}
+	if leg.To != External {
+		l.accounts[leg.To].Deposit(wallet.Bitcoin(leg.Amount))
+	}
+	return nil
+}
+
+func copyTransaction(tx Transaction) Transaction {
+	tx.Legs = append([]Leg(nil), tx.Legs...)
+	return tx
+}
@@ -0,0 +1,254 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	wallet "tmp/learn-go-with-tests/01-go-fundamentals/06-pointers-and-errors"
+)
+
+func TestTransfer(t *testing.T) {
+	t.Run("moves money between wallets", func(t *testing.T) {
+		l := newLedger(t, "alice", "bob")
+		l.Deposit("", "alice", 100)
+
+		_, err := l.Transfer("", "alice", "bob", 30)
+
+		assertNoError(t, err)
+		assertBalance(t, l, "alice", 70)
+		assertBalance(t, l, "bob", 30)
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		l := newLedger(t, "alice", "bob")
+		l.Deposit("", "alice", 10)
+
+		_, err := l.Transfer("", "alice", "bob", 30)
+
+		assertError(t, err, wallet.ErrInsufficientFunds)
+		assertBalance(t, l, "alice", 10)
+		assertBalance(t, l, "bob", 0)
+	})
+
+	t.Run("invalid transfers", func(t *testing.T) {
+		l := newLedger(t, "alice", "bob")
+		l.Deposit("", "alice", 10)
+
+		_, err := l.Transfer("", "alice", "bob", -5)
+		assertError(t, err, ErrInvalidAmount)
+		_, err = l.Transfer("", "alice", "bob", 0)
+		assertError(t, err, ErrInvalidAmount)
+		_, err = l.Transfer("", "alice", "carol", 5)
+		assertError(t, err, ErrUnknownAccount)
+		_, err = l.Transfer("", "alice", "alice", 5)
+		assertError(t, err, ErrSameAccount)
+		_, err = l.Apply("", "nothing")
+		assertError(t, err, ErrEmptyTransaction)
+
+		assertBalance(t, l, "alice", 10)
+		if got := len(l.History(Query{})); got != 1 {
+			t.Errorf("failed transfers must not be recorded, got %d transactions", got)
+		}
+	})
+
+	t.Run("withdraw to external", func(t *testing.T) {
+		l := newLedger(t, "alice")
+		l.Deposit("", "alice", 10)
+
+		_, err := l.Withdraw("", "alice", 11)
+		assertError(t, err, wallet.ErrInsufficientFunds)
+		_, err = l.Withdraw("", "alice", 10)
+		assertNoError(t, err)
+		assertBalance(t, l, "alice", 0)
+	})
+}
+
+func TestAccounts(t *testing.T) {
+	l := newLedger(t, "alice")
+	assertError(t, l.Open("alice"), ErrAccountExists)
+	assertError(t, l.Open(External), ErrAccountExists)
+	_, err := l.Balance("bob")
+	assertError(t, err, ErrUnknownAccount)
+}
+
+func TestApplyIsAtomic(t *testing.T) {
+	l := newLedger(t, "alice", "bob", "carol")
+	l.Deposit("", "alice", 100)
+
+	_, err := l.Apply("", "split",
+		Leg{"alice", "bob", 60},
+		Leg{"alice", "carol", 60}, // alice only has 40 left
+	)
+
+	assertError(t, err, wallet.ErrInsufficientFunds)
+	assertBalance(t, l, "alice", 100)
+	assertBalance(t, l, "bob", 0)
+	assertBalance(t, l, "carol", 0)
+
+	_, err = l.Apply("", "split",
+		Leg{"alice", "bob", 60},
+		Leg{"bob", "carol", 20},
+		Leg{"alice", "carol", 40},
+	)
+	assertNoError(t, err)
+	assertBalance(t, l, "alice", 0)
+	assertBalance(t, l, "bob", 40)
+	assertBalance(t, l, "carol", 60)
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	l := newLedger(t, "alice", "bob")
+	l.Deposit("", "alice", 100)
+
+	first, err := l.Transfer("payment-1", "alice", "bob", 30)
+	assertNoError(t, err)
+	again, err := l.Transfer("payment-1", "alice", "bob", 30)
+	assertNoError(t, err)
+
+	if again.ID != first.ID {
+		t.Errorf("got transaction %d, want the original %d", again.ID, first.ID)
+	}
+	assertBalance(t, l, "alice", 70)
+	assertBalance(t, l, "bob", 30)
+
+	_, err = l.Transfer("payment-1", "alice", "bob", 50)
+	assertError(t, err, ErrIdempotencyConflict)
+
+	t.Run("failed transactions do not consume the key", func(t *testing.T) {
+		_, err := l.Transfer("payment-2", "bob", "alice", 1000)
+		assertError(t, err, wallet.ErrInsufficientFunds)
+		_, err = l.Transfer("payment-2", "bob", "alice", 10)
+		assertNoError(t, err)
+		assertBalance(t, l, "bob", 20)
+	})
+}
+
+func TestHistory(t *testing.T) {
+	l := newLedger(t, "alice", "bob", "carol")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	l.now = func() time.Time {
+		now = now.Add(time.Hour)
+		return now
+	}
+
+	l.Deposit("", "alice", 100)          // 1: 01:00
+	l.Transfer("", "alice", "bob", 10)   // 2: 02:00
+	l.Transfer("", "alice", "carol", 10) // 3: 03:00
+	l.Transfer("", "bob", "carol", 5)    // 4: 04:00
+
+	cases := []struct {
+		Name  string
+		Query Query
+		Want  []int
+	}{
+		{"everything", Query{}, []int{1, 2, 3, 4}},
+		{"by account", Query{Account: "bob"}, []int{2, 4}},
+		{"external account", Query{Account: External}, []int{1}},
+		{"time range", Query{Since: start.Add(2 * time.Hour), Until: start.Add(4 * time.Hour)}, []int{2, 3}},
+		{"paging", Query{AfterID: 1, Limit: 2}, []int{2, 3}},
+	}
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			var got []int
+			for _, tx := range l.History(test.Query) {
+				got = append(got, tx.ID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(test.Want) {
+				t.Errorf("got %v, want %v", got, test.Want)
+			}
+		})
+	}
+
+	t.Run("entries balance", func(t *testing.T) {
+		for _, tx := range l.History(Query{}) {
+			var sum Satoshi
+			for _, e := range tx.Entries() {
+				sum += e.Amount
+			}
+			if sum != 0 {
+				t.Errorf("transaction %d entries sum to %s", tx.ID, sum)
+			}
+		}
+	})
+
+	t.Run("history cannot be modified by callers", func(t *testing.T) {
+		l.History(Query{})[0].Legs[0].Amount = 1
+		if got := l.History(Query{})[0].Legs[0].Amount; got != 100 {
+			t.Errorf("got %d, want 100", got)
+		}
+	})
+}
+
+func TestConcurrentTransfers(t *testing.T) {
+	accounts := []string{"a", "b", "c", "d"}
+	l := newLedger(t, accounts...)
+	for _, a := range accounts {
+		l.Deposit("", a, 1000)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 400; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			from, to := accounts[i%4], accounts[(i+1)%4]
+			key := fmt.Sprintf("tx-%d", i%200) // every key is used twice
+			l.Transfer(key, from, to, Satoshi(i%7+1))
+			l.Balance(from)
+			l.History(Query{Account: to, Limit: 5})
+		}(i)
+	}
+	wg.Wait()
+
+	var total Satoshi
+	for _, a := range accounts {
+		b, _ := l.Balance(a)
+		total += b
+	}
+	if total != 4000 {
+		t.Errorf("money was created or destroyed: total %s", total)
+	}
+	if got := len(l.History(Query{})); got != 4+200 {
+		t.Errorf("got %d transactions, want %d", got, 4+200)
+	}
+}
+
+func newLedger(t testing.TB, accounts ...string) *Ledger {
+	t.Helper()
+	l := New()
+	for _, a := range accounts {
+		if err := l.Open(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return l
+}
+
+func assertBalance(t testing.TB, l *Ledger, account string, want Satoshi) {
+	t.Helper()
+	got, err := l.Balance(account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("%s: got %s want %s", account, got, want)
+	}
+}
+
+func assertNoError(t testing.TB, got error) {
+	t.Helper()
+	if got != nil {
+		t.Fatal("got an error but didn't want one", got)
+	}
+}
+
+func assertError(t testing.TB, got, want error) {
+	t.Helper()
+	if !errors.Is(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"tmp/learn-go-with-tests/01-go-fundamentals/07-maps/dictionary"
+)
+
+const dbFileName = "dictionary.db.json"
+
+func main() {
+	d, err := dictionary.DictionaryFromFile(dbFileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Fatal(http.ListenAndServe(":5000", dictionary.NewDictionaryServer(d)))
+}
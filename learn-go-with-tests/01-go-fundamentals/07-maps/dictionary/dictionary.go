@@ -0,0 +1,228 @@
+package dictionary
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type DictionaryErr string
+
+var (
+	ErrNotFound         = DictionaryErr("could not find the word you were looking for")
+	ErrWordExists       = DictionaryErr("cannot add word because it already exists")
+	ErrWordDoesNotExist = DictionaryErr("word doesn't not exist")
+	ErrEmptyWord        = DictionaryErr("word must not be empty")
+)
+
+func (e DictionaryErr) Error() string {
+	return string(e)
+}
+
+type Entry struct {
+	Word       string `json:"word"`
+	Definition string `json:"definition"`
+}
+
+type Match struct {
+	Entry
+	Distance int `json:"distance"`
+}
+
+// Dictionary is safe for concurrent use. When created with DictionaryFromFile
+// every change is written to the file before the method returns.
+type Dictionary struct {
+	mu    sync.RWMutex
+	words map[string]string
+	save  func() error
+}
+
+func New() *Dictionary {
+	return &Dictionary{words: map[string]string{}}
+}
+
+func (d *Dictionary) Search(word string) (string, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	definition, ok := d.words[word]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return definition, nil
+}
+
+// SearchFold is Search ignoring case, returning the word as it is stored. An
+// exact match wins over other spellings.
+func (d *Dictionary) SearchFold(word string) (Entry, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if definition, ok := d.words[word]; ok {
+		return Entry{word, definition}, nil
+	}
+	for _, w := range d.sortedWords() {
+		if strings.EqualFold(w, word) {
+			return Entry{w, d.words[w]}, nil
+		}
+	}
+	return Entry{}, ErrNotFound
+}
+
+// SearchPrefix returns the entries starting with prefix (ignoring case) in word order.
+func (d *Dictionary) SearchPrefix(prefix string) []Entry {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	prefix = strings.ToLower(prefix)
+	entries := []Entry{}
+	for _, w := range d.sortedWords() {
+		if strings.HasPrefix(strings.ToLower(w), prefix) {
+			entries = append(entries, Entry{w, d.words[w]})
+		}
+	}
+	return entries
+}
+
+// SearchFuzzy returns the entries within maxDistance edits (ignoring case) of word,
+// closest first.
+func (d *Dictionary) SearchFuzzy(word string, maxDistance int) []Match {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	word = strings.ToLower(word)
+	matches := []Match{}
+	for _, w := range d.sortedWords() {
+		if distance := levenshtein(word, strings.ToLower(w)); distance <= maxDistance {
+			matches = append(matches, Match{Entry{w, d.words[w]}, distance})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Distance < matches[j].Distance
+	})
+	return matches
+}
+
+func (d *Dictionary) Add(word, definition string) error {
+	if word == "" {
+		return ErrEmptyWord
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.words[word]; ok {
+		return ErrWordExists
+	}
+	return d.set(word, definition)
+}
+
+func (d *Dictionary) Update(word, definition string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.words[word]; !ok {
+		return ErrWordDoesNotExist
+	}
+	return d.set(word, definition)
+}
+
+// Delete removes word. Deleting an unknown word is not an error, like delete on a map.
+func (d *Dictionary) Delete(word string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	old, ok := d.words[word]
+	if !ok {
+		return nil
+	}
+	delete(d.words, word)
+	if err := d.persist(); err != nil {
+		d.words[word] = old
+		return err
+	}
+	return nil
+}
+
+// Entries returns every entry in word order.
+func (d *Dictionary) Entries() []Entry {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.entries()
+}
+
+func (d *Dictionary) entries() []Entry {
+	entries := make([]Entry, 0, len(d.words))
+	for _, w := range d.sortedWords() {
+		entries = append(entries, Entry{w, d.words[w]})
+	}
+	return entries
+}
+
+// merge adds or overwrites entries in one go, so an import is saved once.
+// Entries without a word are rejected like in Add, and nothing is added.
+func (d *Dictionary) merge(entries []Entry) error {
+	for i, e := range entries {
+		if e.Word == "" {
+			return fmt.Errorf("%w: entry %d: %w", ErrInvalidImport, i, ErrEmptyWord)
+		}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	backup := make(map[string]string, len(d.words))
+	for w, def := range d.words {
+		backup[w] = def
+	}
+	for _, e := range entries {
+		d.words[e.Word] = e.Definition
+	}
+	if err := d.persist(); err != nil {
+		d.words = backup
+		return err
+	}
+	return nil
+}
+
+func (d *Dictionary) set(word, definition string) error {
+	old, existed := d.words[word]
+	d.words[word] = definition
+	if err := d.persist(); err != nil {
+		if existed {
+			d.words[word] = old
+		} else {
+			delete(d.words, word)
+		}
+		return err
+	}
+	return nil
+}
+
+func (d *Dictionary) persist() error {
+	if d.save == nil {
+		return nil
+	}
+	return d.save()
+}
+
+func (d *Dictionary) sortedWords() []string {
+	words := make([]string, 0, len(d.words))
+	for w := range d.words {
+		words = append(words, w)
+	}
+	sort.Strings(words)
+	return words
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,161 @@
+package dictionary
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	dictionary := New()
+	dictionary.Add("test", "this is just a test")
+
+	t.Run("known word", func(t *testing.T) {
+		got, err := dictionary.Search("test")
+		assertError(t, err, nil)
+		assertStrings(t, got, "this is just a test")
+	})
+
+	t.Run("unknown word", func(t *testing.T) {
+		_, err := dictionary.Search("unknown")
+		assertError(t, err, ErrNotFound)
+	})
+
+	t.Run("search is case sensitive", func(t *testing.T) {
+		_, err := dictionary.Search("TEST")
+		assertError(t, err, ErrNotFound)
+	})
+
+	t.Run("case insensitive search", func(t *testing.T) {
+		got, err := dictionary.SearchFold("TeSt")
+		assertError(t, err, nil)
+		if want := (Entry{"test", "this is just a test"}); got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+
+		_, err = dictionary.SearchFold("unknown")
+		assertError(t, err, ErrNotFound)
+	})
+}
+
+func TestAddUpdateDelete(t *testing.T) {
+	dictionary := New()
+
+	assertError(t, dictionary.Add("test", "this is just a test"), nil)
+	assertError(t, dictionary.Add("test", "new test"), ErrWordExists)
+	assertError(t, dictionary.Add("", "no word"), ErrEmptyWord)
+	assertDefinition(t, dictionary, "test", "this is just a test")
+
+	assertError(t, dictionary.Update("test", "new definition"), nil)
+	assertDefinition(t, dictionary, "test", "new definition")
+	assertError(t, dictionary.Update("unknown", "definition"), ErrWordDoesNotExist)
+
+	assertError(t, dictionary.Delete("test"), nil)
+	assertError(t, dictionary.Delete("test"), nil)
+	_, err := dictionary.Search("test")
+	assertError(t, err, ErrNotFound)
+}
+
+func TestSearchPrefix(t *testing.T) {
+	dictionary := newDictionary(t, "go", "gopher", "Golang", "rust")
+
+	got := dictionary.SearchPrefix("go")
+	want := []Entry{{"Golang", "def Golang"}, {"go", "def go"}, {"gopher", "def gopher"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if got := dictionary.SearchPrefix("python"); len(got) != 0 {
+		t.Errorf("got %v, want no entries", got)
+	}
+}
+
+func TestSearchFuzzy(t *testing.T) {
+	dictionary := newDictionary(t, "gopher", "golfer", "goph", "rust")
+
+	got := dictionary.SearchFuzzy("Gopher", 2)
+	want := []Match{
+		{Entry{"gopher", "def gopher"}, 0},
+		{Entry{"golfer", "def golfer"}, 2},
+		{Entry{"goph", "def goph"}, 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"日本", "日本語", 1},
+	}
+	for _, test := range cases {
+		t.Run(fmt.Sprintf("%s to %s", test.a, test.b), func(t *testing.T) {
+			if got := levenshtein(test.a, test.b); got != test.want {
+				t.Errorf("got %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	dictionary := New()
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			word := fmt.Sprintf("word%d", i)
+			dictionary.Add(word, "definition")
+			dictionary.Search(word)
+			dictionary.SearchPrefix("word")
+			dictionary.Update(word, "new definition")
+		}(i)
+	}
+	wg.Wait()
+
+	if got := len(dictionary.Entries()); got != 100 {
+		t.Errorf("got %d entries, want 100", got)
+	}
+}
+
+func newDictionary(t testing.TB, words ...string) *Dictionary {
+	t.Helper()
+	dictionary := New()
+	for _, w := range words {
+		if err := dictionary.Add(w, "def "+w); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dictionary
+}
+
+func assertDefinition(t testing.TB, dictionary *Dictionary, word, definition string) {
+	t.Helper()
+	got, err := dictionary.Search(word)
+	if err != nil {
+		t.Fatal("should find added word:", err)
+	}
+	assertStrings(t, got, definition)
+}
+
+func assertError(t testing.TB, got, want error) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func assertStrings(t testing.TB, got, want string) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
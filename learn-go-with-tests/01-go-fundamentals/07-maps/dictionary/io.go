@@ -0,0 +1,111 @@
+package dictionary
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+var ErrInvalidImport = errors.New("invalid import data")
+
+// ImportJSON reads a JSON array of entries and adds them, overwriting existing words.
+func (d *Dictionary) ImportJSON(r io.Reader) (int, error) {
+	var entries []Entry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return 0, fmt.Errorf("%w: problem parsing dictionary json, %v", ErrInvalidImport, err)
+	}
+	return len(entries), d.merge(entries)
+}
+
+func (d *Dictionary) ExportJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d.Entries())
+}
+
+// ImportCSV reads "word,definition" records and adds them, overwriting existing words.
+// A first record of exactly "word,definition" is treated as a header.
+func (d *Dictionary) ImportCSV(r io.Reader) (int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	records, err := reader.ReadAll()
+	if err != nil {
+		return 0, fmt.Errorf("%w: problem parsing dictionary csv, %v", ErrInvalidImport, err)
+	}
+	if len(records) > 0 && records[0][0] == "word" && records[0][1] == "definition" {
+		records = records[1:]
+	}
+	entries := make([]Entry, 0, len(records))
+	for _, rec := range records {
+		entries = append(entries, Entry{rec[0], rec[1]})
+	}
+	return len(entries), d.merge(entries)
+}
+
+func (d *Dictionary) ExportCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"word", "definition"}); err != nil {
+		return err
+	}
+	for _, e := range d.Entries() {
+		if err := writer.Write([]string{e.Word, e.Definition}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// DictionaryFromFile loads the JSON dictionary at path (an empty dictionary if the file
+// does not exist yet) and saves every change back to it.
+func DictionaryFromFile(path string) (*Dictionary, error) {
+	d := New()
+	f, err := os.Open(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("problem opening %s %v", path, err)
+	default:
+		defer f.Close()
+		if _, err := d.ImportJSON(f); err != nil && !isEmpty(f) {
+			return nil, fmt.Errorf("problem loading dictionary from file %s, %v", path, err)
+		}
+	}
+	d.save = func() error {
+		return writeFile(path, d.exportLocked)
+	}
+	return d, nil
+}
+
+// exportLocked is ExportJSON for callers already holding the lock.
+func (d *Dictionary) exportLocked(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d.entries())
+}
+
+// writeFile replaces path atomically so a crash never leaves a half written dictionary.
+func writeFile(path string, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("problem saving dictionary, %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("problem saving dictionary, %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("problem saving dictionary, %v", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func isEmpty(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Size() == 0
+}
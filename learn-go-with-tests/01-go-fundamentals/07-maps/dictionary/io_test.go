@@ -0,0 +1,112 @@
+package dictionary
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportExport(t *testing.T) {
+	t.Run("csv round trip", func(t *testing.T) {
+		dictionary := newDictionary(t, "go", "comma")
+		dictionary.Update("comma", "a, b")
+
+		var buf bytes.Buffer
+		assertError(t, dictionary.ExportCSV(&buf), nil)
+		assertStrings(t, buf.String(), "word,definition\ncomma,\"a, b\"\ngo,def go\n")
+
+		imported := New()
+		n, err := imported.ImportCSV(&buf)
+		assertError(t, err, nil)
+		if n != 2 || !reflect.DeepEqual(imported.Entries(), dictionary.Entries()) {
+			t.Errorf("got %d entries %v, want %v", n, imported.Entries(), dictionary.Entries())
+		}
+	})
+
+	t.Run("json round trip", func(t *testing.T) {
+		dictionary := newDictionary(t, "go", "rust")
+
+		var buf bytes.Buffer
+		assertError(t, dictionary.ExportJSON(&buf), nil)
+
+		imported := New()
+		n, err := imported.ImportJSON(&buf)
+		assertError(t, err, nil)
+		if n != 2 || !reflect.DeepEqual(imported.Entries(), dictionary.Entries()) {
+			t.Errorf("got %d entries %v, want %v", n, imported.Entries(), dictionary.Entries())
+		}
+	})
+
+	t.Run("import overwrites existing words", func(t *testing.T) {
+		dictionary := newDictionary(t, "go")
+		_, err := dictionary.ImportCSV(strings.NewReader("go,new\nrust,def rust\n"))
+		assertError(t, err, nil)
+		assertDefinition(t, dictionary, "go", "new")
+		assertDefinition(t, dictionary, "rust", "def rust")
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		if _, err := New().ImportCSV(strings.NewReader("only-one-field\n")); err == nil {
+			t.Error("expected an error for a record without definition")
+		}
+		if _, err := New().ImportJSON(strings.NewReader("{")); err == nil {
+			t.Error("expected an error for broken json")
+		}
+	})
+
+	t.Run("entries without a word", func(t *testing.T) {
+		dictionary := New()
+		_, err := dictionary.ImportJSON(strings.NewReader(`[{"word": "go", "definition": "def go"}, {"definition": "no word"}]`))
+		if !errors.Is(err, ErrEmptyWord) || !errors.Is(err, ErrInvalidImport) {
+			t.Errorf("got %v, want %v", err, ErrEmptyWord)
+		}
+		if got := dictionary.Entries(); len(got) != 0 {
+			t.Errorf("got entries %v, want none", got)
+		}
+	})
+}
+
+func TestDictionaryFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dictionary.json")
+
+	dictionary, err := DictionaryFromFile(path)
+	assertError(t, err, nil)
+	assertError(t, dictionary.Add("go", "a language"), nil)
+	assertError(t, dictionary.Add("rust", "another language"), nil)
+	assertError(t, dictionary.Delete("rust"), nil)
+
+	reopened, err := DictionaryFromFile(path)
+	assertError(t, err, nil)
+	want := []Entry{{"go", "a language"}}
+	if got := reopened.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	t.Run("empty file", func(t *testing.T) {
+		empty := filepath.Join(t.TempDir(), "empty.json")
+		os.WriteFile(empty, nil, 0666)
+		dictionary, err := DictionaryFromFile(empty)
+		assertError(t, err, nil)
+		if len(dictionary.Entries()) != 0 {
+			t.Errorf("got %v, want an empty dictionary", dictionary.Entries())
+		}
+	})
+
+	t.Run("changes are rolled back when saving fails", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "gone")
+		os.Mkdir(dir, 0777)
+		dictionary, err := DictionaryFromFile(filepath.Join(dir, "dictionary.json"))
+		assertError(t, err, nil)
+		os.RemoveAll(dir)
+
+		if err := dictionary.Add("go", "a language"); err == nil {
+			t.Fatal("expected an error when the file cannot be written")
+		}
+		_, err = dictionary.Search("go")
+		assertError(t, err, ErrNotFound)
+	})
+}
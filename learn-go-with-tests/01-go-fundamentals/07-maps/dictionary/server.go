@@ -0,0 +1,163 @@
+package dictionary
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const jsonContentType = "application/json"
+
+// DictionaryServer exposes a Dictionary as a JSON API:
+//
+//	GET    /words?prefix=ab | ?fuzzy=word&distance=2   list or search entries
+//	GET    /words/{word}[?ignore_case=true]            Search / SearchFold
+//	POST   /words           {"word","definition"}      Add
+//	PUT    /words/{word}    {"definition"}             Update
+//	DELETE /words/{word}                               Delete
+//	GET    /export?format=json|csv
+//	POST   /import?format=json|csv
+type DictionaryServer struct {
+	dictionary *Dictionary
+	http.Handler
+}
+
+func NewDictionaryServer(dictionary *Dictionary) *DictionaryServer {
+	s := &DictionaryServer{dictionary: dictionary}
+
+	router := http.NewServeMux()
+	router.HandleFunc("GET /words", s.listWords)
+	router.HandleFunc("POST /words", s.addWord)
+	router.HandleFunc("GET /words/{word}", s.searchWord)
+	router.HandleFunc("PUT /words/{word}", s.updateWord)
+	router.HandleFunc("DELETE /words/{word}", s.deleteWord)
+	router.HandleFunc("GET /export", s.export)
+	router.HandleFunc("POST /import", s.importWords)
+	s.Handler = router
+
+	return s
+}
+
+func (s *DictionaryServer) listWords(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch {
+	case query.Has("fuzzy"):
+		distance := 2
+		if d := query.Get("distance"); d != "" {
+			var err error
+			if distance, err = strconv.Atoi(d); err != nil || distance < 0 {
+				writeError(w, http.StatusBadRequest, errors.New("distance must be a non-negative integer"))
+				return
+			}
+		}
+		writeJSON(w, http.StatusOK, s.dictionary.SearchFuzzy(query.Get("fuzzy"), distance))
+	default:
+		writeJSON(w, http.StatusOK, s.dictionary.SearchPrefix(query.Get("prefix")))
+	}
+}
+
+func (s *DictionaryServer) searchWord(w http.ResponseWriter, r *http.Request) {
+	entry := Entry{Word: r.PathValue("word")}
+	var err error
+	if ignoreCase, _ := strconv.ParseBool(r.URL.Query().Get("ignore_case")); ignoreCase {
+		entry, err = s.dictionary.SearchFold(entry.Word)
+	} else {
+		entry.Definition, err = s.dictionary.Search(entry.Word)
+	}
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entry)
+}
+
+func (s *DictionaryServer) addWord(w http.ResponseWriter, r *http.Request) {
+	var entry Entry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("body must be {\"word\": ..., \"definition\": ...}"))
+		return
+	}
+	if err := s.dictionary.Add(entry.Word, entry.Definition); err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, entry)
+}
+
+func (s *DictionaryServer) updateWord(w http.ResponseWriter, r *http.Request) {
+	var entry Entry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("body must be {\"definition\": ...}"))
+		return
+	}
+	entry.Word = r.PathValue("word")
+	if err := s.dictionary.Update(entry.Word, entry.Definition); err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entry)
+}
+
+func (s *DictionaryServer) deleteWord(w http.ResponseWriter, r *http.Request) {
+	if err := s.dictionary.Delete(r.PathValue("word")); err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *DictionaryServer) export(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Query().Get("format") {
+	case "csv":
+		w.Header().Set("content-type", "text/csv")
+		s.dictionary.ExportCSV(w)
+	case "", "json":
+		w.Header().Set("content-type", jsonContentType)
+		s.dictionary.ExportJSON(w)
+	default:
+		writeError(w, http.StatusBadRequest, errors.New("format must be json or csv"))
+	}
+}
+
+func (s *DictionaryServer) importWords(w http.ResponseWriter, r *http.Request) {
+	var n int
+	var err error
+	switch r.URL.Query().Get("format") {
+	case "csv":
+		n, err = s.dictionary.ImportCSV(r.Body)
+	case "", "json":
+		n, err = s.dictionary.ImportJSON(r.Body)
+	default:
+		err = fmt.Errorf("%w: format must be json or csv", ErrInvalidImport)
+	}
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"imported": n})
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrWordDoesNotExist):
+		return http.StatusNotFound
+	case errors.Is(err, ErrWordExists):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidImport), errors.Is(err, ErrEmptyWord):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("content-type", jsonContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
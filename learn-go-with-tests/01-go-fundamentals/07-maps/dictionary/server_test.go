@@ -0,0 +1,125 @@
+package dictionary
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDictionaryServer(t *testing.T) {
+	dictionary := newDictionary(t, "go", "gopher", "rust")
+	server := NewDictionaryServer(dictionary)
+
+	t.Run("search a word", func(t *testing.T) {
+		response := serve(server, http.MethodGet, "/words/go", nil)
+		assertStatus(t, response, http.StatusOK)
+		assertEntry(t, response, Entry{"go", "def go"})
+	})
+
+	t.Run("search ignoring case", func(t *testing.T) {
+		response := serve(server, http.MethodGet, "/words/RUST?ignore_case=true", nil)
+		assertStatus(t, response, http.StatusOK)
+		assertEntry(t, response, Entry{"rust", "def rust"})
+	})
+
+	t.Run("unknown word", func(t *testing.T) {
+		response := serve(server, http.MethodGet, "/words/python", nil)
+		assertStatus(t, response, http.StatusNotFound)
+	})
+
+	t.Run("prefix search", func(t *testing.T) {
+		response := serve(server, http.MethodGet, "/words?prefix=go", nil)
+		assertStatus(t, response, http.StatusOK)
+		var got []Entry
+		json.NewDecoder(response.Body).Decode(&got)
+		want := []Entry{{"go", "def go"}, {"gopher", "def gopher"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("fuzzy search", func(t *testing.T) {
+		response := serve(server, http.MethodGet, "/words?fuzzy=rost&distance=1", nil)
+		assertStatus(t, response, http.StatusOK)
+		var got []Match
+		json.NewDecoder(response.Body).Decode(&got)
+		want := []Match{{Entry{"rust", "def rust"}, 1}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+
+		response = serve(server, http.MethodGet, "/words?fuzzy=rost&distance=-1", nil)
+		assertStatus(t, response, http.StatusBadRequest)
+	})
+
+	t.Run("add, update and delete", func(t *testing.T) {
+		response := serve(server, http.MethodPost, "/words", strings.NewReader(`{"word": "zig", "definition": "a language"}`))
+		assertStatus(t, response, http.StatusCreated)
+
+		response = serve(server, http.MethodPost, "/words", strings.NewReader(`{"word": "zig", "definition": "again"}`))
+		assertStatus(t, response, http.StatusConflict)
+
+		response = serve(server, http.MethodPut, "/words/zig", strings.NewReader(`{"definition": "a new language"}`))
+		assertStatus(t, response, http.StatusOK)
+		assertDefinition(t, dictionary, "zig", "a new language")
+
+		response = serve(server, http.MethodPut, "/words/python", strings.NewReader(`{"definition": "a snake"}`))
+		assertStatus(t, response, http.StatusNotFound)
+
+		response = serve(server, http.MethodDelete, "/words/zig", nil)
+		assertStatus(t, response, http.StatusNoContent)
+		_, err := dictionary.Search("zig")
+		assertError(t, err, ErrNotFound)
+	})
+
+	t.Run("bad request body", func(t *testing.T) {
+		response := serve(server, http.MethodPost, "/words", strings.NewReader(`{"definition": "no word"}`))
+		assertStatus(t, response, http.StatusBadRequest)
+
+		response = serve(server, http.MethodPost, "/import?format=json", strings.NewReader(`[{"definition": "no word"}]`))
+		assertStatus(t, response, http.StatusBadRequest)
+	})
+
+	t.Run("import and export", func(t *testing.T) {
+		response := serve(server, http.MethodPost, "/import?format=csv", strings.NewReader("word,definition\nzig,a language\n"))
+		assertStatus(t, response, http.StatusOK)
+		assertDefinition(t, dictionary, "zig", "a language")
+
+		response = serve(server, http.MethodGet, "/export?format=csv", nil)
+		assertStatus(t, response, http.StatusOK)
+		body, _ := io.ReadAll(response.Body)
+		want := "word,definition\ngo,def go\ngopher,def gopher\nrust,def rust\nzig,a language\n"
+		assertStrings(t, string(body), want)
+
+		response = serve(server, http.MethodGet, "/export?format=xml", nil)
+		assertStatus(t, response, http.StatusBadRequest)
+	})
+}
+
+func serve(server http.Handler, method, target string, body io.Reader) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, httptest.NewRequest(method, target, body))
+	return response
+}
+
+func assertStatus(t testing.TB, response *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if response.Code != want {
+		t.Errorf("got status %d, want %d (body %q)", response.Code, want, response.Body.String())
+	}
+}
+
+func assertEntry(t testing.TB, response *httptest.ResponseRecorder, want Entry) {
+	t.Helper()
+	var got Entry
+	if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+		t.Fatalf("unable to parse response %q, %v", response.Body.String(), err)
+	}
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -1,21 +1,65 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"golang.org/x/text/language"
+
+	"tmp/learn-go-with-tests/01-go-fundamentals/08-dependency-injection/greeter"
 )
 
 func Greet(writer io.Writer, name string) {
 	fmt.Fprintf(writer, "Hello, %s", name) // Fprintf takes writer while Printf uses stdout as writer
 }
 
+// NewGreeterHandler greets the name of the path (/Chris) or of the query
+// (/?name=Chris), or the world, in the language that best matches the
+// Accept-Language header.
+func NewGreeterHandler(g greeter.Greeter) http.Handler {
+	greet := func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		if name == "" {
+			name = r.URL.Query().Get("name")
+		}
+		// an invalid header is no preference, not an error
+		preferred, _, _ := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+
+		greeting, err := g.Greet(r.Context(), name, preferred...)
+		if errors.Is(err, greeter.ErrInvalidName) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Language", greeting.Language.String())
+		w.Header().Add("Vary", "Accept-Language")
+		io.WriteString(w, greeting.Message)
+	}
+
+	router := http.NewServeMux()
+	router.HandleFunc("GET /{$}", greet)
+	router.HandleFunc("GET /{name}", greet)
+	return router
+}
+
 func MyGreeterHandler(w http.ResponseWriter, r *http.Request) {
-	Greet(w, "world")
+	NewGreeterHandler(greeter.Default()).ServeHTTP(w, r)
 }
 
 func main() {
 	// Greet(os.Stdout, "Elodie")
-	log.Fatal(http.ListenAndServe(":5000", http.HandlerFunc(MyGreeterHandler))) // you can open localhost:5000
+	g := greeter.Chain(greeter.Default(),
+		greeter.Logging(log.New(os.Stderr, "greeter ", log.LstdFlags)),
+		greeter.Tracing(otel.Tracer("greeter")),
+	)
+	log.Fatal(http.ListenAndServe(":5000", NewGreeterHandler(g))) // you can open localhost:5000
 }
@@ -2,6 +2,8 @@ package main
 
 import (
 	"bytes"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -16,3 +18,43 @@ func TestGreet(t *testing.T) {
 		t.Errorf("got %q want %q", got, want)
 	}
 }
+
+func TestGreeterHandler(t *testing.T) {
+	cases := []struct {
+		acceptLanguage, path string
+		wantStatus           int
+		wantLanguage, want   string
+	}{
+		{"", "/", http.StatusOK, "en", "Hello, World!"},
+		{"fr-CH, fr;q=0.9, en;q=0.8", "/?name=Chris", http.StatusOK, "fr", "Bonjour, Chris !"},
+		{"fr", "/Chris", http.StatusOK, "fr", "Bonjour, Chris !"},
+		{"pt-BR", "/", http.StatusOK, "pt-BR", "Oi, Mundo!"},
+		{"pt-PT", "/", http.StatusOK, "pt", "Olá, Mundo!"},
+		{"ja, es;q=0.5", "/Elodie", http.StatusOK, "es", "Hola, Elodie!"},
+		{"ja", "/", http.StatusOK, "en", "Hello, World!"},
+		{"", "/?name=%07", http.StatusBadRequest, "", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.acceptLanguage+" "+c.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, c.path, nil)
+			req.Header.Set("Accept-Language", c.acceptLanguage)
+			res := httptest.NewRecorder()
+
+			MyGreeterHandler(res, req)
+
+			if res.Code != c.wantStatus {
+				t.Fatalf("got status %d want %d", res.Code, c.wantStatus)
+			}
+			if c.wantStatus != http.StatusOK {
+				return
+			}
+			if got := res.Body.String(); got != c.want {
+				t.Errorf("got %q want %q", got, c.want)
+			}
+			if got := res.Header().Get("Content-Language"); got != c.wantLanguage {
+				t.Errorf("got Content-Language %q want %q", got, c.wantLanguage)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Package greeter is the greeting domain service behind every transport: the
+// HTTP handler of this chapter and the gRPC helloworld.Greeter server in
+// pragmatic-cases/opentelemetry/grpc.
+package greeter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+
+	"tmp/learn-go-with-tests/01-go-fundamentals/01-hello-world/i18n"
+)
+
+const maxNameLength = 100
+
+// ErrInvalidName is a name no transport should pass on: too long or with
+// control characters.
+var ErrInvalidName = errors.New("greeter: invalid name")
+
+// Greeting is a message and the language it is in.
+type Greeting struct {
+	Message  string
+	Language language.Tag
+}
+
+type Greeter interface {
+	// Greet greets name, or the world when it is empty, in the supported
+	// language closest to the preferred ones.
+	Greet(ctx context.Context, name string, preferred ...language.Tag) (Greeting, error)
+	// GreetAll sends the greeting in every supported language, stopping at the
+	// first error of send or when ctx is done.
+	GreetAll(ctx context.Context, name string, send func(Greeting) error) error
+}
+
+// Service greets with the messages of a catalog.
+type Service struct {
+	catalog *i18n.Catalog
+}
+
+func New(catalog *i18n.Catalog) *Service {
+	return &Service{catalog: catalog}
+}
+
+// Default greets with i18n.Default.
+func Default() *Service {
+	return New(i18n.Default())
+}
+
+func (s *Service) Greet(ctx context.Context, name string, preferred ...language.Tag) (Greeting, error) {
+	if err := validate(name); err != nil {
+		return Greeting{}, err
+	}
+	return s.greet(s.catalog.Match(preferred...), name)
+}
+
+func (s *Service) GreetAll(ctx context.Context, name string, send func(Greeting) error) error {
+	if err := validate(name); err != nil {
+		return err
+	}
+	for _, lang := range s.catalog.Languages() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		greeting, err := s.greet(lang, name)
+		if err != nil {
+			return err
+		}
+		if err := send(greeting); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) greet(lang language.Tag, name string) (Greeting, error) {
+	if name == "" {
+		name, _ = s.catalog.Translate(lang, "world", nil)
+	}
+	message, err := s.catalog.Translate(lang, "greeting", i18n.Vars{"name": name})
+	if err != nil {
+		return Greeting{}, err
+	}
+	return Greeting{Message: message, Language: lang}, nil
+}
+
+func validate(name string) error {
+	switch {
+	case utf8.RuneCountInString(name) > maxNameLength:
+		return fmt.Errorf("%w: longer than %d characters", ErrInvalidName, maxNameLength)
+	case strings.IndexFunc(name, unicode.IsControl) >= 0:
+		return fmt.Errorf("%w: contains control characters", ErrInvalidName)
+	}
+	return nil
+}
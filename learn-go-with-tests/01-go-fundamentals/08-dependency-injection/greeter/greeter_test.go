@@ -0,0 +1,96 @@
+package greeter
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestGreet(t *testing.T) {
+	ctx := context.Background()
+	g := Default()
+
+	cases := []struct {
+		name      string
+		preferred []language.Tag
+		want      Greeting
+	}{
+		{"Chris", nil, Greeting{"Hello, Chris!", language.English}},
+		{"", []language.Tag{language.German}, Greeting{"Hallo, Welt!", language.German}},
+		{"Elodie", []language.Tag{language.Japanese, language.French}, Greeting{"Bonjour, Elodie !", language.French}},
+		{"", []language.Tag{language.Japanese}, Greeting{"Hello, World!", language.English}},
+	}
+
+	for _, c := range cases {
+		got, err := g.Greet(ctx, c.name, c.preferred...)
+
+		assertNoError(t, err)
+		if got != c.want {
+			t.Errorf("Greet(%q, %v) = %+v, want %+v", c.name, c.preferred, got, c.want)
+		}
+	}
+
+	t.Run("invalid names", func(t *testing.T) {
+		for _, name := range []string{"bell\a", strings.Repeat("x", maxNameLength+1)} {
+			if _, err := g.Greet(ctx, name); !errors.Is(err, ErrInvalidName) {
+				t.Errorf("got %v want %v", err, ErrInvalidName)
+			}
+		}
+	})
+}
+
+func TestGreetAll(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("greets in every language", func(t *testing.T) {
+		var got []string
+		err := Default().GreetAll(ctx, "Chris", func(g Greeting) error {
+			got = append(got, g.Language.String()+" "+g.Message)
+			return nil
+		})
+
+		assertNoError(t, err)
+		want := []string{"en Hello, Chris!", "de Hallo, Chris!", "es Hola, Chris!", "fr Bonjour, Chris !", "pt Olá, Chris!", "pt-BR Oi, Chris!"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("stops at the first send error", func(t *testing.T) {
+		broken := errors.New("broken pipe")
+		sent := 0
+		err := Default().GreetAll(ctx, "Chris", func(Greeting) error {
+			sent++
+			return broken
+		})
+
+		if !errors.Is(err, broken) || sent != 1 {
+			t.Errorf("got %v after %d sends, want %v after 1", err, sent, broken)
+		}
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		sent := 0
+		err := Default().GreetAll(ctx, "Chris", func(Greeting) error {
+			sent++
+			cancel()
+			return nil
+		})
+
+		if !errors.Is(err, context.Canceled) || sent != 1 {
+			t.Errorf("got %v after %d sends, want %v after 1", err, sent, context.Canceled)
+		}
+	})
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
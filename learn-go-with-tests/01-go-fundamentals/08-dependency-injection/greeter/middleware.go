@@ -0,0 +1,96 @@
+package greeter
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/text/language"
+)
+
+// Middleware wraps a Greeter, so logging and tracing are written once for
+// every transport.
+type Middleware func(Greeter) Greeter
+
+// Chain wraps g with mws, the first one outermost.
+func Chain(g Greeter, mws ...Middleware) Greeter {
+	for i := len(mws) - 1; i >= 0; i-- {
+		g = mws[i](g)
+	}
+	return g
+}
+
+// Logging logs every call with its duration and error.
+func Logging(logger *log.Logger) Middleware {
+	return func(next Greeter) Greeter {
+		return &logging{next: next, logger: logger}
+	}
+}
+
+type logging struct {
+	next   Greeter
+	logger *log.Logger
+}
+
+func (l *logging) Greet(ctx context.Context, name string, preferred ...language.Tag) (g Greeting, err error) {
+	defer func(start time.Time) {
+		l.logger.Printf("Greet name=%q preferred=%v language=%s took=%v err=%v", name, preferred, g.Language, time.Since(start), err)
+	}(time.Now())
+	return l.next.Greet(ctx, name, preferred...)
+}
+
+func (l *logging) GreetAll(ctx context.Context, name string, send func(Greeting) error) (err error) {
+	sent := 0
+	defer func(start time.Time) {
+		l.logger.Printf("GreetAll name=%q sent=%d took=%v err=%v", name, sent, time.Since(start), err)
+	}(time.Now())
+	return l.next.GreetAll(ctx, name, func(g Greeting) error {
+		sent++
+		return send(g)
+	})
+}
+
+// Tracing records a span per call, child of the span in ctx, such as the one
+// otelgrpc or otelhttp started for the request.
+func Tracing(tracer trace.Tracer) Middleware {
+	return func(next Greeter) Greeter {
+		return &tracing{next: next, tracer: tracer}
+	}
+}
+
+type tracing struct {
+	next   Greeter
+	tracer trace.Tracer
+}
+
+func (t *tracing) Greet(ctx context.Context, name string, preferred ...language.Tag) (Greeting, error) {
+	ctx, span := t.tracer.Start(ctx, "Greeter.Greet")
+	defer span.End()
+
+	g, err := t.next.Greet(ctx, name, preferred...)
+	span.SetAttributes(attribute.String("greeter.language", g.Language.String()))
+	record(span, err)
+	return g, err
+}
+
+func (t *tracing) GreetAll(ctx context.Context, name string, send func(Greeting) error) error {
+	ctx, span := t.tracer.Start(ctx, "Greeter.GreetAll")
+	defer span.End()
+
+	err := t.next.GreetAll(ctx, name, func(g Greeting) error {
+		span.AddEvent("greeting", trace.WithAttributes(attribute.String("greeter.language", g.Language.String())))
+		return send(g)
+	})
+	record(span, err)
+	return err
+}
+
+func record(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,60 @@
+package greeter
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"golang.org/x/text/language"
+)
+
+func TestMiddleware(t *testing.T) {
+	ctx := context.Background()
+	var logs bytes.Buffer
+	spans := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)).Tracer("test")
+
+	g := Chain(Default(), Logging(log.New(&logs, "", 0)), Tracing(tracer))
+
+	_, err := g.Greet(ctx, "Chris", language.French)
+	assertNoError(t, err)
+	err = g.GreetAll(ctx, "Chris", func(Greeting) error { return nil })
+	assertNoError(t, err)
+	_, err = g.Greet(ctx, "bell\a")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	t.Run("logs every call", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("got %d lines, want 3:\n%s", len(lines), logs.String())
+		}
+		for i, want := range []string{`Greet name="Chris" preferred=[fr] language=fr`, `GreetAll name="Chris" sent=6`, `err=greeter: invalid name`} {
+			if !strings.Contains(lines[i], want) {
+				t.Errorf("line %d is %q, want it to contain %q", i, lines[i], want)
+			}
+		}
+	})
+
+	t.Run("traces every call", func(t *testing.T) {
+		ended := spans.Ended()
+		if len(ended) != 3 {
+			t.Fatalf("got %d spans, want 3", len(ended))
+		}
+		if ended[0].Name() != "Greeter.Greet" || ended[1].Name() != "Greeter.GreetAll" {
+			t.Errorf("got spans %q and %q", ended[0].Name(), ended[1].Name())
+		}
+		if events := ended[1].Events(); len(events) != 6 {
+			t.Errorf("got %d greeting events, want 6", len(events))
+		}
+		if status := ended[2].Status(); status.Code != codes.Error {
+			t.Errorf("got status %v for a failed call", status)
+		}
+	})
+}
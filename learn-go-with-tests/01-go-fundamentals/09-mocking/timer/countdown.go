@@ -0,0 +1,106 @@
+package timer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+type countdown struct {
+	clock    Clock
+	start    int
+	step     int
+	interval time.Duration
+	format   func(int) string
+	final    string
+}
+
+type Option func(*countdown)
+
+func WithClock(c Clock) Option {
+	return func(cd *countdown) { cd.clock = c }
+}
+
+// WithStart sets the first number printed, 3 by default.
+func WithStart(n int) Option {
+	return func(cd *countdown) { cd.start = n }
+}
+
+// WithStep sets how much is subtracted after every print, 1 by default.
+func WithStep(n int) Option {
+	return func(cd *countdown) { cd.step = n }
+}
+
+// WithInterval sets the wait before every print, one second by default.
+func WithInterval(d time.Duration) Option {
+	return func(cd *countdown) { cd.interval = d }
+}
+
+// WithFormat sets how every number is printed, "%d\n" by default.
+func WithFormat(format func(n int) string) Option {
+	return func(cd *countdown) { cd.format = format }
+}
+
+// WithFinalWord sets what is printed after the last number, "Go!" by default.
+func WithFinalWord(s string) Option {
+	return func(cd *countdown) { cd.final = s }
+}
+
+// Countdown prints start, start-step, ... down to the last positive number and
+// then the final word, waiting one interval before every print. It stops with
+// ctx.Err() as soon as ctx is done.
+func Countdown(ctx context.Context, w io.Writer, opts ...Option) error {
+	cd := countdown{
+		clock:    Real,
+		start:    3,
+		step:     1,
+		interval: time.Second,
+		format:   func(n int) string { return fmt.Sprintln(n) },
+		final:    "Go!",
+	}
+	for _, opt := range opts {
+		opt(&cd)
+	}
+	if cd.step < 1 {
+		return fmt.Errorf("timer: countdown step must be positive, got %d", cd.step)
+	}
+
+	for i := cd.start; i > 0; i -= cd.step {
+		if err := cd.wait(ctx); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, cd.format(i)); err != nil {
+			return err
+		}
+	}
+	if err := cd.wait(ctx); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, cd.final)
+	return err
+}
+
+func (cd *countdown) wait(ctx context.Context) error {
+	select {
+	case <-cd.clock.After(cd.interval):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Every calls fn with the tick time every d until ctx is done, then returns
+// ctx.Err(). Ticks are dropped while fn is still running.
+func Every(ctx context.Context, clock Clock, d time.Duration, fn func(time.Time)) error {
+	ticker := clock.NewTicker(d)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C():
+			fn(now)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package timer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCountdown(t *testing.T) {
+	cases := []struct {
+		name  string
+		opts  []Option
+		steps int
+		want  string
+	}{
+		{"prints 3 to Go!", nil, 4, "3\n2\n1\nGo!"},
+		{"custom steps", []Option{WithStart(10), WithStep(4)}, 4, "10\n6\n2\nGo!"},
+		{"custom format", []Option{
+			WithStart(2),
+			WithFormat(func(n int) string { return fmt.Sprintf("T-%d ", n) }),
+			WithFinalWord("Liftoff"),
+		}, 3, "T-2 T-1 Liftoff"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clock := NewFake(epoch)
+			buffer := &bytes.Buffer{}
+			done := make(chan error)
+			go func() {
+				done <- Countdown(context.Background(), buffer, append(c.opts, WithClock(clock), WithInterval(time.Second))...)
+			}()
+
+			for range c.steps {
+				clock.BlockUntil(1)
+				clock.Advance(time.Second)
+			}
+
+			if err := <-done; err != nil {
+				t.Fatal(err)
+			}
+			if buffer.String() != c.want {
+				t.Errorf("got %q want %q", buffer.String(), c.want)
+			}
+		})
+	}
+
+	t.Run("waits before every print", func(t *testing.T) {
+		clock := NewFake(epoch)
+		buffer := &bytes.Buffer{}
+		done := make(chan error)
+		go func() { done <- Countdown(context.Background(), buffer, WithClock(clock)) }()
+
+		clock.BlockUntil(1)
+		if buffer.Len() != 0 {
+			t.Errorf("printed %q before the first second", buffer.String())
+		}
+		for range 4 {
+			clock.BlockUntil(1)
+			clock.Advance(time.Second)
+		}
+		<-done
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		clock := NewFake(epoch)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- Countdown(ctx, &bytes.Buffer{}, WithClock(clock)) }()
+
+		clock.BlockUntil(1)
+		cancel()
+
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want %v", err, context.Canceled)
+		}
+	})
+
+	t.Run("rejects a non-positive step", func(t *testing.T) {
+		if err := Countdown(context.Background(), &bytes.Buffer{}, WithStep(0)); err == nil {
+			t.Error("want an error")
+		}
+	})
+}
+
+func TestEvery(t *testing.T) {
+	clock := NewFake(epoch)
+	ctx, cancel := context.WithCancel(context.Background())
+	ticks := make(chan time.Time)
+	done := make(chan error)
+	go func() {
+		done <- Every(ctx, clock, time.Minute, func(now time.Time) { ticks <- now })
+	}()
+
+	clock.BlockUntil(1)
+	for i := 1; i <= 3; i++ {
+		clock.Advance(time.Minute)
+		if got := <-ticks; !got.Equal(epoch.Add(time.Duration(i) * time.Minute)) {
+			t.Errorf("got tick %v, want %v", got, epoch.Add(time.Duration(i)*time.Minute))
+		}
+	}
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+	if clock.Waiters() != 0 {
+		t.Error("ticker was not stopped")
+	}
+}
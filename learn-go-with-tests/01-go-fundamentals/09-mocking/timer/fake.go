@@ -0,0 +1,155 @@
+package timer
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Fake is a Clock that only moves when Advance is called. Timers, tickers and
+// After channels fire synchronously inside Advance, in the order of their
+// deadlines, with Now returning that deadline while they run.
+type Fake struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	waiters []*waiter
+}
+
+var _ Clock = (*Fake)(nil)
+
+type waiter struct {
+	at     time.Time
+	period time.Duration // zero for one-shot waiters
+	fire   func(time.Time)
+}
+
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.changed = sync.NewCond(&f.mu)
+	return f
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	f.schedule(d, 0, func(now time.Time) { ch <- now })
+	return ch
+}
+
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	return &fakeTimer{f, f.schedule(d, 0, func(time.Time) { fn() })}
+}
+
+// NewTicker panics on a non-positive d, like time.NewTicker. As with a real
+// ticker, ticks are dropped while nobody reads the channel.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("timer: non-positive interval for NewTicker")
+	}
+	ch := make(chan time.Time, 1)
+	w := f.schedule(d, d, func(now time.Time) {
+		select {
+		case ch <- now:
+		default:
+		}
+	})
+	return &fakeTicker{fakeTimer{f, w}, ch}
+}
+
+// Advance moves the clock forward by d, firing everything due on the way.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	end := f.now.Add(d)
+	for {
+		w := f.next(end)
+		if w == nil {
+			break
+		}
+		f.now = w.at
+		if w.period > 0 {
+			w.at = w.at.Add(w.period)
+		} else {
+			f.remove(w)
+		}
+		now := f.now
+		f.mu.Unlock()
+		w.fire(now)
+		f.mu.Lock()
+	}
+	f.now = end
+	f.mu.Unlock()
+}
+
+// Waiters returns the number of pending timers, tickers and After channels.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// BlockUntil waits until at least n timers, tickers or After channels are
+// pending. Use it before Advance when the code under test schedules from
+// another goroutine.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.waiters) < n {
+		f.changed.Wait()
+	}
+}
+
+func (f *Fake) schedule(d, period time.Duration, fire func(time.Time)) *waiter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &waiter{at: f.now.Add(d), period: period, fire: fire}
+	f.waiters = append(f.waiters, w)
+	f.changed.Broadcast()
+	return w
+}
+
+// next returns the earliest waiter due at or before end, the first scheduled
+// one winning ties.
+func (f *Fake) next(end time.Time) *waiter {
+	var next *waiter
+	for _, w := range f.waiters {
+		if !w.at.After(end) && (next == nil || w.at.Before(next.at)) {
+			next = w
+		}
+	}
+	return next
+}
+
+func (f *Fake) remove(w *waiter) bool {
+	i := slices.Index(f.waiters, w)
+	if i < 0 {
+		return false
+	}
+	f.waiters = slices.Delete(f.waiters, i, i+1)
+	f.changed.Broadcast()
+	return true
+}
+
+type fakeTimer struct {
+	fake *Fake
+	w    *waiter
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+	return t.fake.remove(t.w)
+}
+
+type fakeTicker struct {
+	fakeTimer
+	c chan time.Time
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.c }
+func (t *fakeTicker) Stop()               { t.fakeTimer.Stop() }
@@ -0,0 +1,104 @@
+package timer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFake(t *testing.T) {
+	t.Run("fires in deadline order with Now at the deadline", func(t *testing.T) {
+		clock := NewFake(epoch)
+		var fired []time.Duration
+		record := func() { fired = append(fired, clock.Now().Sub(epoch)) }
+		clock.AfterFunc(3*time.Second, record)
+		clock.AfterFunc(time.Second, record)
+		clock.AfterFunc(10*time.Second, record)
+
+		clock.Advance(5 * time.Second)
+
+		want := []time.Duration{time.Second, 3 * time.Second}
+		if !reflect.DeepEqual(fired, want) {
+			t.Errorf("got %v, want %v", fired, want)
+		}
+		if got := clock.Now(); !got.Equal(epoch.Add(5 * time.Second)) {
+			t.Errorf("got now %v, want %v", got, epoch.Add(5*time.Second))
+		}
+		if clock.Waiters() != 1 {
+			t.Errorf("got %d waiters, want 1", clock.Waiters())
+		}
+	})
+
+	t.Run("stopped timers do not fire", func(t *testing.T) {
+		clock := NewFake(epoch)
+		fired := false
+		timer := clock.AfterFunc(time.Second, func() { fired = true })
+
+		if !timer.Stop() {
+			t.Error("Stop should report the pending timer")
+		}
+		clock.Advance(time.Minute)
+
+		if fired || timer.Stop() {
+			t.Error("timer fired or was stopped twice")
+		}
+	})
+
+	t.Run("After", func(t *testing.T) {
+		clock := NewFake(epoch)
+		ch := clock.After(time.Second)
+
+		clock.Advance(999 * time.Millisecond)
+		select {
+		case <-ch:
+			t.Fatal("fired too early")
+		default:
+		}
+
+		clock.Advance(time.Millisecond)
+		if got := <-ch; !got.Equal(epoch.Add(time.Second)) {
+			t.Errorf("got %v, want %v", got, epoch.Add(time.Second))
+		}
+	})
+
+	t.Run("ticker drops ticks nobody reads", func(t *testing.T) {
+		clock := NewFake(epoch)
+		ticker := clock.NewTicker(time.Second)
+
+		clock.Advance(3 * time.Second)
+		if got := <-ticker.C(); !got.Equal(epoch.Add(time.Second)) {
+			t.Errorf("got %v, want the first tick", got)
+		}
+		clock.Advance(time.Second)
+		if got := <-ticker.C(); !got.Equal(epoch.Add(4 * time.Second)) {
+			t.Errorf("got %v, want the fourth tick", got)
+		}
+
+		ticker.Stop()
+		clock.Advance(time.Minute)
+		select {
+		case <-ticker.C():
+			t.Error("stopped ticker ticked")
+		default:
+		}
+	})
+
+	t.Run("callbacks may schedule again", func(t *testing.T) {
+		clock := NewFake(epoch)
+		count := 0
+		var again func()
+		again = func() {
+			count++
+			clock.AfterFunc(time.Second, again)
+		}
+		clock.AfterFunc(time.Second, again)
+
+		clock.Advance(5 * time.Second)
+
+		if count != 5 {
+			t.Errorf("got %d calls, want 5", count)
+		}
+	})
+}
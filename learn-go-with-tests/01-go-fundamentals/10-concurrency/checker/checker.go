@@ -0,0 +1,196 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const maxRedirects = 10
+
+// Result is the outcome of checking one url.
+type Result struct {
+	URL        string
+	StatusCode int
+	Latency    time.Duration // of the last attempt
+	Attempts   int
+	Redirects  []string // urls followed after URL, in order
+	Err        error
+}
+
+func (r Result) MarshalJSON() ([]byte, error) {
+	var errMsg string
+	if r.Err != nil {
+		errMsg = r.Err.Error()
+	}
+	return json.Marshal(struct {
+		URL        string   `json:"url"`
+		OK         bool     `json:"ok"`
+		StatusCode int      `json:"status_code,omitempty"`
+		LatencyMS  float64  `json:"latency_ms"`
+		Attempts   int      `json:"attempts"`
+		Redirects  []string `json:"redirects,omitempty"`
+		Error      string   `json:"error,omitempty"`
+	}{r.URL, r.OK(), r.StatusCode, float64(r.Latency.Microseconds()) / 1000, r.Attempts, r.Redirects, errMsg})
+}
+
+// OK reports whether the final response was 2xx or 3xx.
+func (r Result) OK() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode < 400
+}
+
+type options struct {
+	client      *http.Client
+	concurrency int
+	timeout     time.Duration
+	retries     int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+}
+
+type Option func(*options)
+
+func WithClient(c *http.Client) Option {
+	return func(o *options) { o.client = c }
+}
+
+// WithConcurrency limits how many urls are checked at the same time.
+func WithConcurrency(n int) Option {
+	return func(o *options) { o.concurrency = n }
+}
+
+// WithTimeout bounds every attempt, including redirects.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) { o.timeout = d }
+}
+
+// WithRetries retries network errors, 429 and 5xx responses up to n times,
+// waiting backoff, 2*backoff, 4*backoff... (capped at maxBackoff) in between.
+func WithRetries(n int, backoff, maxBackoff time.Duration) Option {
+	return func(o *options) {
+		o.retries = n
+		o.backoff = backoff
+		o.maxBackoff = maxBackoff
+	}
+}
+
+type Checker struct {
+	options
+}
+
+func New(opts ...Option) *Checker {
+	o := options{
+		client:      http.DefaultClient,
+		concurrency: 10,
+		timeout:     10 * time.Second,
+		backoff:     100 * time.Millisecond,
+		maxBackoff:  5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.concurrency < 1 {
+		o.concurrency = 1
+	}
+	return &Checker{o}
+}
+
+// Check checks urls concurrently and sends every Result as soon as it is ready.
+// The channel is buffered for all urls and closed once every url has a result,
+// so callers may stop reading early without leaking goroutines. Urls not started
+// before ctx is done get a Result carrying ctx.Err().
+func (c *Checker) Check(ctx context.Context, urls []string) <-chan Result {
+	results := make(chan Result, len(urls))
+	go func() {
+		defer close(results)
+		var g errgroup.Group
+		g.SetLimit(c.concurrency)
+		for _, url := range urls {
+			if err := ctx.Err(); err != nil {
+				results <- Result{URL: url, Err: err}
+				continue
+			}
+			g.Go(func() error {
+				results <- c.check(ctx, url)
+				return nil
+			})
+		}
+		g.Wait()
+	}()
+	return results
+}
+
+// CheckAll is Check collecting the results in the order of urls.
+func (c *Checker) CheckAll(ctx context.Context, urls []string) []Result {
+	byURL := make(map[string][]Result, len(urls))
+	for r := range c.Check(ctx, urls) {
+		byURL[r.URL] = append(byURL[r.URL], r)
+	}
+	results := make([]Result, len(urls))
+	for i, url := range urls {
+		results[i] = byURL[url][0]
+		byURL[url] = byURL[url][1:]
+	}
+	return results
+}
+
+func (c *Checker) check(ctx context.Context, url string) Result {
+	result := Result{URL: url}
+	backoff := c.backoff
+	for {
+		result.Attempts++
+		retry := c.attempt(ctx, &result)
+		if !retry || result.Attempts > c.retries {
+			return result
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			result.Err = ctx.Err()
+			return result
+		}
+		backoff = min(2*backoff, c.maxBackoff)
+	}
+}
+
+// attempt fills result and reports whether the failure is worth retrying.
+func (c *Checker) attempt(parent context.Context, result *Result) (retry bool) {
+	ctx, cancel := context.WithTimeout(parent, c.timeout)
+	defer cancel()
+
+	result.Redirects = nil
+	client := *c.client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		result.Redirects = append(result.Redirects, req.URL.String())
+		return nil
+	}
+
+	start := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, result.URL, nil)
+	if err != nil {
+		result.Err = err
+		return false
+	}
+	res, err := client.Do(req)
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.StatusCode = 0
+		result.Err = err
+		// network errors and timed out attempts are retried, the caller giving up is not
+		return parent.Err() == nil
+	}
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+	res.Body.Close()
+
+	result.StatusCode = res.StatusCode
+	result.Err = nil
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+}
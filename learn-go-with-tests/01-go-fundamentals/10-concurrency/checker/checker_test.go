@@ -0,0 +1,208 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	t.Run("status and latency", func(t *testing.T) {
+		ok := makeServer(t, http.StatusOK)
+		missing := makeServer(t, http.StatusNotFound)
+
+		results := New().CheckAll(context.Background(), []string{ok.URL, missing.URL, "waat://furhurterwe.geds"})
+
+		assertResult(t, results[0], ok.URL, http.StatusOK, true)
+		assertResult(t, results[1], missing.URL, http.StatusNotFound, false)
+		if results[2].Err == nil || results[2].OK() {
+			t.Errorf("got %+v, want an error", results[2])
+		}
+		if results[0].Latency <= 0 || results[0].Attempts != 1 {
+			t.Errorf("got %+v, want a latency and one attempt", results[0])
+		}
+	})
+
+	t.Run("redirect chain", func(t *testing.T) {
+		final := makeServer(t, http.StatusOK)
+		second := httptest.NewServer(http.RedirectHandler(final.URL+"/done", http.StatusFound))
+		defer second.Close()
+		first := httptest.NewServer(http.RedirectHandler(second.URL+"/next", http.StatusMovedPermanently))
+		defer first.Close()
+
+		results := New().CheckAll(context.Background(), []string{first.URL})
+
+		assertResult(t, results[0], first.URL, http.StatusOK, true)
+		want := []string{second.URL + "/next", final.URL + "/done"}
+		if !reflect.DeepEqual(results[0].Redirects, want) {
+			t.Errorf("got redirects %v, want %v", results[0].Redirects, want)
+		}
+	})
+
+	t.Run("results are streamed as they complete", func(t *testing.T) {
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(50 * time.Millisecond)
+		}))
+		defer slow.Close()
+		fast := makeServer(t, http.StatusOK)
+
+		results := New().Check(context.Background(), []string{slow.URL, fast.URL})
+
+		first := <-results
+		if first.URL != fast.URL {
+			t.Errorf("got %q first, want %q", first.URL, fast.URL)
+		}
+		<-results
+		if _, open := <-results; open {
+			t.Error("channel should be closed after every result")
+		}
+	})
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	urls := make([]string, 20)
+	for i := range urls {
+		urls[i] = server.URL
+	}
+	results := New(WithConcurrency(3)).CheckAll(context.Background(), urls)
+
+	if len(results) != len(urls) {
+		t.Fatalf("got %d results, want %d", len(results), len(urls))
+	}
+	if got := maxInFlight.Load(); got > 3 {
+		t.Errorf("got %d concurrent requests, want at most 3", got)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	t.Run("retries 5xx with backoff until it succeeds", func(t *testing.T) {
+		var calls atomic.Int32
+		var lastCall time.Time
+		var gaps []time.Duration
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !lastCall.IsZero() {
+				gaps = append(gaps, time.Since(lastCall))
+			}
+			lastCall = time.Now()
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+
+		results := New(WithRetries(5, 10*time.Millisecond, time.Second)).CheckAll(context.Background(), []string{server.URL})
+
+		assertResult(t, results[0], server.URL, http.StatusOK, true)
+		if results[0].Attempts != 3 {
+			t.Errorf("got %d attempts, want 3", results[0].Attempts)
+		}
+		if len(gaps) != 2 || gaps[0] < 10*time.Millisecond || gaps[1] < 20*time.Millisecond {
+			t.Errorf("got gaps %v, want at least 10ms then 20ms", gaps)
+		}
+	})
+
+	t.Run("gives up after the retries", func(t *testing.T) {
+		server := makeServer(t, http.StatusBadGateway)
+
+		results := New(WithRetries(2, time.Millisecond, time.Millisecond)).CheckAll(context.Background(), []string{server.URL})
+
+		assertResult(t, results[0], server.URL, http.StatusBadGateway, false)
+		if results[0].Attempts != 3 {
+			t.Errorf("got %d attempts, want 3", results[0].Attempts)
+		}
+	})
+
+	t.Run("does not retry 4xx", func(t *testing.T) {
+		server := makeServer(t, http.StatusNotFound)
+
+		results := New(WithRetries(2, time.Millisecond, time.Millisecond)).CheckAll(context.Background(), []string{server.URL})
+
+		if results[0].Attempts != 1 {
+			t.Errorf("got %d attempts, want 1", results[0].Attempts)
+		}
+	})
+
+	t.Run("retries timed out attempts", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				select {
+				case <-time.After(time.Second):
+				case <-r.Context().Done():
+				}
+			}
+		}))
+		defer server.Close()
+
+		results := New(WithTimeout(20*time.Millisecond), WithRetries(1, time.Millisecond, time.Millisecond)).
+			CheckAll(context.Background(), []string{server.URL})
+
+		assertResult(t, results[0], server.URL, http.StatusOK, true)
+		if results[0].Attempts != 2 {
+			t.Errorf("got %d attempts, want 2", results[0].Attempts)
+		}
+	})
+}
+
+func TestCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	results := New(WithConcurrency(1), WithRetries(3, time.Millisecond, time.Millisecond)).
+		CheckAll(ctx, []string{server.URL, server.URL, server.URL})
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("took %v after the context was done", elapsed)
+	}
+	for _, r := range results {
+		if r.OK() || !errors.Is(r.Err, context.DeadlineExceeded) {
+			t.Errorf("got %+v, want %v", r, context.DeadlineExceeded)
+		}
+		if r.Attempts > 1 {
+			t.Errorf("got %d attempts, the caller giving up must not be retried", r.Attempts)
+		}
+	}
+}
+
+func makeServer(t testing.TB, status int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func assertResult(t testing.TB, got Result, url string, status int, ok bool) {
+	t.Helper()
+	if got.URL != url || got.StatusCode != status || got.OK() != ok {
+		t.Errorf("got %+v, want url %q status %d ok %t", got, url, status, ok)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"tmp/learn-go-with-tests/01-go-fundamentals/10-concurrency/checker"
+)
+
+func main() {
+	file := flag.String("file", "urls.txt", "file with one url per line")
+	format := flag.String("format", "table", "output format: table or json")
+	concurrency := flag.Int("concurrency", 10, "number of urls checked at the same time")
+	timeout := flag.Duration("timeout", 10*time.Second, "timeout of each attempt")
+	retries := flag.Int("retries", 2, "retries for network errors, 429 and 5xx")
+	backoff := flag.Duration("backoff", 200*time.Millisecond, "wait before the first retry, doubled for each next one")
+	flag.Parse()
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	urls, err := checker.ReadURLs(f)
+	f.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c := checker.New(
+		checker.WithConcurrency(*concurrency),
+		checker.WithTimeout(*timeout),
+		checker.WithRetries(*retries, *backoff, 10*(*backoff)),
+	)
+	results := c.CheckAll(ctx, urls)
+
+	switch *format {
+	case "json":
+		err = checker.WriteJSON(os.Stdout, results)
+	default:
+		err = checker.WriteTable(os.Stdout, results)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, r := range results {
+		if !r.OK() {
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package checker
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadURLs(t *testing.T) {
+	input := `
+# production
+https://example.com
+  https://example.org/health
+
+`
+	got, err := ReadURLs(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"https://example.com", "https://example.org/health"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestWriters(t *testing.T) {
+	results := []Result{
+		{URL: "https://a", StatusCode: 200, Latency: 1500 * time.Microsecond, Attempts: 1, Redirects: []string{"https://b"}},
+		{URL: "https://c", Attempts: 3, Err: errors.New("connection refused")},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteTable(&buf, results); err != nil {
+			t.Fatal(err)
+		}
+		want := `URL        OK     STATUS  LATENCY  ATTEMPTS  REDIRECTS  ERROR
+https://a  true   200     2ms      1         https://b
+https://c  false  -       0s       3                    connection refused
+`
+		// tabwriter pads every cell, including the last empty one
+		got := buf.String()
+		for strings.Contains(got, " \n") {
+			got = strings.ReplaceAll(got, " \n", "\n")
+		}
+		if got != want {
+			t.Errorf("got\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteJSON(&buf, results); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{`"latency_ms": 1.5`, `"ok": true`, `"error": "connection refused"`, `"redirects": [`} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("%s does not contain %s", buf.String(), want)
+			}
+		}
+	})
+}
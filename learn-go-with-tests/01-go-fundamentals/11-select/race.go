@@ -0,0 +1,180 @@
+package racer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var ErrNoURLs = errors.New("no urls to race")
+
+// Result describes the winning response. Its body has already been closed.
+type Result struct {
+	URL        string
+	StatusCode int
+	Latency    time.Duration
+}
+
+// UnsuccessfulError is returned for a response rejected by the success predicate.
+type UnsuccessfulError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e UnsuccessfulError) Error() string {
+	return fmt.Sprintf("%s responded with status %d", e.URL, e.StatusCode)
+}
+
+type options struct {
+	client     *http.Client
+	success    func(*http.Response) bool
+	hedgeDelay time.Duration
+	stats      *Stats
+}
+
+type Option func(*options)
+
+func WithClient(c *http.Client) Option {
+	return func(o *options) { o.client = c }
+}
+
+// WithSuccess decides whether a response wins. It may read the body. The default is StatusOK.
+func WithSuccess(success func(*http.Response) bool) Option {
+	return func(o *options) { o.success = success }
+}
+
+// WithHedgeDelay starts the urls one after the other, waiting delay between them,
+// instead of all at once. A failure starts the next url straight away.
+func WithHedgeDelay(delay time.Duration) Option {
+	return func(o *options) { o.hedgeDelay = delay }
+}
+
+// WithStats records every finished request in stats and tries the historically
+// best urls first, which matters when hedging.
+func WithStats(stats *Stats) Option {
+	return func(o *options) { o.stats = stats }
+}
+
+// StatusOK accepts any 2xx response.
+func StatusOK(res *http.Response) bool {
+	return res.StatusCode >= 200 && res.StatusCode < 300
+}
+
+// BodyCheck accepts 2xx responses whose body (up to 1MB) satisfies check.
+func BodyCheck(check func(body []byte) bool) func(*http.Response) bool {
+	return func(res *http.Response) bool {
+		if !StatusOK(res) {
+			return false
+		}
+		body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+		return err == nil && check(body)
+	}
+}
+
+type attempt struct {
+	result Result
+	err    error
+}
+
+// Race GETs the urls concurrently and returns the first successful response.
+// The other requests are cancelled and their bodies closed before Race returns.
+// If every url fails the errors are joined together; if ctx ends first its error is returned.
+func Race(ctx context.Context, urls []string, opts ...Option) (Result, error) {
+	if len(urls) == 0 {
+		return Result{}, ErrNoURLs
+	}
+	o := options{client: http.DefaultClient, success: StatusOK}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.stats != nil {
+		urls = o.stats.Rank(urls)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+
+	// buffered so that losers never block after Race has returned
+	attempts := make(chan attempt, len(urls))
+	started := 0
+	startNext := func() {
+		if started == len(urls) {
+			return
+		}
+		url := urls[started]
+		started++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			attempts <- o.get(ctx, url)
+		}()
+	}
+
+	var hedge <-chan time.Time
+	if o.hedgeDelay > 0 {
+		timer := time.NewTicker(o.hedgeDelay)
+		defer timer.Stop()
+		hedge = timer.C
+		startNext()
+	} else {
+		for range urls {
+			startNext()
+		}
+	}
+
+	var errs []error
+	for len(errs) < len(urls) {
+		select {
+		case a := <-attempts:
+			if a.err == nil {
+				return a.result, nil
+			}
+			errs = append(errs, a.err)
+			startNext()
+		case <-hedge:
+			startNext()
+		case <-ctx.Done():
+			return Result{}, fmt.Errorf("racing %v: %w", urls, ctx.Err())
+		}
+	}
+	return Result{}, errors.Join(errs...)
+}
+
+func (o *options) get(ctx context.Context, url string) attempt {
+	start := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return attempt{err: err}
+	}
+	res, err := o.client.Do(req)
+	if err != nil {
+		o.record(ctx, url, time.Since(start), false)
+		return attempt{err: err}
+	}
+	defer func() {
+		io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10)) // let the connection be reused
+		res.Body.Close()
+	}()
+
+	ok := o.success(res)
+	latency := time.Since(start)
+	o.record(ctx, url, latency, ok)
+	if !ok {
+		return attempt{err: UnsuccessfulError{url, res.StatusCode}}
+	}
+	return attempt{result: Result{url, res.StatusCode, latency}}
+}
+
+// record skips requests cancelled because another url won: they did not fail.
+func (o *options) record(ctx context.Context, url string, latency time.Duration, ok bool) {
+	if o.stats == nil || ctx.Err() != nil {
+		return
+	}
+	o.stats.Record(url, latency, ok)
+}
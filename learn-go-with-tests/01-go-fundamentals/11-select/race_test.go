@@ -0,0 +1,205 @@
+package racer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRace(t *testing.T) {
+	t.Run("returns the fastest of N servers", func(t *testing.T) {
+		slow := makeServer(t, 50*time.Millisecond, http.StatusOK, "")
+		medium := makeServer(t, 25*time.Millisecond, http.StatusOK, "")
+		fast := makeServer(t, 0, http.StatusOK, "")
+
+		got, err := Race(context.Background(), []string{slow.URL, medium.URL, fast.URL})
+
+		assertNoError(t, err)
+		assertWinner(t, got, fast.URL)
+		if got.StatusCode != http.StatusOK || got.Latency <= 0 {
+			t.Errorf("got %+v, want a 200 with a latency", got)
+		}
+	})
+
+	t.Run("a fast 500 does not win", func(t *testing.T) {
+		broken := makeServer(t, 0, http.StatusInternalServerError, "")
+		slow := makeServer(t, 20*time.Millisecond, http.StatusOK, "")
+
+		got, err := Race(context.Background(), []string{broken.URL, slow.URL})
+
+		assertNoError(t, err)
+		assertWinner(t, got, slow.URL)
+	})
+
+	t.Run("custom success predicate checks the body", func(t *testing.T) {
+		wrong := makeServer(t, 0, http.StatusOK, "maintenance")
+		right := makeServer(t, 20*time.Millisecond, http.StatusOK, "ready")
+
+		got, err := Race(context.Background(), []string{wrong.URL, right.URL},
+			WithSuccess(BodyCheck(func(body []byte) bool { return bytes.Equal(body, []byte("ready")) })))
+
+		assertNoError(t, err)
+		assertWinner(t, got, right.URL)
+	})
+
+	t.Run("every server fails", func(t *testing.T) {
+		a := makeServer(t, 0, http.StatusInternalServerError, "")
+		b := makeServer(t, 0, http.StatusNotFound, "")
+
+		_, err := Race(context.Background(), []string{a.URL, b.URL})
+
+		var unsuccessful UnsuccessfulError
+		if !errors.As(err, &unsuccessful) {
+			t.Fatalf("got %v, want an UnsuccessfulError", err)
+		}
+		if !bytes.Contains([]byte(err.Error()), []byte("404")) || !bytes.Contains([]byte(err.Error()), []byte("500")) {
+			t.Errorf("got %q, want both failures reported", err)
+		}
+	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		slow := makeServer(t, 100*time.Millisecond, http.StatusOK, "")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := Race(ctx, []string{slow.URL})
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("no urls", func(t *testing.T) {
+		_, err := Race(context.Background(), nil)
+		if !errors.Is(err, ErrNoURLs) {
+			t.Errorf("got %v, want %v", err, ErrNoURLs)
+		}
+	})
+
+	t.Run("losers are cancelled", func(t *testing.T) {
+		var cancelled atomic.Int32
+		loser := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+				cancelled.Add(1)
+			case <-time.After(time.Second):
+			}
+		}))
+		defer loser.Close()
+		winner := makeServer(t, 0, http.StatusOK, "")
+
+		start := time.Now()
+		_, err := Race(context.Background(), []string{loser.URL, winner.URL})
+
+		assertNoError(t, err)
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("Race waited %v for the loser", elapsed)
+		}
+		waitFor(t, func() bool { return cancelled.Load() == 1 })
+	})
+}
+
+func TestHedgedRace(t *testing.T) {
+	t.Run("backups are only sent after the delay", func(t *testing.T) {
+		var backupCalls atomic.Int32
+		primary := makeServer(t, 0, http.StatusOK, "")
+		backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			backupCalls.Add(1)
+		}))
+		defer backup.Close()
+
+		got, err := Race(context.Background(), []string{primary.URL, backup.URL}, WithHedgeDelay(50*time.Millisecond))
+
+		assertNoError(t, err)
+		assertWinner(t, got, primary.URL)
+		if backupCalls.Load() != 0 {
+			t.Error("the backup should not have been called")
+		}
+	})
+
+	t.Run("a slow primary is hedged", func(t *testing.T) {
+		primary := makeServer(t, 200*time.Millisecond, http.StatusOK, "")
+		backup := makeServer(t, 0, http.StatusOK, "")
+
+		got, err := Race(context.Background(), []string{primary.URL, backup.URL}, WithHedgeDelay(20*time.Millisecond))
+
+		assertNoError(t, err)
+		assertWinner(t, got, backup.URL)
+	})
+
+	t.Run("a failing primary starts the backup at once", func(t *testing.T) {
+		primary := makeServer(t, 0, http.StatusServiceUnavailable, "")
+		backup := makeServer(t, 0, http.StatusOK, "")
+
+		start := time.Now()
+		got, err := Race(context.Background(), []string{primary.URL, backup.URL}, WithHedgeDelay(time.Second))
+
+		assertNoError(t, err)
+		assertWinner(t, got, backup.URL)
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("waited %v for the hedge delay", elapsed)
+		}
+	})
+
+	t.Run("stats put the historically fastest url first", func(t *testing.T) {
+		first := makeServer(t, 30*time.Millisecond, http.StatusOK, "")
+		second := makeServer(t, 0, http.StatusOK, "")
+		stats := NewStats(10)
+		stats.Record(first.URL, 30*time.Millisecond, true)
+		stats.Record(second.URL, time.Millisecond, true)
+
+		got, err := Race(context.Background(), []string{first.URL, second.URL},
+			WithHedgeDelay(time.Second), WithStats(stats))
+
+		assertNoError(t, err)
+		assertWinner(t, got, second.URL)
+		if got := stats.Get(second.URL).Requests; got != 2 {
+			t.Errorf("got %d recorded requests, want 2", got)
+		}
+	})
+}
+
+func makeServer(t testing.TB, delay time.Duration, status int, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func waitFor(t testing.TB, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func assertWinner(t testing.TB, got Result, want string) {
+	t.Helper()
+	if got.URL != want {
+		t.Errorf("got %q, want %q", got.URL, want)
+	}
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("did not expect an error but got one %v", err)
+	}
+}
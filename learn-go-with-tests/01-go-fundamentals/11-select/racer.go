@@ -1,8 +1,9 @@
 package racer
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"net/http"
 	"time"
 )
 
@@ -21,26 +22,16 @@ func Racer(a, b string) (winner string, err error) {
 }
 
 func ConfigurableRacer(a, b string, timeout time.Duration) (winner string, err error) {
-	select {
-	case <-ping(a):
-		return a, nil
-	case <-ping(b):
-		return b, nil
-	case <-time.After(timeout):
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	res, err := Race(ctx, []string{a, b})
+	if errors.Is(err, context.DeadlineExceeded) {
 		return "", fmt.Errorf("timed out waiting for %s and %s", a, b)
 	}
-}
-
-func ping(url string) chan struct{} {
-	ch := make(chan struct{})
-	go func() {
-		_, err := http.Get(url)
-		if err != nil {
-			return
-		}
-		close(ch)
-	}()
-	return ch
+	if err != nil {
+		return "", err
+	}
+	return res.URL, nil
 }
 
 // func measureResponseTime(url string) time.Duration {
@@ -0,0 +1,122 @@
+package racer
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Stats keeps the last few requests of every url to tell which backend is
+// historically fastest. It is safe for concurrent use.
+type Stats struct {
+	mu      sync.Mutex
+	window  int
+	samples map[string][]sample
+}
+
+type sample struct {
+	latency time.Duration
+	ok      bool
+}
+
+// EndpointStats summarises the samples of one url.
+type EndpointStats struct {
+	Requests    int
+	Failures    int
+	MeanLatency time.Duration // of successful requests only
+}
+
+// SuccessRate is 0 when there are no requests yet.
+func (e EndpointStats) SuccessRate() float64 {
+	if e.Requests == 0 {
+		return 0
+	}
+	return float64(e.Requests-e.Failures) / float64(e.Requests)
+}
+
+// Score is the mean latency in seconds divided by the success rate: lower is better,
+// and a url that never succeeded scores +Inf.
+func (e EndpointStats) Score() float64 {
+	rate := e.SuccessRate()
+	if rate == 0 {
+		return math.Inf(1)
+	}
+	return e.MeanLatency.Seconds() / rate
+}
+
+// NewStats keeps the last window samples per url.
+func NewStats(window int) *Stats {
+	if window < 1 {
+		window = 1
+	}
+	return &Stats{window: window, samples: map[string][]sample{}}
+}
+
+func (s *Stats) Record(url string, latency time.Duration, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	samples := append(s.samples[url], sample{latency, ok})
+	if len(samples) > s.window {
+		samples = samples[len(samples)-s.window:]
+	}
+	s.samples[url] = samples
+}
+
+func (s *Stats) Get(url string) EndpointStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.get(url)
+}
+
+func (s *Stats) get(url string) EndpointStats {
+	var e EndpointStats
+	var total time.Duration
+	for _, smp := range s.samples[url] {
+		e.Requests++
+		if !smp.ok {
+			e.Failures++
+			continue
+		}
+		total += smp.latency
+	}
+	if ok := e.Requests - e.Failures; ok > 0 {
+		e.MeanLatency = total / time.Duration(ok)
+	}
+	return e
+}
+
+// Rank orders urls by Score, best first. Urls without samples keep their
+// relative order after the measured ones.
+func (s *Stats) Rank(urls []string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ranked := append([]string(nil), urls...)
+	scores := make(map[string]float64, len(urls))
+	for _, url := range urls {
+		e := s.get(url)
+		if e.Requests == 0 {
+			scores[url] = math.NaN()
+			continue
+		}
+		scores[url] = e.Score()
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := scores[ranked[i]], scores[ranked[j]]
+		if math.IsNaN(a) || math.IsNaN(b) {
+			return !math.IsNaN(a) && math.IsNaN(b)
+		}
+		return a < b
+	})
+	return ranked
+}
+
+// Fastest returns the best ranked url that has succeeded at least once.
+func (s *Stats) Fastest(urls []string) (string, bool) {
+	for _, url := range s.Rank(urls) {
+		if e := s.Get(url); e.Requests > e.Failures {
+			return url, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,58 @@
+package racer
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	t.Run("rolling window", func(t *testing.T) {
+		stats := NewStats(2)
+		stats.Record("a", 100*time.Millisecond, true)
+		stats.Record("a", 10*time.Millisecond, true)
+		stats.Record("a", 20*time.Millisecond, false)
+
+		got := stats.Get("a")
+		want := EndpointStats{Requests: 2, Failures: 1, MeanLatency: 10 * time.Millisecond}
+		if got != want {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+		if got.SuccessRate() != 0.5 {
+			t.Errorf("got success rate %v, want 0.5", got.SuccessRate())
+		}
+	})
+
+	t.Run("failures make a url score worse", func(t *testing.T) {
+		healthy := EndpointStats{Requests: 2, MeanLatency: 20 * time.Millisecond}
+		flaky := EndpointStats{Requests: 4, Failures: 3, MeanLatency: 10 * time.Millisecond}
+		if healthy.Score() >= flaky.Score() {
+			t.Errorf("healthy %v should beat flaky %v", healthy.Score(), flaky.Score())
+		}
+		if !math.IsInf(EndpointStats{Requests: 1, Failures: 1}.Score(), 1) {
+			t.Error("a url that never succeeded should score +Inf")
+		}
+	})
+
+	t.Run("rank and fastest", func(t *testing.T) {
+		stats := NewStats(10)
+		stats.Record("slow", 50*time.Millisecond, true)
+		stats.Record("fast", 5*time.Millisecond, true)
+		stats.Record("down", time.Millisecond, false)
+
+		got := stats.Rank([]string{"new", "down", "slow", "other", "fast"})
+		want := []string{"fast", "slow", "down", "new", "other"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+
+		fastest, ok := stats.Fastest([]string{"down", "slow", "fast"})
+		if !ok || fastest != "fast" {
+			t.Errorf("got %q, want fast", fastest)
+		}
+		if _, ok := stats.Fastest([]string{"down", "new"}); ok {
+			t.Error("no url has succeeded yet")
+		}
+	})
+}
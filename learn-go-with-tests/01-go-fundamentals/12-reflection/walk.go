@@ -0,0 +1,236 @@
+package reflection
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Node is a leaf value found by Walk.
+type Node struct {
+	Path  string            // JSONPath-like location, e.g. $.Profile.City, $.Items[0], $.Labels["env"], or $.Labels{"env"} for the key itself
+	Value reflect.Value     // settable when the leaf was reached through a pointer
+	Tag   reflect.StructTag // tag of the nearest enclosing struct field
+	Depth int
+	IsKey bool // the leaf is a map key rather than a map value
+}
+
+type ChanMode int
+
+const (
+	ChanSkip     ChanMode = iota // do not touch channels
+	ChanBuffered                 // receive only the values already buffered, never block
+	ChanDrain                    // receive until the channel is closed
+)
+
+type FuncMode int
+
+const (
+	FuncSkip FuncMode = iota // do not call functions
+	FuncCall                 // call functions taking no arguments and walk their results
+)
+
+type options struct {
+	maxDepth    int
+	chanMode    ChanMode
+	funcMode    FuncMode
+	sortMapKeys bool
+}
+
+type Option func(*options)
+
+// WithMaxDepth stops descending below depth n. The root is at depth 0. n < 0 means unlimited.
+func WithMaxDepth(n int) Option {
+	return func(o *options) { o.maxDepth = n }
+}
+
+func WithChanMode(m ChanMode) Option {
+	return func(o *options) { o.chanMode = m }
+}
+
+func WithFuncMode(m FuncMode) Option {
+	return func(o *options) { o.funcMode = m }
+}
+
+// WithSortedMapKeys visits map entries in key order instead of Go's random map order.
+func WithSortedMapKeys() Option {
+	return func(o *options) { o.sortMapKeys = true }
+}
+
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type walker struct {
+	opts    options
+	fn      func(Node) error
+	visited map[visit]bool
+}
+
+// Walk calls fn for every leaf of x (anything that is not a struct, slice, array,
+// map, pointer, interface, channel or function) together with its location.
+// Pass a pointer to x to be able to modify leaves through Node.Value.
+// Values already on the current path are not visited again, so cyclic data terminates.
+// Channels and functions in unexported fields are skipped, as reflect cannot receive
+// from or call them.
+// Walk stops at the first error returned by fn and returns it.
+func Walk(x interface{}, fn func(Node) error, opts ...Option) error {
+	o := options{maxDepth: -1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	w := &walker{opts: o, fn: fn, visited: map[visit]bool{}}
+	return w.walk(reflect.ValueOf(x), "$", "", 0)
+}
+
+func (w *walker) walk(val reflect.Value, path string, tag reflect.StructTag, depth int) error {
+	if !val.IsValid() {
+		return nil
+	}
+	if w.opts.maxDepth >= 0 && depth > w.opts.maxDepth {
+		return nil
+	}
+
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if val.IsNil() {
+			return nil
+		}
+		key := visit{val.Pointer(), val.Type()}
+		if w.visited[key] {
+			return nil
+		}
+		w.visited[key] = true
+		defer delete(w.visited, key)
+	}
+
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return nil
+		}
+		return w.walk(val.Elem(), path, tag, depth)
+	case reflect.Struct:
+		typ := val.Type()
+		for i := 0; i < val.NumField(); i++ {
+			field := typ.Field(i)
+			if err := w.walk(val.Field(i), path+"."+field.Name, field.Tag, depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			if err := w.walk(val.Index(i), path+"["+strconv.Itoa(i)+"]", tag, depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		return w.walkMap(val, path, tag, depth)
+	case reflect.Chan:
+		return w.walkChan(val, path, tag, depth)
+	case reflect.Func:
+		if w.opts.funcMode != FuncCall || val.IsNil() || !val.CanInterface() || val.Type().NumIn() != 0 {
+			return nil
+		}
+		for i, res := range val.Call(nil) {
+			if err := w.walk(res, path+"()["+strconv.Itoa(i)+"]", tag, depth+1); err != nil {
+				return err
+			}
+		}
+	default:
+		return w.fn(Node{Path: path, Value: val, Tag: tag, Depth: depth})
+	}
+	return nil
+}
+
+func (w *walker) walkMap(val reflect.Value, path string, tag reflect.StructTag, depth int) error {
+	keys := val.MapKeys()
+	if w.opts.sortMapKeys {
+		sort.Slice(keys, func(i, j int) bool { return lessKey(keys[i], keys[j]) })
+	}
+	for _, key := range keys {
+		keyPath := path + "[" + formatKey(key) + "]"
+		if isLeaf(key) {
+			if err := w.fn(Node{Path: path + "{" + formatKey(key) + "}", Value: key, Tag: tag, Depth: depth + 1, IsKey: true}); err != nil {
+				return err
+			}
+		}
+		elem := val.MapIndex(key)
+		if val.CanSet() {
+			// map elements are not addressable, so walk a copy and store it back
+			elemCopy := reflect.New(elem.Type()).Elem()
+			elemCopy.Set(elem)
+			elem = elemCopy
+		}
+		if err := w.walk(elem, keyPath, tag, depth+1); err != nil {
+			return err
+		}
+		if val.CanSet() {
+			val.SetMapIndex(key, elem)
+		}
+	}
+	return nil
+}
+
+func (w *walker) walkChan(val reflect.Value, path string, tag reflect.StructTag, depth int) error {
+	if val.IsNil() || !val.CanInterface() || val.Type().ChanDir()&reflect.RecvDir == 0 {
+		return nil
+	}
+	for i := 0; ; i++ {
+		var v reflect.Value
+		var ok bool
+		switch w.opts.chanMode {
+		case ChanBuffered:
+			if val.Len() == 0 {
+				return nil
+			}
+			v, ok = val.TryRecv()
+		case ChanDrain:
+			v, ok = val.Recv()
+		default:
+			return nil
+		}
+		if !ok {
+			return nil
+		}
+		if err := w.walk(v, path+"["+strconv.Itoa(i)+"]", tag, depth+1); err != nil {
+			return err
+		}
+	}
+}
+
+func isLeaf(val reflect.Value) bool {
+	switch val.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map, reflect.Ptr, reflect.Interface, reflect.Chan, reflect.Func:
+		return false
+	}
+	return true
+}
+
+func formatKey(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return strconv.Quote(key.String())
+	}
+	if key.CanInterface() {
+		return fmt.Sprint(key.Interface())
+	}
+	return key.String()
+}
+
+func lessKey(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	}
+	return formatKey(a) < formatKey(b)
+}
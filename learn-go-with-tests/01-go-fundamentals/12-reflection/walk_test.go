@@ -0,0 +1,256 @@
+package reflection
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Tagged struct {
+	Name    string `json:"name"`
+	Age     int    `json:"age"`
+	Profile *Profile
+	Labels  map[string]string
+}
+
+type Loop struct {
+	Name string
+	Next *Loop
+}
+
+func collect(t testing.TB, x interface{}, opts ...Option) map[string]interface{} {
+	t.Helper()
+	got := map[string]interface{}{}
+	err := Walk(x, func(n Node) error {
+		got[n.Path] = n.Value.Interface()
+		return nil
+	}, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return got
+}
+
+func TestWalkPaths(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Input interface{}
+		Want  map[string]interface{}
+	}{
+		{
+			"Struct with every kind of leaf",
+			struct {
+				Name  string
+				Age   int
+				Alive bool
+			}{"Chris", 33, true},
+			map[string]interface{}{"$.Name": "Chris", "$.Age": 33, "$.Alive": true},
+		},
+		{
+			"Nested pointers",
+			&Person{"Chris", Profile{33, "London"}},
+			map[string]interface{}{"$.Name": "Chris", "$.Profile.Age": 33, "$.Profile.City": "London"},
+		},
+		{
+			"Slices",
+			[]Profile{{33, "London"}},
+			map[string]interface{}{"$[0].Age": 33, "$[0].City": "London"},
+		},
+		{
+			"Maps visit keys and values",
+			map[string]int{"Foo": 1},
+			map[string]interface{}{`${"Foo"}`: "Foo", `$["Foo"]`: 1},
+		},
+		{
+			"Interfaces and nil values",
+			[]interface{}{"a", nil, (*Profile)(nil)},
+			map[string]interface{}{"$[0]": "a"},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Name, func(t *testing.T) {
+			got := collect(t, test.Input)
+			if !reflect.DeepEqual(got, test.Want) {
+				t.Errorf("got %v, want %v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestWalkOptions(t *testing.T) {
+	t.Run("map keys are reported", func(t *testing.T) {
+		var keys []string
+		Walk(map[string]int{"b": 2, "a": 1}, func(n Node) error {
+			if n.IsKey {
+				keys = append(keys, n.Value.String())
+			}
+			return nil
+		}, WithSortedMapKeys())
+		want := []string{"a", "b"}
+		if !reflect.DeepEqual(keys, want) {
+			t.Errorf("got %v, want %v", keys, want)
+		}
+	})
+
+	t.Run("sorted map keys give a deterministic order", func(t *testing.T) {
+		var got []string
+		Walk(map[int]string{3: "c", 1: "a", 2: "b"}, func(n Node) error {
+			if !n.IsKey {
+				got = append(got, n.Path)
+			}
+			return nil
+		}, WithSortedMapKeys())
+		want := []string{"$[1]", "$[2]", "$[3]"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("struct tags", func(t *testing.T) {
+		tags := map[string]string{}
+		Walk(Tagged{Name: "Chris", Age: 33}, func(n Node) error {
+			tags[n.Path] = n.Tag.Get("json")
+			return nil
+		})
+		want := map[string]string{"$.Name": "name", "$.Age": "age"}
+		if !reflect.DeepEqual(tags, want) {
+			t.Errorf("got %v, want %v", tags, want)
+		}
+	})
+
+	t.Run("mutates leaves through a pointer", func(t *testing.T) {
+		x := Tagged{Name: "chris", Profile: &Profile{33, "london"}, Labels: map[string]string{"city": "berlin"}}
+		err := Walk(&x, func(n Node) error {
+			if n.Value.Kind() == reflect.String && n.Value.CanSet() {
+				n.Value.SetString(strings.ToUpper(n.Value.String()))
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := Tagged{Name: "CHRIS", Profile: &Profile{33, "LONDON"}, Labels: map[string]string{"city": "BERLIN"}}
+		if !reflect.DeepEqual(x, want) {
+			t.Errorf("got %+v, want %+v", x, want)
+		}
+	})
+
+	t.Run("values passed by value are not settable", func(t *testing.T) {
+		Walk(Person{"Chris", Profile{33, "London"}}, func(n Node) error {
+			if n.Value.CanSet() {
+				t.Errorf("%s should not be settable", n.Path)
+			}
+			return nil
+		})
+	})
+
+	t.Run("cycles terminate", func(t *testing.T) {
+		a := &Loop{Name: "a"}
+		b := &Loop{Name: "b", Next: a}
+		a.Next = b
+		got := collect(t, a)
+		want := map[string]interface{}{"$.Name": "a", "$.Next.Name": "b"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("shared pointers are visited at every location", func(t *testing.T) {
+		p := &Profile{33, "London"}
+		got := collect(t, []*Profile{p, p})
+		if len(got) != 4 {
+			t.Errorf("got %v, want 4 leaves", got)
+		}
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		got := collect(t, Person{"Chris", Profile{33, "London"}}, WithMaxDepth(1))
+		want := map[string]interface{}{"$.Name": "Chris"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("channels are skipped by default", func(t *testing.T) {
+		ch := make(chan string) // never closed, so draining would block forever
+		got := collect(t, ch)
+		if len(got) != 0 {
+			t.Errorf("got %v, want nothing", got)
+		}
+	})
+
+	t.Run("buffered channel values without blocking", func(t *testing.T) {
+		ch := make(chan string, 2)
+		ch <- "Berlin"
+		got := collect(t, ch, WithChanMode(ChanBuffered))
+		want := map[string]interface{}{"$[0]": "Berlin"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("drain channels until closed", func(t *testing.T) {
+		ch := make(chan Profile)
+		go func() {
+			ch <- Profile{33, "Berlin"}
+			ch <- Profile{34, "Katowice"}
+			close(ch)
+		}()
+		got := collect(t, ch, WithChanMode(ChanDrain))
+		want := map[string]interface{}{"$[0].Age": 33, "$[0].City": "Berlin", "$[1].Age": 34, "$[1].City": "Katowice"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("functions", func(t *testing.T) {
+		called := false
+		fns := []interface{}{
+			func() string { called = true; return "Berlin" },
+			func(s string) string { t.Error("functions with arguments must not be called"); return s },
+		}
+		if got := collect(t, fns); len(got) != 0 || called {
+			t.Errorf("functions should be skipped by default, got %v", got)
+		}
+		got := collect(t, fns, WithFuncMode(FuncCall))
+		want := map[string]interface{}{"$[0]()[0]": "Berlin"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("unexported channels and functions are skipped", func(t *testing.T) {
+		ch := make(chan string, 1)
+		ch <- "Berlin"
+		close(ch)
+		x := struct {
+			ch chan string
+			fn func() string
+		}{ch, func() string { t.Error("unexported functions must not be called"); return "Berlin" }}
+		for _, opts := range [][]Option{
+			{WithChanMode(ChanBuffered), WithFuncMode(FuncCall)},
+			{WithChanMode(ChanDrain), WithFuncMode(FuncCall)},
+		} {
+			if got := collect(t, x, opts...); len(got) != 0 {
+				t.Errorf("got %v, want nothing", got)
+			}
+		}
+		if len(ch) != 1 {
+			t.Error("the unexported channel was received from")
+		}
+	})
+
+	t.Run("stops at the first error", func(t *testing.T) {
+		errStop := errors.New("stop")
+		calls := 0
+		err := Walk([]string{"a", "b", "c"}, func(n Node) error {
+			calls++
+			return errStop
+		})
+		if !errors.Is(err, errStop) || calls != 1 {
+			t.Errorf("got err %v after %d calls, want %v after 1 call", err, calls, errStop)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package counter
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Counter is safe for concurrent use.
+type Counter interface {
+	Add(delta int64)
+	Value() int64
+	Reset()
+	// Flush returns the value and resets it to zero in one step: every Add is
+	// counted by exactly one Flush, even while others are adding.
+	Flush() int64
+}
+
+var (
+	_ Counter = (*Mutex)(nil)
+	_ Counter = (*Atomic)(nil)
+	_ Counter = (*Sharded)(nil)
+)
+
+// Mutex guards a plain int64 with a sync.Mutex.
+type Mutex struct {
+	mu    sync.Mutex
+	value int64
+}
+
+func NewMutex() *Mutex {
+	return &Mutex{}
+}
+
+func (c *Mutex) Add(delta int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.value += delta
+}
+
+func (c *Mutex) Value() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value
+}
+
+func (c *Mutex) Reset() {
+	c.Flush()
+}
+
+func (c *Mutex) Flush() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v := c.value
+	c.value = 0
+	return v
+}
+
+// Atomic is a single atomic.Int64, lock-free but contended on one cache line.
+type Atomic struct {
+	value atomic.Int64
+}
+
+func NewAtomic() *Atomic {
+	return &Atomic{}
+}
+
+func (c *Atomic) Add(delta int64) { c.value.Add(delta) }
+func (c *Atomic) Value() int64    { return c.value.Load() }
+func (c *Atomic) Reset()          { c.value.Store(0) }
+func (c *Atomic) Flush() int64    { return c.value.Swap(0) }
+
+// cacheLine keeps shards from sharing a cache line (false sharing).
+const cacheLine = 64
+
+type shard struct {
+	value atomic.Int64
+	_     [cacheLine - 8]byte
+}
+
+// Sharded spreads Add over several atomics so goroutines on different CPUs
+// rarely touch the same cache line. Go does not expose the current CPU, so the
+// shard is picked at random. Adds are cheap under contention, Value and Flush
+// cost one load per shard, and Value is not a point-in-time snapshot while
+// others are adding.
+type Sharded struct {
+	shards []shard
+	mask   uint64
+}
+
+// NewSharded returns a counter with at least n shards, GOMAXPROCS when n < 1.
+func NewSharded(n int) *Sharded {
+	if n < 1 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	size := 1
+	for size < n {
+		size <<= 1
+	}
+	return &Sharded{shards: make([]shard, size), mask: uint64(size - 1)}
+}
+
+func (c *Sharded) Add(delta int64) {
+	c.shards[rand.Uint64()&c.mask].value.Add(delta)
+}
+
+func (c *Sharded) Value() int64 {
+	var sum int64
+	for i := range c.shards {
+		sum += c.shards[i].value.Load()
+	}
+	return sum
+}
+
+func (c *Sharded) Reset() {
+	c.Flush()
+}
+
+func (c *Sharded) Flush() int64 {
+	var sum int64
+	for i := range c.shards {
+		sum += c.shards[i].value.Swap(0)
+	}
+	return sum
+}
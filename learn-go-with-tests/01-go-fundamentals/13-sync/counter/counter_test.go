@@ -0,0 +1,133 @@
+package counter
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+var implementations = []struct {
+	name string
+	new  func() Counter
+}{
+	{"mutex", func() Counter { return NewMutex() }},
+	{"atomic", func() Counter { return NewAtomic() }},
+	{"sharded", func() Counter { return NewSharded(0) }},
+}
+
+func TestCounter(t *testing.T) {
+	for _, impl := range implementations {
+		t.Run(impl.name, func(t *testing.T) {
+			counter := impl.new()
+			counter.Add(3)
+			counter.Add(-1)
+			counter.Add(10)
+			assertValue(t, counter, 12)
+
+			if got := counter.Flush(); got != 12 {
+				t.Errorf("Flush returned %d, want 12", got)
+			}
+			assertValue(t, counter, 0)
+
+			counter.Add(5)
+			counter.Reset()
+			assertValue(t, counter, 0)
+		})
+	}
+}
+
+// TestConcurrentFlush is meant to be run with -race: adders and a flusher run
+// at the same time and every Add must be counted by exactly one Flush.
+func TestConcurrentFlush(t *testing.T) {
+	const goroutines, adds = 16, 2000
+
+	for _, impl := range implementations {
+		t.Run(impl.name, func(t *testing.T) {
+			counter := impl.new()
+			var flushed atomic.Int64
+			stop := make(chan struct{})
+			flusherDone := make(chan struct{})
+			go func() {
+				defer close(flusherDone)
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+						flushed.Add(counter.Flush())
+						counter.Value()
+					}
+				}
+			}()
+
+			var wg sync.WaitGroup
+			wg.Add(goroutines)
+			for range goroutines {
+				go func() {
+					defer wg.Done()
+					for range adds {
+						counter.Add(1)
+					}
+				}()
+			}
+			wg.Wait()
+			close(stop)
+			<-flusherDone
+
+			if got := flushed.Load() + counter.Flush(); got != goroutines*adds {
+				t.Errorf("got %d adds, want %d", got, goroutines*adds)
+			}
+		})
+	}
+}
+
+func TestNewShardedRoundsUp(t *testing.T) {
+	if got := len(NewSharded(5).shards); got != 8 {
+		t.Errorf("got %d shards, want 8", got)
+	}
+}
+
+func assertValue(t testing.TB, counter Counter, want int64) {
+	t.Helper()
+	if got := counter.Value(); got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+}
+
+// BenchmarkAdd compares the implementations as more goroutines per CPU add at
+// the same time, e.g. go test -bench . -cpu 1,4,8
+func BenchmarkAdd(b *testing.B) {
+	for _, impl := range implementations {
+		for _, parallelism := range []int{1, 4, 16} {
+			b.Run(fmt.Sprintf("%s/goroutines-per-cpu-%d", impl.name, parallelism), func(b *testing.B) {
+				counter := impl.new()
+				b.SetParallelism(parallelism)
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						counter.Add(1)
+					}
+				})
+			})
+		}
+	}
+}
+
+// BenchmarkMixed reads the value once every 100 adds, the sharded counter's
+// worst case.
+func BenchmarkMixed(b *testing.B) {
+	for _, impl := range implementations {
+		b.Run(impl.name, func(b *testing.B) {
+			counter := impl.new()
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					if i%100 == 0 {
+						counter.Value()
+					} else {
+						counter.Add(1)
+					}
+				}
+			})
+		})
+	}
+}
@@ -14,5 +14,7 @@ func (c *Counter) Inc() {
 }
 
 func (c *Counter) Value() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.value
 }
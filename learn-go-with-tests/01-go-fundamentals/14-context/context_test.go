@@ -24,8 +24,11 @@ type SpyStore struct {
 
 func (s *SpyStore) Fetch(ctx context.Context) (string, error) {
 	data := make(chan string, 1)
+	stopped := make(chan struct{})
 
 	go func() {
+		// t.Log must not run after the test finished
+		defer close(stopped)
 		var result string
 		// intentionally make the process slow
 		for _, c := range s.response {
@@ -42,6 +45,7 @@ func (s *SpyStore) Fetch(ctx context.Context) (string, error) {
 	}()
 	select {
 	case <-ctx.Done():
+		<-stopped
 		return "", ctx.Err()
 	case res := <-data:
 		return res, nil
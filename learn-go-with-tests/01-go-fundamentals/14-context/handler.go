@@ -0,0 +1,160 @@
+package context
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// StatusClientClosedRequest is nginx's non-standard status for a client that
+// went away before the response was ready. The client never sees it, it is
+// for logs and metrics.
+const StatusClientClosedRequest = 499
+
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// RequestID returns the id Handler and StreamHandler put on the request
+// context, so stores can use it in their own logs.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// StreamStore sends its data in chunks and stops as soon as ctx is done or
+// send fails.
+type StreamStore interface {
+	Stream(ctx context.Context, send func(chunk string) error) error
+}
+
+type handlerOptions struct {
+	timeout time.Duration
+	logger  *log.Logger
+}
+
+type HandlerOption func(*handlerOptions)
+
+// WithTimeout bounds every request, 0 (the default) keeps only the client's
+// own cancellation.
+func WithTimeout(d time.Duration) HandlerOption {
+	return func(o *handlerOptions) { o.timeout = d }
+}
+
+func WithLogger(l *log.Logger) HandlerOption {
+	return func(o *handlerOptions) { o.logger = l }
+}
+
+// Handler serves store.Fetch. Unlike Server it always answers: 504 when the
+// deadline passed, 499 when the client cancelled and 500 for other errors.
+func Handler(store Store, opts ...HandlerOption) http.Handler {
+	o := newHandlerOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := o.context(w, r)
+		defer cancel()
+
+		data, err := store.Fetch(ctx)
+		if err != nil {
+			o.fail(w, r, ctx, err)
+			return
+		}
+		fmt.Fprint(w, data)
+	})
+}
+
+// StreamHandler writes and flushes every chunk of store.Stream as it comes.
+// The store's context is cancelled when the client goes away or a write fails,
+// so it stops producing. Errors after the first chunk can only be logged.
+func StreamHandler(store StreamStore, opts ...HandlerOption) http.Handler {
+	o := newHandlerOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := o.context(w, r)
+		defer cancel()
+
+		flusher, _ := w.(http.Flusher)
+		started := false
+		err := store.Stream(ctx, func(chunk string) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			started = true
+			if _, err := fmt.Fprint(w, chunk); err != nil {
+				cancel()
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			return nil
+		})
+		if err == nil {
+			return
+		}
+		if started {
+			o.logger.Printf("request %s: %s %s: stream stopped: %v", RequestID(ctx), r.Method, r.URL.Path, err)
+			return
+		}
+		o.fail(w, r, ctx, err)
+	})
+}
+
+func newHandlerOptions(opts []HandlerOption) handlerOptions {
+	o := handlerOptions{logger: log.Default()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// context derives the store's context from the request: the request id (taken
+// from the X-Request-ID header or generated) and the deadline.
+func (o handlerOptions) context(w http.ResponseWriter, r *http.Request) (context.Context, context.CancelFunc) {
+	id := r.Header.Get(RequestIDHeader)
+	if id == "" {
+		id = newRequestID()
+	}
+	// a spy writer may not have headers
+	if h := w.Header(); h != nil {
+		h.Set(RequestIDHeader, id)
+	}
+	ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+	if o.timeout > 0 {
+		return context.WithTimeout(ctx, o.timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+func (o handlerOptions) fail(w http.ResponseWriter, r *http.Request, ctx context.Context, err error) {
+	status := statusFor(r.Context(), err)
+	o.logger.Printf("request %s: %s %s: %d: %v", RequestID(ctx), r.Method, r.URL.Path, status, err)
+	if status == StatusClientClosedRequest {
+		// nobody reads the body, the status is still seen by wrapping middleware
+		w.WriteHeader(status)
+		return
+	}
+	http.Error(w, http.StatusText(status), status)
+}
+
+// statusFor maps err to a status. A cancelled request context means the client
+// went away even if the store reported something else.
+func statusFor(requestCtx context.Context, err error) int {
+	switch {
+	case errors.Is(requestCtx.Err(), context.Canceled), errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,211 @@
+package context
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ErrStore struct {
+	err error
+}
+
+func (s ErrStore) Fetch(context.Context) (string, error) {
+	return "", s.err
+}
+
+func TestHandler(t *testing.T) {
+	data := "hello, world"
+
+	t.Run("returns data from store with a request id", func(t *testing.T) {
+		store := &SpyStore{response: data, t: t}
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set(RequestIDHeader, "abc")
+		response := httptest.NewRecorder()
+
+		Handler(store).ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		if response.Body.String() != data {
+			t.Errorf(`got "%s", want "%s"`, response.Body.String(), data)
+		}
+		if got := response.Header().Get(RequestIDHeader); got != "abc" {
+			t.Errorf("got request id %q, want %q", got, "abc")
+		}
+	})
+
+	t.Run("504 when the store is slower than the timeout", func(t *testing.T) {
+		store := &SpyStore{response: data, t: t}
+		logs := &bytes.Buffer{}
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set(RequestIDHeader, "slow-one")
+		response := httptest.NewRecorder()
+
+		Handler(store, WithTimeout(20*time.Millisecond), WithLogger(log.New(logs, "", 0))).ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusGatewayTimeout)
+		if !strings.Contains(logs.String(), "request slow-one: GET /: 504: context deadline exceeded") {
+			t.Errorf("got log %q", logs.String())
+		}
+	})
+
+	t.Run("499 when the client cancels", func(t *testing.T) {
+		store := &SpyStore{response: data, t: t}
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		cancellingCtx, cancel := context.WithCancel(request.Context())
+		time.AfterFunc(5*time.Millisecond, cancel)
+		request = request.WithContext(cancellingCtx)
+		response := httptest.NewRecorder()
+
+		Handler(store, WithTimeout(time.Second), withDiscardLogger).ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, StatusClientClosedRequest)
+		if response.Body.Len() != 0 {
+			t.Errorf("got body %q for a client that went away", response.Body.String())
+		}
+	})
+
+	t.Run("500 for other errors", func(t *testing.T) {
+		response := httptest.NewRecorder()
+
+		Handler(ErrStore{errors.New("disk on fire")}, withDiscardLogger).ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assertStatus(t, response.Code, http.StatusInternalServerError)
+	})
+
+	t.Run("the store sees the request id", func(t *testing.T) {
+		var got string
+		store := storeFunc(func(ctx context.Context) (string, error) {
+			got = RequestID(ctx)
+			return "", nil
+		})
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set(RequestIDHeader, "abc")
+
+		Handler(store).ServeHTTP(httptest.NewRecorder(), request)
+
+		if got != "abc" {
+			t.Errorf("got %q, want %q", got, "abc")
+		}
+	})
+}
+
+type storeFunc func(ctx context.Context) (string, error)
+
+func (f storeFunc) Fetch(ctx context.Context) (string, error) { return f(ctx) }
+
+// SpyStreamStore sends a tick every millisecond until it is stopped.
+type SpyStreamStore struct {
+	sent      int
+	stoppedBy error
+}
+
+func (s *SpyStreamStore) Stream(ctx context.Context, send func(string) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			s.stoppedBy = ctx.Err()
+			return s.stoppedBy
+		case <-time.After(time.Millisecond):
+			if err := send("tick\n"); err != nil {
+				s.stoppedBy = err
+				return err
+			}
+			s.sent++
+		}
+	}
+}
+
+// SpyStreamResponseWriter accepts a few writes and then behaves like a
+// connection whose client went away.
+type SpyStreamResponseWriter struct {
+	SpyResponseWriter
+	writes    int
+	failAfter int
+	flushes   int
+}
+
+func (s *SpyStreamResponseWriter) Write(p []byte) (int, error) {
+	s.written = true
+	if s.writes == s.failAfter {
+		return 0, errors.New("broken pipe")
+	}
+	s.writes++
+	return len(p), nil
+}
+
+func (s *SpyStreamResponseWriter) Flush() {
+	s.flushes++
+}
+
+func TestStreamHandler(t *testing.T) {
+	t.Run("stops the store when a write fails", func(t *testing.T) {
+		store := &SpyStreamStore{}
+		response := &SpyStreamResponseWriter{failAfter: 3}
+
+		StreamHandler(store, withDiscardLogger).ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if response.writes != 3 || response.flushes != 3 {
+			t.Errorf("got %d writes and %d flushes, want 3 of each", response.writes, response.flushes)
+		}
+		if store.sent != 3 {
+			t.Errorf("store sent %d chunks, want 3", store.sent)
+		}
+	})
+
+	t.Run("stops the store promptly when the client goes away", func(t *testing.T) {
+		store := &SpyStreamStore{}
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		cancellingCtx, cancel := context.WithCancel(request.Context())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		request = request.WithContext(cancellingCtx)
+		response := &SpyStreamResponseWriter{failAfter: -1}
+
+		done := make(chan struct{})
+		go func() {
+			StreamHandler(store, withDiscardLogger).ServeHTTP(response, request)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("handler kept streaming after the client went away")
+		}
+		if !errors.Is(store.stoppedBy, context.Canceled) {
+			t.Errorf("store stopped by %v, want %v", store.stoppedBy, context.Canceled)
+		}
+	})
+
+	t.Run("maps errors before the first chunk", func(t *testing.T) {
+		store := streamFunc(func(ctx context.Context, send func(string) error) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		response := httptest.NewRecorder()
+
+		StreamHandler(store, WithTimeout(5*time.Millisecond), withDiscardLogger).ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assertStatus(t, response.Code, http.StatusGatewayTimeout)
+	})
+}
+
+type streamFunc func(ctx context.Context, send func(string) error) error
+
+func (f streamFunc) Stream(ctx context.Context, send func(string) error) error { return f(ctx, send) }
+
+var withDiscardLogger = WithLogger(log.New(io.Discard, "", 0))
+
+func assertStatus(t testing.TB, got, want int) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got status %d, want %d", got, want)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"tmp/learn-go-with-tests/01-go-fundamentals/09-mocking/timer"
 	"tmp/learn-go-with-tests/01-go-fundamentals/16-math"
 )
 
@@ -170,6 +171,35 @@ func TestSVGWriterHourHand(t *testing.T) {
 	}
 }
 
+func TestSVGWriterWithFakeClock(t *testing.T) {
+	clock := timer.NewFake(simpleTime(0, 0, 0))
+	var frames []bytes.Buffer
+	clock.AfterFunc(15*time.Second, func() {
+		frames = append(frames, bytes.Buffer{})
+		clockface.SVGWriter(&frames[len(frames)-1], clock.Now())
+	})
+	clock.AfterFunc(30*time.Second, func() {
+		frames = append(frames, bytes.Buffer{})
+		clockface.SVGWriter(&frames[len(frames)-1], clock.Now())
+	})
+
+	clock.Advance(time.Minute)
+
+	want := []Line{{150, 150, 240, 150}, {150, 150, 150, 240}}
+	if len(frames) != len(want) {
+		t.Fatalf("got %d frames, want %d", len(frames), len(want))
+	}
+	for i, b := range frames {
+		svg := SVG{}
+		if err := xml.Unmarshal(b.Bytes(), &svg); err != nil {
+			t.Fatal(err)
+		}
+		if !containsLine(want[i], svg.Line) {
+			t.Errorf("Expected to find the second hand line %+v, in the SVG lines %+v", want[i], svg.Line)
+		}
+	}
+}
+
 func containsLine(l Line, ls []Line) bool {
 	for _, line := range ls {
 		if line == l {
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"time"
+
+	"tmp/learn-go-with-tests/01-go-fundamentals/09-mocking/timer"
 )
 
 type BlindAlerter interface {
@@ -16,7 +18,14 @@ func (a BlindAlerterFunc) ScheduleAlertAt(duration time.Duration, amount int, to
 }
 
 func Alerter(duration time.Duration, amount int, to io.Writer) {
-	time.AfterFunc(duration, func() {
-		fmt.Fprintf(to, "Blind is now %d\n", amount)
-	})
+	ClockAlerter(timer.Real)(duration, amount, to)
+}
+
+// ClockAlerter is Alerter scheduling on clock, so tests can use a timer.Fake.
+func ClockAlerter(clock timer.Clock) BlindAlerterFunc {
+	return func(duration time.Duration, amount int, to io.Writer) {
+		clock.AfterFunc(duration, func() {
+			fmt.Fprintf(to, "Blind is now %d\n", amount)
+		})
+	}
 }
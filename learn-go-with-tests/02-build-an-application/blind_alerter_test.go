@@ -0,0 +1,34 @@
+package poker_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"tmp/learn-go-with-tests/01-go-fundamentals/09-mocking/timer"
+	"tmp/learn-go-with-tests/02-build-an-application"
+)
+
+func TestClockAlerter(t *testing.T) {
+	clock := timer.NewFake(time.Date(2024, time.January, 1, 20, 0, 0, 0, time.UTC))
+	game := poker.NewTexasHoldem(poker.ClockAlerter(clock), dummyPlayerStore)
+	out := &bytes.Buffer{}
+
+	game.Start(5, out)
+
+	clock.Advance(0)
+	assertAlerts(t, out, "Blind is now 100\n")
+
+	clock.Advance(9 * time.Minute)
+	assertAlerts(t, out, "Blind is now 100\n")
+
+	clock.Advance(21 * time.Minute)
+	assertAlerts(t, out, "Blind is now 100\nBlind is now 200\nBlind is now 300\nBlind is now 400\n")
+}
+
+func assertAlerts(t testing.TB, out *bytes.Buffer, want string) {
+	t.Helper()
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"os"
 	"tmp/learn-go-with-tests/02-build-an-application"
+	"tmp/pragmatic-cases/health"
 )
 
 const dbFileName = "game.db.json"
@@ -22,5 +25,14 @@ func main() {
 		log.Fatalf("problem creating player server %v", err)
 	}
 	// server := NewPlayerServer(NewInMemoryPlayerStore())
-	log.Fatal(http.ListenAndServe(":5000", server))
+
+	h := health.New()
+	h.Readiness.Register("store", health.CheckFunc(func(context.Context) error {
+		_, err := os.Stat(dbFileName)
+		return err
+	}))
+	mux := http.NewServeMux()
+	mux.Handle("/", server)
+	h.Mount(mux)
+	log.Fatal(http.ListenAndServe(":5000", mux))
 }
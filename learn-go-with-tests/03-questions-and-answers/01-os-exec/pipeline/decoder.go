@@ -0,0 +1,88 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrUnknownFormat = errors.New("pipeline: unknown format")
+
+// Decoder reads one value from r into v, the way xml.NewDecoder(r).Decode(v)
+// does.
+type Decoder func(r io.Reader, v any) error
+
+// DecodeError is a payload that does not parse in its format.
+type DecodeError struct {
+	Format string
+	Err    error
+}
+
+func (d *DecodeError) Error() string {
+	return fmt.Sprintf("cannot decode %s payload: %v", d.Format, d.Err)
+}
+
+func (d *DecodeError) Unwrap() error { return d.Err }
+
+// Registry maps format names ("xml", "json", "yaml") to decoders.
+type Registry struct {
+	mu       sync.RWMutex
+	decoders map[string]Decoder
+}
+
+// NewRegistry returns a registry knowing xml, json and yaml (also as "yml").
+func NewRegistry() *Registry {
+	r := &Registry{decoders: map[string]Decoder{}}
+	r.Register("xml", func(src io.Reader, v any) error { return xml.NewDecoder(src).Decode(v) })
+	r.Register("json", func(src io.Reader, v any) error { return json.NewDecoder(src).Decode(v) })
+	yamlDecoder := func(src io.Reader, v any) error { return yaml.NewDecoder(src).Decode(v) }
+	r.Register("yaml", yamlDecoder)
+	r.Register("yml", yamlDecoder)
+	return r
+}
+
+// Register adds or replaces the decoder of format. Names are case-insensitive.
+func (r *Registry) Register(format string, d Decoder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.decoders[strings.ToLower(format)] = d
+}
+
+func (r *Registry) Formats() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	formats := make([]string, 0, len(r.decoders))
+	for f := range r.decoders {
+		formats = append(formats, f)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Decode decodes src with the decoder of format, wrapping failures in a
+// *DecodeError.
+func (r *Registry) Decode(format string, src io.Reader, v any) error {
+	r.mu.RLock()
+	d, ok := r.decoders[strings.ToLower(format)]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w %q, known formats: %s", ErrUnknownFormat, format, strings.Join(r.Formats(), ", "))
+	}
+	if err := d(src, v); err != nil {
+		return &DecodeError{Format: format, Err: err}
+	}
+	return nil
+}
+
+// FormatOf returns the format of a file from its extension: "msg.xml" is "xml".
+func FormatOf(path string) string {
+	return strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+}
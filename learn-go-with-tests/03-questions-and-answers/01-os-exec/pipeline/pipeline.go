@@ -0,0 +1,52 @@
+package pipeline
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+type Payload struct {
+	Message string `xml:"message" json:"message" yaml:"message"`
+}
+
+// Pipeline turns the output of a command into an upper-cased message, like
+// GetData(getXMLFromCommand()) but returning errors instead of exiting.
+type Pipeline struct {
+	Runner   CommandRunner
+	Decoders *Registry
+}
+
+func New(runner CommandRunner) *Pipeline {
+	return &Pipeline{Runner: runner, Decoders: NewRegistry()}
+}
+
+// GetData decodes a payload of format from data.
+func (p *Pipeline) GetData(data io.Reader, format string) (string, error) {
+	var payload Payload
+	if err := p.Decoders.Decode(format, data, &payload); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(payload.Message), nil
+}
+
+// GetDataFromCommand decodes the stdout of the command while it runs. A
+// failing command is reported as a *CommandError even when its partial output
+// could not be decoded, since that is the cause.
+func (p *Pipeline) GetDataFromCommand(ctx context.Context, format, name string, args ...string) (string, error) {
+	stdout, err := p.Runner.Run(ctx, name, args...)
+	if err != nil {
+		return "", err
+	}
+	message, decodeErr := p.GetData(stdout, format)
+	if err := stdout.Close(); err != nil {
+		return "", err
+	}
+	return message, decodeErr
+}
+
+// GetDataFromFile is the original example: cat the file and decode it in the
+// format given by its extension.
+func (p *Pipeline) GetDataFromFile(ctx context.Context, path string) (string, error) {
+	return p.GetDataFromCommand(ctx, FormatOf(path), "cat", path)
+}
@@ -0,0 +1,128 @@
+package pipeline
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetData(t *testing.T) {
+	p := New(&FakeRunner{})
+	cases := []struct {
+		format string
+		input  string
+	}{
+		{"xml", "<payload>\n    <message>Cats are the best animal</message>\n</payload>"},
+		{"json", `{"message": "Cats are the best animal"}`},
+		{"yaml", "message: Cats are the best animal\n"},
+		{"YML", "message: Cats are the best animal\n"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.format, func(t *testing.T) {
+			got, err := p.GetData(strings.NewReader(c.input), c.format)
+			assertNoError(t, err)
+			assertMessage(t, got, "CATS ARE THE BEST ANIMAL")
+		})
+	}
+
+	t.Run("bad payloads are errors, not exits", func(t *testing.T) {
+		_, err := p.GetData(strings.NewReader("<payload><message>"), "xml")
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Format != "xml" {
+			t.Errorf("got %v, want a DecodeError", err)
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := p.GetData(strings.NewReader(""), "toml")
+
+		if !errors.Is(err, ErrUnknownFormat) {
+			t.Errorf("got %v, want %v", err, ErrUnknownFormat)
+		}
+	})
+
+	t.Run("registered formats", func(t *testing.T) {
+		p := New(&FakeRunner{})
+		// a plain text format: the first line is the message
+		p.Decoders.Register("txt", func(r io.Reader, v any) error {
+			line, err := bufio.NewReader(r).ReadString('\n')
+			if err != nil && err != io.EOF {
+				return err
+			}
+			v.(*Payload).Message = strings.TrimSpace(line)
+			return nil
+		})
+
+		got, err := p.GetData(strings.NewReader("hello\nworld"), "txt")
+
+		assertNoError(t, err)
+		assertMessage(t, got, "HELLO")
+		if want := []string{"json", "txt", "xml", "yaml", "yml"}; !reflect.DeepEqual(p.Decoders.Formats(), want) {
+			t.Errorf("got formats %v, want %v", p.Decoders.Formats(), want)
+		}
+	})
+}
+
+func TestGetDataFromCommand(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("decodes the output of the command", func(t *testing.T) {
+		runner := &FakeRunner{Commands: map[string]FakeCommand{
+			"cat msg.json": {Stdout: `{"message": "Happy New Year!"}`},
+		}}
+
+		got, err := New(runner).GetDataFromFile(ctx, "msg.json")
+
+		assertNoError(t, err)
+		assertMessage(t, got, "HAPPY NEW YEAR!")
+		if want := []string{"cat msg.json"}; !reflect.DeepEqual(runner.Calls, want) {
+			t.Errorf("got calls %v, want %v", runner.Calls, want)
+		}
+	})
+
+	t.Run("a failing command wins over its partial output", func(t *testing.T) {
+		runner := &FakeRunner{Commands: map[string]FakeCommand{
+			"cat msg.xml": {Stdout: "<payload>", Stderr: "cat: read error", ExitCode: 1},
+		}}
+
+		_, err := New(runner).GetDataFromFile(ctx, "msg.xml")
+
+		var cmdErr *CommandError
+		if !errors.As(err, &cmdErr) {
+			t.Fatalf("got %v, want a CommandError", err)
+		}
+		want := CommandError{Command: "cat msg.xml", ExitCode: 1, Stderr: "cat: read error"}
+		if cmdErr.Command != want.Command || cmdErr.ExitCode != want.ExitCode || cmdErr.Stderr != want.Stderr {
+			t.Errorf("got %+v, want %+v", *cmdErr, want)
+		}
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		_, err := New(&FakeRunner{}).GetDataFromCommand(ctx, "xml", "curl", "https://example.com")
+
+		if !errors.Is(err, exec.ErrNotFound) {
+			t.Errorf("got %v, want %v", err, exec.ErrNotFound)
+		}
+	})
+}
+
+func assertMessage(t testing.TB, got, want string) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,180 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// CommandRunner starts a command and streams its stdout. Closing the stream
+// waits for the command and returns a *CommandError if it failed.
+type CommandRunner interface {
+	Run(ctx context.Context, name string, args ...string) (io.ReadCloser, error)
+}
+
+// CommandError is a command that could not run or exited non-zero.
+type CommandError struct {
+	Command  string
+	ExitCode int    // -1 when the command did not exit normally
+	Stderr   string // the end of stderr, at most maxStderr bytes
+	Err      error
+}
+
+func (c *CommandError) Error() string {
+	msg := fmt.Sprintf("command %q failed with exit code %d", c.Command, c.ExitCode)
+	if c.Stderr != "" {
+		msg += ": " + c.Stderr
+	}
+	return msg
+}
+
+func (c *CommandError) Unwrap() error { return c.Err }
+
+const (
+	maxStderr    = 4 << 10
+	maxDrain     = 1 << 20
+	drainTimeout = time.Second
+)
+
+// ExecRunner runs commands with exec.CommandContext. Cancelling ctx kills the
+// command.
+type ExecRunner struct {
+	Dir string
+	Env []string // nil means the environment of this process
+}
+
+func (e ExecRunner) Run(ctx context.Context, name string, args ...string) (io.ReadCloser, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = e.Dir
+	cmd.Env = e.Env
+	// children of a killed command may keep stderr open
+	cmd.WaitDelay = drainTimeout
+	stderr := &tailBuffer{max: maxStderr}
+	cmd.Stderr = stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, &CommandError{Command: commandLine(name, args), ExitCode: -1, Err: err}
+	}
+	return &commandOutput{ReadCloser: stdout, cmd: cmd, stderr: stderr, line: commandLine(name, args)}, nil
+}
+
+type commandOutput struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	stderr *tailBuffer
+	line   string
+}
+
+// Close drains what is left of stdout, so a command about to exit does not
+// block on a full pipe, then waits for it. A command still writing after
+// maxDrain bytes or drainTimeout, like yes or tail -f that the reader gave up
+// on, is killed and reported as a *CommandError.
+func (c *commandOutput) Close() error {
+	drained := make(chan int64, 1)
+	go func() {
+		n, _ := io.Copy(io.Discard, io.LimitReader(c.ReadCloser, maxDrain))
+		drained <- n
+	}()
+	timer := time.NewTimer(drainTimeout)
+	defer timer.Stop()
+	select {
+	case n := <-drained:
+		if n < maxDrain {
+			return c.wait()
+		}
+		c.cmd.Process.Kill()
+	case <-timer.C:
+		c.cmd.Process.Kill()
+		// a child of the command may still hold the pipe open
+		c.ReadCloser.Close()
+		<-drained
+	}
+	return c.wait()
+}
+
+func (c *commandOutput) wait() error {
+	err := c.cmd.Wait()
+	if err == nil {
+		return nil
+	}
+	cmdErr := &CommandError{Command: c.line, ExitCode: -1, Stderr: strings.TrimSpace(c.stderr.String()), Err: err}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		cmdErr.ExitCode = exitErr.ExitCode()
+	}
+	return cmdErr
+}
+
+// tailBuffer keeps the last max bytes written to it. The buffer is not
+// embedded so io.Copy cannot go around Write through its ReadFrom.
+type tailBuffer struct {
+	buf bytes.Buffer
+	max int
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	n, _ := t.buf.Write(p)
+	if over := t.buf.Len() - t.max; over > 0 {
+		t.buf.Next(over)
+	}
+	return n, nil
+}
+
+func (t *tailBuffer) String() string { return t.buf.String() }
+
+func commandLine(name string, args []string) string {
+	return strings.Join(append([]string{name}, args...), " ")
+}
+
+// FakeCommand is what FakeRunner answers for one command line.
+type FakeCommand struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// FakeRunner answers commands from Commands, keyed by the command line
+// ("cat msg.xml"), and records the command lines it ran.
+type FakeRunner struct {
+	Commands map[string]FakeCommand
+	Calls    []string
+}
+
+func (f *FakeRunner) Run(ctx context.Context, name string, args ...string) (io.ReadCloser, error) {
+	line := commandLine(name, args)
+	f.Calls = append(f.Calls, line)
+	if err := ctx.Err(); err != nil {
+		return nil, &CommandError{Command: line, ExitCode: -1, Err: err}
+	}
+	cmd, ok := f.Commands[line]
+	if !ok {
+		return nil, &CommandError{Command: line, ExitCode: -1, Err: exec.ErrNotFound}
+	}
+	return &fakeOutput{Reader: strings.NewReader(cmd.Stdout), cmd: cmd, line: line}, nil
+}
+
+type fakeOutput struct {
+	io.Reader
+	cmd  FakeCommand
+	line string
+}
+
+func (f *fakeOutput) Close() error {
+	if f.cmd.ExitCode == 0 {
+		return nil
+	}
+	return &CommandError{
+		Command:  f.line,
+		ExitCode: f.cmd.ExitCode,
+		Stderr:   f.cmd.Stderr,
+		Err:      fmt.Errorf("exit status %d", f.cmd.ExitCode),
+	}
+}
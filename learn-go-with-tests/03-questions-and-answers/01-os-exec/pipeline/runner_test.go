@@ -0,0 +1,86 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecRunnerIntegration(t *testing.T) {
+	ctx := context.Background()
+
+	for _, path := range []string{"testdata/msg.xml", "testdata/msg.json", "testdata/msg.yaml"} {
+		t.Run(path, func(t *testing.T) {
+			got, err := New(ExecRunner{}).GetDataFromFile(ctx, path)
+
+			assertNoError(t, err)
+			assertMessage(t, got, "HAPPY NEW YEAR!")
+		})
+	}
+
+	t.Run("exit code and stderr", func(t *testing.T) {
+		_, err := New(ExecRunner{}).GetDataFromCommand(ctx, "xml", "sh", "-c", "echo '<payload>'; echo 'no more data' >&2; exit 3")
+
+		var cmdErr *CommandError
+		if !errors.As(err, &cmdErr) {
+			t.Fatalf("got %v, want a CommandError", err)
+		}
+		if cmdErr.ExitCode != 3 || cmdErr.Stderr != "no more data" {
+			t.Errorf("got exit code %d and stderr %q", cmdErr.ExitCode, cmdErr.Stderr)
+		}
+	})
+
+	t.Run("stderr is truncated to its end", func(t *testing.T) {
+		out, err := ExecRunner{}.Run(ctx, "sh", "-c", "head -c 10000 /dev/zero | tr '\\0' a >&2; echo end >&2; exit 1")
+		assertNoError(t, err)
+
+		err = out.Close()
+
+		var cmdErr *CommandError
+		if !errors.As(err, &cmdErr) || len(cmdErr.Stderr) > maxStderr || !strings.HasSuffix(cmdErr.Stderr, "end") {
+			t.Errorf("got %v", err)
+		}
+	})
+
+	t.Run("cancelling kills the command", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+
+		out, err := ExecRunner{}.Run(ctx, "sleep", "10")
+		assertNoError(t, err)
+		io.ReadAll(out)
+		err = out.Close()
+
+		if err == nil || time.Since(start) > 5*time.Second {
+			t.Errorf("got %v after %v, want the command killed", err, time.Since(start))
+		}
+	})
+
+	t.Run("closing early kills an endless command", func(t *testing.T) {
+		for _, script := range []string{"yes", "echo start; sleep 10"} {
+			start := time.Now()
+			out, err := ExecRunner{}.Run(ctx, "sh", "-c", script)
+			assertNoError(t, err)
+			io.ReadFull(out, make([]byte, 4))
+			err = out.Close()
+
+			var cmdErr *CommandError
+			if !errors.As(err, &cmdErr) || time.Since(start) > 5*time.Second {
+				t.Errorf("%s: got %v after %v, want the command killed", script, err, time.Since(start))
+			}
+		}
+	})
+
+	t.Run("missing command", func(t *testing.T) {
+		_, err := ExecRunner{}.Run(ctx, "no-such-command-hopefully")
+
+		var cmdErr *CommandError
+		if !errors.As(err, &cmdErr) || cmdErr.ExitCode != -1 {
+			t.Errorf("got %v, want a CommandError", err)
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const maxErrorBody = 512
+
+type options struct {
+	client      *http.Client
+	timeout     time.Duration
+	retries     int
+	backoff     time.Duration
+	maxBodySize int64
+}
+
+type Option func(*options)
+
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) { o.client = c }
+}
+
+// WithTimeout bounds every attempt, reading the body included. 0 disables it.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) { o.timeout = d }
+}
+
+// WithRetries retries idempotent requests up to n times on timeouts, network
+// errors, 429 and 5xx, waiting backoff, 2*backoff, 4*backoff... in between.
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.retries = n
+		o.backoff = backoff
+	}
+}
+
+// WithMaxBodySize makes larger responses fail with TooLargeError.
+func WithMaxBodySize(n int64) Option {
+	return func(o *options) { o.maxBodySize = n }
+}
+
+type Client struct {
+	options
+}
+
+func New(opts ...Option) *Client {
+	o := options{
+		client:      http.DefaultClient,
+		timeout:     30 * time.Second,
+		backoff:     100 * time.Millisecond,
+		maxBodySize: 10 << 20,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Client{o}
+}
+
+// Get returns the body of url if it gets a 2xx.
+func (c *Client) Get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// GetJSON decodes the body of url into v.
+func (c *Client) GetJSON(ctx context.Context, url string, v any) error {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return DecodeError{URL: url, Err: err}
+	}
+	return nil
+}
+
+// Do sends req and returns the body of a 2xx response. Only idempotent
+// requests whose body can be replayed are retried.
+func (c *Client) Do(req *http.Request) ([]byte, error) {
+	retries := c.retries
+	if !idempotent(req) {
+		retries = 0
+	}
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		body, retry, err := c.attempt(req)
+		if err == nil || !retry || attempt >= retries {
+			return body, err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-req.Context().Done():
+			return nil, err
+		}
+		backoff *= 2
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (c *Client) attempt(parent *http.Request) (body []byte, retry bool, err error) {
+	ctx := parent.Context()
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	req := parent.WithContext(ctx)
+	url := req.URL.String()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		err = c.fail(parent, err)
+		// a bad name or certificate does not fix itself
+		retry := parent.Context().Err() == nil && !errors.Is(err, ErrDNS) && !errors.Is(err, ErrTLS)
+		return nil, retry, err
+	}
+	// closed on every path, unlike DumbGetter
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBody))
+		err := BadStatusError{URL: url, Status: res.StatusCode, Body: string(snippet)}
+		return nil, res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500, err
+	}
+
+	body, err = io.ReadAll(io.LimitReader(res.Body, c.maxBodySize+1))
+	if err != nil {
+		return nil, parent.Context().Err() == nil, c.fail(parent, err)
+	}
+	if int64(len(body)) > c.maxBodySize {
+		return nil, false, TooLargeError{URL: url, Limit: c.maxBodySize}
+	}
+	return body, false, nil
+}
+
+func (c *Client) fail(req *http.Request, err error) error {
+	url := req.URL.String()
+	// the caller gave up, whatever the transport says
+	switch ctxErr := req.Context().Err(); {
+	case errors.Is(ctxErr, context.DeadlineExceeded):
+		return TimeoutError{URL: url, Err: err}
+	case ctxErr != nil:
+		return fmt.Errorf("problem fetching from %s: %w", url, ctxErr)
+	}
+	return classify(url, c.timeout, err)
+}
+
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	}
+	return false
+}
+
+// NewJSONRequest builds a request with v encoded as its body, replayable for
+// retries.
+func NewJSONRequest(ctx context.Context, method, url string, v any) (*http.Request, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
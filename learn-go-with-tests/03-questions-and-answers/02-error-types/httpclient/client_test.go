@@ -0,0 +1,256 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGet(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns the body of a 200", func(t *testing.T) {
+		svr := makeServer(t, http.StatusOK, "hello")
+
+		body, err := New().Get(ctx, svr.URL)
+
+		assertNoError(t, err)
+		if string(body) != "hello" {
+			t.Errorf("got %q, want %q", body, "hello")
+		}
+	})
+
+	t.Run("4xx is a BadStatusError and ErrClientStatus", func(t *testing.T) {
+		svr := makeServer(t, http.StatusTeapot, "short and stout")
+
+		_, err := New().Get(ctx, svr.URL)
+
+		var got BadStatusError
+		if !errors.As(err, &got) {
+			t.Fatalf("was not a BadStatusError, got %T", err)
+		}
+		want := BadStatusError{URL: svr.URL, Status: http.StatusTeapot, Body: "short and stout"}
+		if got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		assertKind(t, err, ErrClientStatus)
+	})
+
+	t.Run("5xx is ErrServerStatus", func(t *testing.T) {
+		svr := makeServer(t, http.StatusBadGateway, "")
+
+		_, err := New().Get(ctx, svr.URL)
+
+		assertKind(t, err, ErrServerStatus)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer svr.Close()
+
+		_, err := New(WithTimeout(20*time.Millisecond)).Get(ctx, svr.URL)
+
+		var got TimeoutError
+		if !errors.As(err, &got) || got.Timeout != 20*time.Millisecond {
+			t.Fatalf("got %v, want a TimeoutError after 20ms", err)
+		}
+		assertKind(t, err, ErrTimeout)
+	})
+
+	t.Run("the caller's deadline is a timeout too", func(t *testing.T) {
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer svr.Close()
+		short, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+
+		_, err := New(WithRetries(3, time.Millisecond)).Get(short, svr.URL)
+
+		assertKind(t, err, ErrTimeout)
+	})
+
+	t.Run("dns failure", func(t *testing.T) {
+		_, err := New().Get(ctx, "http://no-such-host.invalid")
+
+		var got DNSError
+		if !errors.As(err, &got) {
+			t.Fatalf("got %v (%T), want a DNSError", err, err)
+		}
+		assertKind(t, err, ErrDNS)
+	})
+
+	t.Run("tls failure", func(t *testing.T) {
+		svr := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		// keep the handshake failure out of the test output
+		svr.Config.ErrorLog = log.New(io.Discard, "", 0)
+		svr.StartTLS()
+		defer svr.Close()
+
+		// the default client does not trust the test certificate
+		_, err := New().Get(ctx, svr.URL)
+
+		var got TLSError
+		if !errors.As(err, &got) {
+			t.Fatalf("got %v (%T), want a TLSError", err, err)
+		}
+		assertKind(t, err, ErrTLS)
+
+		_, err = New(WithHTTPClient(svr.Client())).Get(ctx, svr.URL)
+		assertNoError(t, err)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		svr := makeServer(t, http.StatusOK, strings.Repeat("x", 11))
+
+		_, err := New(WithMaxBodySize(10)).Get(ctx, svr.URL)
+
+		if want := (TooLargeError{URL: svr.URL, Limit: 10}); err != want {
+			t.Errorf("got %v, want %v", err, want)
+		}
+		assertKind(t, err, ErrTooLarge)
+	})
+}
+
+func TestGetJSON(t *testing.T) {
+	t.Run("decodes", func(t *testing.T) {
+		svr := makeServer(t, http.StatusOK, `{"name": "Chris"}`)
+		var got struct{ Name string }
+
+		assertNoError(t, New().GetJSON(context.Background(), svr.URL, &got))
+		if got.Name != "Chris" {
+			t.Errorf("got %q, want %q", got.Name, "Chris")
+		}
+	})
+
+	t.Run("decode failure", func(t *testing.T) {
+		svr := makeServer(t, http.StatusOK, `{"name": `)
+		var got struct{ Name string }
+
+		err := New().GetJSON(context.Background(), svr.URL, &got)
+
+		var decodeErr DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.URL != svr.URL {
+			t.Fatalf("got %v, want a DecodeError", err)
+		}
+		assertKind(t, err, ErrDecode)
+	})
+}
+
+func TestRetries(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retries idempotent requests on 5xx", func(t *testing.T) {
+		svr, calls := makeFlakyServer(t, 2)
+
+		body, err := New(WithRetries(3, time.Millisecond)).Get(ctx, svr.URL)
+
+		assertNoError(t, err)
+		if string(body) != "ok" || calls.Load() != 3 {
+			t.Errorf("got %q after %d calls, want %q after 3", body, calls.Load(), "ok")
+		}
+	})
+
+	t.Run("replays the body of a PUT", func(t *testing.T) {
+		var bodies []string
+		var calls atomic.Int32
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer svr.Close()
+		req, err := NewJSONRequest(ctx, http.MethodPut, svr.URL, map[string]int{"wins": 3})
+		assertNoError(t, err)
+
+		_, err = New(WithRetries(1, time.Millisecond)).Do(req)
+
+		assertNoError(t, err)
+		if len(bodies) != 2 || bodies[0] != `{"wins":3}` || bodies[1] != bodies[0] {
+			t.Errorf("got bodies %q", bodies)
+		}
+	})
+
+	t.Run("does not retry a POST", func(t *testing.T) {
+		svr, calls := makeFlakyServer(t, 2)
+		req, err := NewJSONRequest(ctx, http.MethodPost, svr.URL, "win")
+		assertNoError(t, err)
+
+		_, err = New(WithRetries(3, time.Millisecond)).Do(req)
+
+		assertKind(t, err, ErrServerStatus)
+		if calls.Load() != 1 {
+			t.Errorf("got %d calls, want 1", calls.Load())
+		}
+	})
+
+	t.Run("does not retry a 4xx", func(t *testing.T) {
+		var calls atomic.Int32
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer svr.Close()
+
+		New(WithRetries(3, time.Millisecond)).Get(ctx, svr.URL)
+
+		if calls.Load() != 1 {
+			t.Errorf("got %d calls, want 1", calls.Load())
+		}
+	})
+}
+
+func makeServer(t testing.TB, status int, body string) *httptest.Server {
+	t.Helper()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(svr.Close)
+	return svr
+}
+
+// makeFlakyServer answers 503 the first failures times, then "ok".
+func makeFlakyServer(t testing.TB, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	t.Cleanup(svr.Close)
+	return svr, &calls
+}
+
+var kinds = []error{ErrTimeout, ErrDNS, ErrTLS, ErrClientStatus, ErrServerStatus, ErrDecode, ErrTooLarge}
+
+// assertKind checks err is want and none of the other kinds.
+func assertKind(t testing.TB, err, want error) {
+	t.Helper()
+	for _, kind := range kinds {
+		if got := errors.Is(err, kind); got != (kind == want) {
+			t.Errorf("errors.Is(%v, %v) = %t", err, kind, got)
+		}
+	}
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
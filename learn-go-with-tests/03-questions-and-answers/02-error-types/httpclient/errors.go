@@ -0,0 +1,131 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Kinds of failure, for errors.Is. Every error returned by Client matches at
+// most one of them; the typed errors below carry the details for errors.As.
+var (
+	ErrTimeout      = errors.New("httpclient: timeout")
+	ErrDNS          = errors.New("httpclient: dns lookup failed")
+	ErrTLS          = errors.New("httpclient: tls handshake failed")
+	ErrClientStatus = errors.New("httpclient: 4xx status")
+	ErrServerStatus = errors.New("httpclient: 5xx status")
+	ErrDecode       = errors.New("httpclient: cannot decode response")
+	ErrTooLarge     = errors.New("httpclient: response too large")
+)
+
+// BadStatusError is returned for any non-2xx response, as in DumbGetter. Body
+// holds the beginning of the response body to help debugging.
+type BadStatusError struct {
+	URL    string
+	Status int
+	Body   string
+}
+
+func (b BadStatusError) Error() string {
+	return fmt.Sprintf("did not get 2xx from %s, got %d", b.URL, b.Status)
+}
+
+func (b BadStatusError) Is(target error) bool {
+	switch target {
+	case ErrClientStatus:
+		return b.Status >= 400 && b.Status < 500
+	case ErrServerStatus:
+		return b.Status >= 500
+	}
+	return false
+}
+
+type TimeoutError struct {
+	URL     string
+	Timeout time.Duration // zero when the deadline came from the caller's context
+	Err     error
+}
+
+func (t TimeoutError) Error() string {
+	if t.Timeout > 0 {
+		return fmt.Sprintf("timed out after %v fetching from %s: %v", t.Timeout, t.URL, t.Err)
+	}
+	return fmt.Sprintf("timed out fetching from %s: %v", t.URL, t.Err)
+}
+
+func (t TimeoutError) Is(target error) bool { return target == ErrTimeout }
+func (t TimeoutError) Unwrap() error        { return t.Err }
+
+type DNSError struct {
+	URL string
+	Err *net.DNSError
+}
+
+func (d DNSError) Error() string {
+	return fmt.Sprintf("cannot resolve %s for %s: %v", d.Err.Name, d.URL, d.Err.Err)
+}
+
+func (d DNSError) Is(target error) bool { return target == ErrDNS }
+func (d DNSError) Unwrap() error        { return d.Err }
+
+type TLSError struct {
+	URL string
+	Err error
+}
+
+func (t TLSError) Error() string {
+	return fmt.Sprintf("tls error fetching from %s: %v", t.URL, t.Err)
+}
+
+func (t TLSError) Is(target error) bool { return target == ErrTLS }
+func (t TLSError) Unwrap() error        { return t.Err }
+
+type DecodeError struct {
+	URL string
+	Err error
+}
+
+func (d DecodeError) Error() string {
+	return fmt.Sprintf("cannot decode json from %s: %v", d.URL, d.Err)
+}
+
+func (d DecodeError) Is(target error) bool { return target == ErrDecode }
+func (d DecodeError) Unwrap() error        { return d.Err }
+
+type TooLargeError struct {
+	URL   string
+	Limit int64
+}
+
+func (t TooLargeError) Error() string {
+	return fmt.Sprintf("response from %s is larger than %d bytes", t.URL, t.Limit)
+}
+
+func (t TooLargeError) Is(target error) bool { return target == ErrTooLarge }
+
+// classify turns a transport error into one of the typed errors above, or
+// wraps it as DumbGetter did.
+func classify(url string, timeout time.Duration, err error) error {
+	var (
+		netErr     net.Error
+		dnsErr     *net.DNSError
+		verifyErr  *tls.CertificateVerificationError
+		recordErr  tls.RecordHeaderError
+		authErr    x509.UnknownAuthorityError
+		hostErr    x509.HostnameError
+		invalidErr x509.CertificateInvalidError
+	)
+	switch {
+	case errors.As(err, &dnsErr) && !dnsErr.IsTimeout:
+		return DNSError{URL: url, Err: dnsErr}
+	case errors.As(err, &verifyErr), errors.As(err, &recordErr), errors.As(err, &authErr),
+		errors.As(err, &hostErr), errors.As(err, &invalidErr):
+		return TLSError{URL: url, Err: err}
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return TimeoutError{URL: url, Timeout: timeout, Err: err}
+	}
+	return fmt.Errorf("problem fetching from %s: %w", url, err)
+}
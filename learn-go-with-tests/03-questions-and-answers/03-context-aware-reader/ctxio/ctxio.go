@@ -0,0 +1,135 @@
+package ctxio
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// ReadDeadliner is implemented by net.Conn and by pollable *os.File such as
+// pipes: a blocked Read returns once its deadline passes.
+type ReadDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+type WriteDeadliner interface {
+	SetWriteDeadline(t time.Time) error
+}
+
+// NewReader returns a Reader whose Read returns ctx.Err() as soon as ctx is
+// done, including a Read that is already blocked.
+//
+// Sources with read deadlines are interrupted by moving the deadline to the
+// past. Read clears the deadline of such a source, so a deadline set by the
+// caller is lost; put it on ctx instead. Any other source, and one whose
+// SetReadDeadline fails like a regular *os.File, is read from a goroutine: a
+// cancelled Read returns at once but that goroutine stays blocked until the
+// source returns, so close the source when you are done with it.
+func NewReader(ctx context.Context, r io.Reader) io.Reader {
+	return &reader{ctx: ctx, r: r}
+}
+
+type reader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+type result struct {
+	n   int
+	err error
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if d, ok := r.r.(ReadDeadliner); ok && d.SetReadDeadline(time.Time{}) == nil {
+		return withDeadline(r.ctx, d.SetReadDeadline, func() (int, error) { return r.r.Read(p) })
+	}
+
+	// the goroutine may outlive this call, so it must not touch p
+	buf := make([]byte, len(p))
+	done := make(chan result, 1)
+	go func() {
+		n, err := r.r.Read(buf)
+		done <- result{n, err}
+	}()
+	select {
+	case res := <-done:
+		return copy(p, buf[:res.n]), res.err
+	case <-r.ctx.Done():
+		return 0, r.ctx.Err()
+	}
+}
+
+// NewWriter is NewReader for writes. Write clears the write deadline too.
+// Writers without working write deadlines are written from a goroutine with a
+// copy of the data; a cancelled Write may still complete in the background.
+func NewWriter(ctx context.Context, w io.Writer) io.Writer {
+	return &writer{ctx: ctx, w: w}
+}
+
+type writer struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if d, ok := w.w.(WriteDeadliner); ok && d.SetWriteDeadline(time.Time{}) == nil {
+		return withDeadline(w.ctx, d.SetWriteDeadline, func() (int, error) { return w.w.Write(p) })
+	}
+
+	buf := append([]byte(nil), p...)
+	done := make(chan result, 1)
+	go func() {
+		n, err := w.w.Write(buf)
+		done <- result{n, err}
+	}()
+	select {
+	case res := <-done:
+		return res.n, res.err
+	case <-w.ctx.Done():
+		return 0, w.ctx.Err()
+	}
+}
+
+// withDeadline runs op, moving the deadline to the past when ctx is done, and
+// reports the resulting deadline error as ctx.Err(). The caller has checked
+// that setDeadline works and cleared the deadline.
+func withDeadline(ctx context.Context, setDeadline func(time.Time) error, op func() (int, error)) (int, error) {
+	interrupted := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		setDeadline(time.Unix(1, 0))
+		close(interrupted)
+	})
+
+	n, err := op()
+	if !stop() {
+		// clear the past deadline so the source stays usable with another context
+		<-interrupted
+		setDeadline(time.Time{})
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			err = ctx.Err()
+		}
+	}
+	return n, err
+}
+
+// Copy is io.Copy that stops with ctx.Err() as soon as ctx is done, even while
+// blocked reading src or writing dst.
+func Copy(ctx context.Context, dst io.Writer, src io.Reader) (int64, error) {
+	// wrapping hides ReaderFrom/WriterTo, which could not be interrupted
+	return io.Copy(NewWriter(ctx, dst), NewReader(ctx, src))
+}
+
+// NewScanner scans r line by line. Once ctx is done Scan returns false and Err
+// returns ctx.Err().
+func NewScanner(ctx context.Context, r io.Reader) *bufio.Scanner {
+	return bufio.NewScanner(NewReader(ctx, r))
+}
@@ -0,0 +1,198 @@
+package ctxio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const prompt = 200 * time.Millisecond
+
+func TestReader(t *testing.T) {
+	t.Run("behaves like a normal reader", func(t *testing.T) {
+		got, err := io.ReadAll(NewReader(context.Background(), strings.NewReader("123456")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "123456" {
+			t.Errorf("got %q, want %q", got, "123456")
+		}
+	})
+
+	t.Run("interrupts a blocked read on an io.Pipe", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		defer pw.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		n, err := readWithin(t, NewReader(ctx, pr))
+
+		assertErr(t, err, context.Canceled)
+		if n != 0 {
+			t.Errorf("read %d bytes after cancellation", n)
+		}
+	})
+
+	t.Run("interrupts a blocked read on an os.Pipe through its deadline", func(t *testing.T) {
+		pr, pw, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer pr.Close()
+		defer pw.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = readWithin(t, NewReader(ctx, pr))
+		assertErr(t, err, context.DeadlineExceeded)
+
+		// the deadline was reset, the pipe works with another context
+		pw.Write([]byte("hi"))
+		n, err := readWithin(t, NewReader(context.Background(), pr))
+		if err != nil || n != 2 {
+			t.Errorf("got %d bytes and %v, want 2 bytes", n, err)
+		}
+	})
+
+	t.Run("interrupts a blocked read on a source without working deadlines", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		defer pw.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		_, err := readWithin(t, NewReader(ctx, noDeadline{pr}))
+		assertErr(t, err, context.Canceled)
+	})
+}
+
+// noDeadline has SetReadDeadline, but like a regular *os.File it fails.
+type noDeadline struct{ io.Reader }
+
+func (noDeadline) SetReadDeadline(time.Time) error { return os.ErrNoDeadline }
+
+func TestWriter(t *testing.T) {
+	t.Run("interrupts a blocked write on an io.Pipe", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		defer pr.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		done := make(chan error)
+		go func() {
+			_, err := NewWriter(ctx, pw).Write([]byte("nobody reads this"))
+			done <- err
+		}()
+
+		select {
+		case err := <-done:
+			assertErr(t, err, context.Canceled)
+		case <-time.After(prompt):
+			t.Fatal("Write was not interrupted")
+		}
+	})
+
+	t.Run("does not retain the buffer of a cancelled write", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		defer pr.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		buf := []byte("abc")
+
+		_, err := NewWriter(ctx, pw).Write(buf)
+		assertErr(t, err, context.Canceled)
+		copy(buf, "xyz")
+
+		// the abandoned write still completes in the background, with the old data
+		got := make([]byte, 3)
+		if _, err := io.ReadFull(pr, got); err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "abc" {
+			t.Errorf("got %q, want %q", got, "abc")
+		}
+	})
+}
+
+func TestCopy(t *testing.T) {
+	t.Run("copies everything", func(t *testing.T) {
+		var dst bytes.Buffer
+		n, err := Copy(context.Background(), &dst, strings.NewReader("hello, world"))
+		if err != nil || n != 12 || dst.String() != "hello, world" {
+			t.Errorf("got %d bytes %q and %v", n, dst.String(), err)
+		}
+	})
+
+	t.Run("stops while waiting for more input", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		defer pw.Close()
+		go pw.Write([]byte("first chunk"))
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+		var dst bytes.Buffer
+
+		start := time.Now()
+		n, err := Copy(ctx, &dst, pr)
+
+		assertErr(t, err, context.Canceled)
+		if n != 11 || dst.String() != "first chunk" {
+			t.Errorf("copied %d bytes %q, want the first chunk", n, dst.String())
+		}
+		if time.Since(start) > prompt {
+			t.Errorf("took %v to stop", time.Since(start))
+		}
+	})
+}
+
+func TestScanner(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	go pw.Write([]byte("one\ntwo\n"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scanner := NewScanner(ctx, pr)
+
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) == 2 {
+			cancel()
+		}
+	}
+
+	if strings.Join(lines, ",") != "one,two" {
+		t.Errorf("got lines %v", lines)
+	}
+	assertErr(t, scanner.Err(), context.Canceled)
+}
+
+func readWithin(t testing.TB, r io.Reader) (int, error) {
+	t.Helper()
+	type result struct {
+		n   int
+		err error
+	}
+	done := make(chan result)
+	go func() {
+		n, err := r.Read(make([]byte, 16))
+		done <- result{n, err}
+	}()
+	select {
+	case res := <-done:
+		return res.n, res.err
+	case <-time.After(prompt):
+		t.Fatal("Read was not interrupted")
+		return 0, nil
+	}
+}
+
+func assertErr(t testing.TB, got, want error) {
+	t.Helper()
+	if !errors.Is(got, want) {
+		t.Errorf("got error %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package ctxio
+
+import (
+	"io"
+	"time"
+)
+
+// Progress is reported by NewProgressReader and NewProgressWriter.
+type Progress struct {
+	Bytes int64
+	Total int64 // -1 when unknown
+	Done  bool  // the last report: EOF or an error was reached
+}
+
+// Percent returns how much of Total is done, -1 when Total is unknown.
+func (p Progress) Percent() float64 {
+	if p.Total <= 0 {
+		return -1
+	}
+	return float64(p.Bytes) * 100 / float64(p.Total)
+}
+
+// NewProgressReader calls report with the bytes read so far at most once every
+// interval (every Read when interval is 0), and always once at EOF or on error.
+func NewProgressReader(r io.Reader, total int64, interval time.Duration, report func(Progress)) io.Reader {
+	return &progressReader{r: r, progress: progress{total: total, interval: interval, report: report, now: time.Now}}
+}
+
+// NewProgressWriter is NewProgressReader for writes. Done is only reported on
+// an error, since a writer does not know when the stream ends.
+func NewProgressWriter(w io.Writer, total int64, interval time.Duration, report func(Progress)) io.Writer {
+	return &progressWriter{w: w, progress: progress{total: total, interval: interval, report: report, now: time.Now}}
+}
+
+type progress struct {
+	bytes    int64
+	total    int64
+	interval time.Duration
+	report   func(Progress)
+	last     time.Time
+	now      func() time.Time
+}
+
+func (p *progress) add(n int, err error) {
+	p.bytes += int64(n)
+	done := err != nil
+	if !done && p.interval > 0 {
+		now := p.now()
+		if now.Sub(p.last) < p.interval {
+			return
+		}
+		p.last = now
+	}
+	p.report(Progress{Bytes: p.bytes, Total: p.total, Done: done})
+}
+
+type progressReader struct {
+	r io.Reader
+	progress
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.add(n, err)
+	return n, err
+}
+
+type progressWriter struct {
+	w io.Writer
+	progress
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.add(n, err)
+	return n, err
+}
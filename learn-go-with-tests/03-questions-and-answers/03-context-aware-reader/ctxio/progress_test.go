@@ -0,0 +1,87 @@
+package ctxio
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressReader(t *testing.T) {
+	t.Run("reports every read and the end", func(t *testing.T) {
+		var reports []Progress
+		r := NewProgressReader(strings.NewReader("123456"), 6, 0, func(p Progress) { reports = append(reports, p) })
+
+		buf := make([]byte, 4)
+		for {
+			if _, err := r.Read(buf); err != nil {
+				break
+			}
+		}
+
+		want := []Progress{{4, 6, false}, {6, 6, false}, {6, 6, true}}
+		if !reflect.DeepEqual(reports, want) {
+			t.Errorf("got %v, want %v", reports, want)
+		}
+		if got := reports[0].Percent(); got < 66 || got > 67 {
+			t.Errorf("got %v%%, want 66.6%%", got)
+		}
+	})
+
+	t.Run("reports at most once per interval", func(t *testing.T) {
+		var reports []Progress
+		r := NewProgressReader(strings.NewReader(strings.Repeat("x", 10)), -1, time.Second, func(p Progress) { reports = append(reports, p) })
+		now := time.Unix(0, 0)
+		r.(*progressReader).now = func() time.Time {
+			now = now.Add(400 * time.Millisecond)
+			return now
+		}
+
+		buf := make([]byte, 1)
+		for {
+			if _, err := r.Read(buf); err != nil {
+				break
+			}
+		}
+
+		// a read every 400ms: reported at 0.4s, 1.6s, 2.8s, 4s and at EOF
+		var bytes []int64
+		for _, p := range reports {
+			bytes = append(bytes, p.Bytes)
+		}
+		if want := []int64{1, 4, 7, 10, 10}; !reflect.DeepEqual(bytes, want) {
+			t.Errorf("got reports at %v bytes, want %v", bytes, want)
+		}
+		if last := reports[len(reports)-1]; !last.Done || last.Percent() != -1 {
+			t.Errorf("got last report %+v", last)
+		}
+	})
+}
+
+func TestProgressWriter(t *testing.T) {
+	var last Progress
+	w := NewProgressWriter(&failingWriter{capacity: 5}, 10, 0, func(p Progress) { last = p })
+
+	w.Write([]byte("abc"))
+	w.Write([]byte("defg"))
+
+	if last != (Progress{Bytes: 5, Total: 10, Done: true}) {
+		t.Errorf("got %+v", last)
+	}
+}
+
+// failingWriter accepts capacity bytes in total.
+type failingWriter struct {
+	capacity int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.capacity {
+		n := w.capacity
+		w.capacity = 0
+		return n, errors.New("disk full")
+	}
+	w.capacity -= len(p)
+	return len(p), nil
+}
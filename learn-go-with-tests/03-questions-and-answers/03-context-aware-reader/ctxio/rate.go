@@ -0,0 +1,108 @@
+package ctxio
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// NewRateLimitedReader reads at most bytesPerSecond on average. Reads are cut
+// to a tenth of a second worth of data so the flow stays smooth, and waiting
+// for the next slot stops with ctx.Err() once ctx is done.
+func NewRateLimitedReader(ctx context.Context, r io.Reader, bytesPerSecond int) io.Reader {
+	return &rateLimitedReader{limiter: newLimiter(ctx, bytesPerSecond), r: r}
+}
+
+func NewRateLimitedWriter(ctx context.Context, w io.Writer, bytesPerSecond int) io.Writer {
+	return &rateLimitedWriter{limiter: newLimiter(ctx, bytesPerSecond), w: w}
+}
+
+// limiter paces a byte stream: after n bytes the stream may not be ahead of
+// start + n/rate.
+type limiter struct {
+	ctx   context.Context
+	rate  int
+	chunk int
+	start time.Time
+	total int64
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func newLimiter(ctx context.Context, bytesPerSecond int) *limiter {
+	if bytesPerSecond < 1 {
+		panic("ctxio: rate must be positive")
+	}
+	return &limiter{
+		ctx:   ctx,
+		rate:  bytesPerSecond,
+		chunk: max(bytesPerSecond/10, 1),
+		now:   time.Now,
+		sleep: sleep,
+	}
+}
+
+// wait blocks until the stream may move on after n more bytes.
+func (l *limiter) wait(n int) error {
+	if l.start.IsZero() {
+		l.start = l.now()
+	}
+	l.total += int64(n)
+	due := l.start.Add(time.Duration(l.total) * time.Second / time.Duration(l.rate))
+	return l.sleep(l.ctx, due.Sub(l.now()))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type rateLimitedReader struct {
+	*limiter
+	r io.Reader
+}
+
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if len(p) > r.chunk {
+		p = p[:r.chunk]
+	}
+	n, err := r.r.Read(p)
+	if waitErr := r.wait(n); waitErr != nil && err == nil {
+		err = waitErr
+	}
+	return n, err
+}
+
+type rateLimitedWriter struct {
+	*limiter
+	w io.Writer
+}
+
+func (w *rateLimitedWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p[:min(len(p), w.chunk)]
+		if err := w.wait(len(chunk)); err != nil {
+			return written, err
+		}
+		n, err := w.w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
@@ -0,0 +1,79 @@
+package ctxio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTime lets the limiter sleep without waiting.
+type fakeTime struct {
+	now   time.Time
+	slept []time.Duration
+}
+
+func (f *fakeTime) install(l *limiter) {
+	l.now = func() time.Time { return f.now }
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		if d > 0 {
+			f.slept = append(f.slept, d)
+			f.now = f.now.Add(d)
+		}
+		return ctx.Err()
+	}
+}
+
+func TestRateLimitedReader(t *testing.T) {
+	clock := &fakeTime{now: time.Unix(0, 0)}
+	r := NewRateLimitedReader(context.Background(), strings.NewReader(strings.Repeat("x", 250)), 1000)
+	clock.install(r.(*rateLimitedReader).limiter)
+
+	got, err := io.ReadAll(r)
+
+	if err != nil || len(got) != 250 {
+		t.Fatalf("got %d bytes and %v", len(got), err)
+	}
+	// chunks of a tenth of a second, each one waiting for its slot
+	want := []time.Duration{100 * time.Millisecond, 100 * time.Millisecond, 50 * time.Millisecond}
+	if !reflect.DeepEqual(clock.slept, want) {
+		t.Errorf("slept %v, want %v", clock.slept, want)
+	}
+}
+
+func TestRateLimitedWriter(t *testing.T) {
+	clock := &fakeTime{now: time.Unix(0, 0)}
+	var dst bytes.Buffer
+	w := NewRateLimitedWriter(context.Background(), &dst, 10)
+	clock.install(w.(*rateLimitedWriter).limiter)
+
+	n, err := w.Write([]byte("abc"))
+
+	if err != nil || n != 3 || dst.String() != "abc" {
+		t.Fatalf("wrote %d bytes %q and %v", n, dst.String(), err)
+	}
+	if got := clock.now.Sub(time.Unix(0, 0)); got != 300*time.Millisecond {
+		t.Errorf("took %v, want 300ms", got)
+	}
+}
+
+func TestRateLimitCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	// one byte per second: the second byte would come after a second
+	r := NewRateLimitedReader(ctx, strings.NewReader("ab"), 1)
+
+	start := time.Now()
+	_, err := io.ReadAll(r)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+	if time.Since(start) > prompt {
+		t.Errorf("took %v to stop", time.Since(start))
+	}
+}
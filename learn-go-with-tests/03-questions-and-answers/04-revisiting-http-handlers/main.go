@@ -2,25 +2,46 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 )
 
+const maxUserPayload = 1 << 20
+
 type User struct {
-	Name string
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
 }
 
 type UserService interface {
 	Register(user User) (insertedID string, err error)
+	Get(id string) (User, error)
+	Delete(id string) error
 }
 
+// UserServer routes by method:
+//
+//	POST   /users       register a user, 201 with the new ID
+//	GET    /users/{id}  the user as JSON
+//	DELETE /users/{id}  204
 type UserServer struct {
 	service UserService
+	http.Handler
 }
 
 func NewUserServer(service UserService) *UserServer {
-	return &UserServer{service: service}
+	u := &UserServer{service: service}
+
+	router := http.NewServeMux()
+	router.HandleFunc("POST /users", u.RegisterUser)
+	router.HandleFunc("GET /users/{id}", u.GetUser)
+	router.HandleFunc("DELETE /users/{id}", u.DeleteUser)
+	u.Handler = router
+
+	return u
 }
 
 func (u *UserServer) RegisterUser(w http.ResponseWriter, r *http.Request) {
@@ -28,20 +49,24 @@ func (u *UserServer) RegisterUser(w http.ResponseWriter, r *http.Request) {
 
 	// request parsing and validation
 	var newUser User
-	err := json.NewDecoder(r.Body).Decode(&newUser)
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxUserPayload)).Decode(&newUser)
 
 	if err != nil {
 		http.Error(w, fmt.Sprintf("could not decode user payload: %v", err), http.StatusBadRequest)
 		return
 	}
 
+	if err := newUser.Validate(); err != nil {
+		writeError(w, "invalid user", err)
+		return
+	}
+
 	// call a service thing to take care of the hard work
 	insertedID, err := u.service.Register(newUser)
 
 	// depending on what we get back, respond accordingly
 	if err != nil {
-		//todo: handle different kinds of errors differently
-		http.Error(w, fmt.Sprintf("problem registering new user: %v", err), http.StatusInternalServerError)
+		writeError(w, "problem registering new user", err)
 		return
 	}
 
@@ -49,25 +74,64 @@ func (u *UserServer) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, insertedID)
 }
 
-type MongoUserService struct {
+func (u *UserServer) GetUser(w http.ResponseWriter, r *http.Request) {
+	user, err := u.service.Get(r.PathValue("id"))
+	if err != nil {
+		writeError(w, "problem getting user", err)
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(user)
 }
 
-func NewMongoUserService() *MongoUserService {
-	//todo: pass in DB URL as argument to this function
-	//todo: connect to db, create a connection pool
-	return &MongoUserService{}
+func (u *UserServer) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	if err := u.service.Delete(r.PathValue("id")); err != nil {
+		writeError(w, "problem deleting user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
-func (m MongoUserService) Register(user User) (insertedID string, err error) {
-	// use m.mongoConnection to perform queries
-	panic("implement me")
+// writeError answers with the status of err. Validation errors list the
+// invalid fields as JSON so clients can show them next to their inputs.
+func writeError(w http.ResponseWriter, msg string, err error) {
+	var invalid ValidationError
+	if errors.As(err, &invalid) {
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(invalid)
+		return
+	}
+
+	http.Error(w, fmt.Sprintf("%s: %v", msg, err), statusFor(err))
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, ErrDuplicateUser):
+		return http.StatusConflict
+	case errors.Is(err, ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrUnavailable):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
 }
 
 func main() {
-	mongoService := NewMongoUserService()
-	server := NewUserServer(mongoService)
-	err := http.ListenAndServe(":8000", http.HandlerFunc(server.RegisterUser))
+	dbPath := flag.String("db", "users.db", "path of the SQLite database")
+	flag.Parse()
+
+	service, err := NewSQLiteUserService(*dbPath)
 	if err != nil {
 		log.Fatal(err)
 	}
+	defer service.Close()
+
+	server := NewUserServer(service)
+	if err := http.ListenAndServe(":8000", server); err != nil {
+		log.Fatal(err)
+	}
 }
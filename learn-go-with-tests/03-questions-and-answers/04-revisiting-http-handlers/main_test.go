@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -14,6 +15,8 @@ import (
 
 type MockUserService struct {
 	RegisterFunc    func(user User) (string, error)
+	GetFunc         func(id string) (User, error)
+	DeleteFunc      func(id string) error
 	UsersRegistered []User
 }
 
@@ -22,6 +25,14 @@ func (m *MockUserService) Register(user User) (insertedID string, err error) {
 	return m.RegisterFunc(user)
 }
 
+func (m *MockUserService) Get(id string) (User, error) {
+	return m.GetFunc(id)
+}
+
+func (m *MockUserService) Delete(id string) error {
+	return m.DeleteFunc(id)
+}
+
 func TestRegisterUser(t *testing.T) {
 	t.Run("can register valid users", func(t *testing.T) {
 		user := User{Name: "CJ"}
@@ -34,7 +45,7 @@ func TestRegisterUser(t *testing.T) {
 		}
 		server := NewUserServer(service)
 
-		req := httptest.NewRequest(http.MethodGet, "/", userToJSON(user))
+		req := httptest.NewRequest(http.MethodPost, "/users", userToJSON(user))
 		res := httptest.NewRecorder()
 
 		server.RegisterUser(res, req)
@@ -57,7 +68,7 @@ func TestRegisterUser(t *testing.T) {
 	t.Run("returns 400 bad request if body is not valid user JSON", func(t *testing.T) {
 		server := NewUserServer(nil)
 
-		req := httptest.NewRequest(http.MethodGet, "/", strings.NewReader("trouble will find me"))
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("trouble will find me"))
 		res := httptest.NewRecorder()
 
 		server.RegisterUser(res, req)
@@ -65,29 +76,115 @@ func TestRegisterUser(t *testing.T) {
 		assertStatus(t, res.Code, http.StatusBadRequest)
 	})
 
-	t.Run("returns a 500 internal server error if the service fails", func(t *testing.T) {
-		user := User{Name: "CJ"}
-
-		service := &MockUserService{
-			RegisterFunc: func(user User) (string, error) {
-				return "", errors.New("couldn't add new user")
-			},
-		}
+	t.Run("returns 422 with the invalid fields and does not call the service", func(t *testing.T) {
+		service := &MockUserService{}
 		server := NewUserServer(service)
 
-		req := httptest.NewRequest(http.MethodGet, "/", userToJSON(user))
+		req := httptest.NewRequest(http.MethodPost, "/users", userToJSON(User{Name: "  ", Email: "not an email"}))
 		res := httptest.NewRecorder()
 
 		server.RegisterUser(res, req)
 
-		assertStatus(t, res.Code, http.StatusInternalServerError)
+		assertStatus(t, res.Code, http.StatusUnprocessableEntity)
+
+		var got ValidationError
+		json.NewDecoder(res.Body).Decode(&got)
+		want := map[string]string{"name": "is required", "email": "must be an address like cj@example.com"}
+		if !reflect.DeepEqual(got.Fields, want) {
+			t.Errorf("got field errors %v, want %v", got.Fields, want)
+		}
+
+		if len(service.UsersRegistered) != 0 {
+			t.Errorf("expected no user added but got %d", len(service.UsersRegistered))
+		}
 	})
+
+	serviceErrors := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"a 409 conflict if the user exists", fmt.Errorf("%w: %q", ErrDuplicateUser, "CJ"), http.StatusConflict},
+		{"a 422 if the service rejects the user", ValidationError{Fields: map[string]string{"name": "is taken"}}, http.StatusUnprocessableEntity},
+		{"a 503 service unavailable if the store is down", fmt.Errorf("%w: database is locked", ErrUnavailable), http.StatusServiceUnavailable},
+		{"a 500 internal server error if the service fails", errors.New("couldn't add new user"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range serviceErrors {
+		t.Run("returns "+tt.name, func(t *testing.T) {
+			user := User{Name: "CJ"}
+
+			service := &MockUserService{
+				RegisterFunc: func(user User) (string, error) {
+					return "", tt.err
+				},
+			}
+			server := NewUserServer(service)
+
+			req := httptest.NewRequest(http.MethodPost, "/users", userToJSON(user))
+			res := httptest.NewRecorder()
+
+			server.RegisterUser(res, req)
+
+			assertStatus(t, res.Code, tt.want)
+		})
+	}
+}
+
+func TestUserRoutes(t *testing.T) {
+	service := &MockUserService{
+		RegisterFunc: func(user User) (string, error) {
+			return "1", nil
+		},
+		GetFunc: func(id string) (User, error) {
+			if id != "1" {
+				return User{}, ErrUserNotFound
+			}
+			return User{Name: "CJ", Email: "cj@example.com"}, nil
+		},
+		DeleteFunc: func(id string) error {
+			if id != "1" {
+				return ErrUserNotFound
+			}
+			return nil
+		},
+	}
+	server := NewUserServer(service)
+
+	cases := []struct {
+		method, path string
+		body         io.Reader
+		wantStatus   int
+		wantBody     string
+	}{
+		{http.MethodPost, "/users", userToJSON(User{Name: "CJ"}), http.StatusCreated, "1"},
+		{http.MethodGet, "/users/1", nil, http.StatusOK, `{"name":"CJ","email":"cj@example.com"}` + "\n"},
+		{http.MethodGet, "/users/2", nil, http.StatusNotFound, ""},
+		{http.MethodDelete, "/users/1", nil, http.StatusNoContent, ""},
+		{http.MethodDelete, "/users/2", nil, http.StatusNotFound, ""},
+		{http.MethodGet, "/users", nil, http.StatusMethodNotAllowed, ""},
+		{http.MethodPut, "/users/1", userToJSON(User{Name: "CJ"}), http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.method+" "+c.path, func(t *testing.T) {
+			req := httptest.NewRequest(c.method, c.path, c.body)
+			res := httptest.NewRecorder()
+
+			server.ServeHTTP(res, req)
+
+			assertStatus(t, res.Code, c.wantStatus)
+			if c.wantBody != "" && res.Body.String() != c.wantBody {
+				t.Errorf("got body %q, want %q", res.Body.String(), c.wantBody)
+			}
+		})
+	}
 }
 
 func assertStatus(t testing.TB, got, want int) {
 	t.Helper()
 	if got != want {
-		t.Errorf("wanted http status %d but got %d", got, want)
+		t.Errorf("wanted http status %d but got %d", want, got)
 	}
 }
 
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const createUsers = `CREATE TABLE IF NOT EXISTS users (
+	id    INTEGER PRIMARY KEY AUTOINCREMENT,
+	name  TEXT NOT NULL UNIQUE COLLATE NOCASE,
+	email TEXT NOT NULL DEFAULT ''
+)`
+
+// SQLiteUserService stores users in a SQLite database file. Names are
+// unique regardless of case.
+type SQLiteUserService struct {
+	db *sql.DB
+}
+
+func NewSQLiteUserService(path string) (*SQLiteUserService, error) {
+	db, err := sql.Open("sqlite3", path+"?_busy_timeout=5000")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(createUsers); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not create users table in %s: %w", path, err)
+	}
+	return &SQLiteUserService{db: db}, nil
+}
+
+func (s *SQLiteUserService) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteUserService) Register(user User) (insertedID string, err error) {
+	if err := user.Validate(); err != nil {
+		return "", err
+	}
+
+	res, err := s.db.Exec(`INSERT INTO users (name, email) VALUES (?, ?)`, strings.TrimSpace(user.Name), user.Email)
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		return "", fmt.Errorf("%w: %q", ErrDuplicateUser, user.Name)
+	}
+	if err != nil {
+		return "", unavailable(err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return "", unavailable(err)
+	}
+	return strconv.FormatInt(id, 10), nil
+}
+
+func (s *SQLiteUserService) Get(id string) (User, error) {
+	var user User
+	err := s.db.QueryRow(`SELECT name, email FROM users WHERE id = ?`, id).Scan(&user.Name, &user.Email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, fmt.Errorf("%w: %q", ErrUserNotFound, id)
+	}
+	if err != nil {
+		return User{}, unavailable(err)
+	}
+	return user, nil
+}
+
+func (s *SQLiteUserService) Delete(id string) error {
+	res, err := s.db.Exec(`DELETE FROM users WHERE id = ?`, id)
+	if err != nil {
+		return unavailable(err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return unavailable(err)
+	} else if n == 0 {
+		return fmt.Errorf("%w: %q", ErrUserNotFound, id)
+	}
+	return nil
+}
+
+// unavailable reports a failing database: locked, closed, out of disk. None
+// of those are the caller's fault.
+func unavailable(err error) error {
+	return fmt.Errorf("%w: %v", ErrUnavailable, err)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteUserService(t *testing.T) {
+	service := newTestService(t)
+
+	id, err := service.Register(User{Name: " CJ ", Email: "cj@example.com"})
+	assertNoError(t, err)
+
+	t.Run("gets a registered user", func(t *testing.T) {
+		got, err := service.Get(id)
+
+		assertNoError(t, err)
+		if want := (User{Name: "CJ", Email: "cj@example.com"}); got != want {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("names are unique regardless of case", func(t *testing.T) {
+		_, err := service.Register(User{Name: "cj"})
+
+		assertErrorIs(t, err, ErrDuplicateUser)
+	})
+
+	t.Run("rejects invalid users", func(t *testing.T) {
+		_, err := service.Register(User{})
+
+		var invalid ValidationError
+		if !errors.As(err, &invalid) {
+			t.Errorf("got %v, want a ValidationError", err)
+		}
+	})
+
+	t.Run("unknown users are not found", func(t *testing.T) {
+		_, err := service.Get("404")
+		assertErrorIs(t, err, ErrUserNotFound)
+
+		assertErrorIs(t, service.Delete("404"), ErrUserNotFound)
+	})
+
+	t.Run("deletes users", func(t *testing.T) {
+		assertNoError(t, service.Delete(id))
+
+		_, err := service.Get(id)
+		assertErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("persists users across restarts", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "users.db")
+		service, err := NewSQLiteUserService(path)
+		assertNoError(t, err)
+		id, err := service.Register(User{Name: "Chris"})
+		assertNoError(t, err)
+		service.Close()
+
+		service, err = NewSQLiteUserService(path)
+		assertNoError(t, err)
+		defer service.Close()
+		got, err := service.Get(id)
+
+		assertNoError(t, err)
+		if got.Name != "Chris" {
+			t.Errorf("got %+v, want Chris", got)
+		}
+	})
+
+	t.Run("a closed database is unavailable", func(t *testing.T) {
+		service := newTestService(t)
+		service.Close()
+
+		_, err := service.Register(User{Name: "CJ"})
+
+		assertErrorIs(t, err, ErrUnavailable)
+	})
+}
+
+func newTestService(t testing.TB) *SQLiteUserService {
+	t.Helper()
+	service, err := NewSQLiteUserService(filepath.Join(t.TempDir(), "users.db"))
+	assertNoError(t, err)
+	t.Cleanup(func() { service.Close() })
+	return service
+}
+
+func assertErrorIs(t testing.TB, got, want error) {
+	t.Helper()
+	if !errors.Is(got, want) {
+		t.Errorf("got error %v, want %v", got, want)
+	}
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxNameLength = 64
+
+var (
+	ErrDuplicateUser = errors.New("user already exists")
+	ErrUserNotFound  = errors.New("user not found")
+	// ErrUnavailable is the store failing rather than the request, so the
+	// client may retry later.
+	ErrUnavailable = errors.New("user service unavailable")
+)
+
+// ValidationError maps the invalid fields of a user to what is wrong with
+// them, e.g. {"name": "is required"}.
+type ValidationError struct {
+	Fields map[string]string `json:"errors"`
+}
+
+func (v ValidationError) Error() string {
+	fields := make([]string, 0, len(v.Fields))
+	for field, problem := range v.Fields {
+		fields = append(fields, field+" "+problem)
+	}
+	sort.Strings(fields)
+	return "invalid user: " + strings.Join(fields, ", ")
+}
+
+// Validate checks the name is set, short and printable, and the email, when
+// given, is a plain address.
+func (u User) Validate() error {
+	problems := map[string]string{}
+
+	name := strings.TrimSpace(u.Name)
+	switch {
+	case name == "":
+		problems["name"] = "is required"
+	case utf8.RuneCountInString(name) > maxNameLength:
+		problems["name"] = fmt.Sprintf("must be at most %d characters", maxNameLength)
+	case strings.IndexFunc(name, func(r rune) bool { return !unicode.IsPrint(r) }) >= 0:
+		problems["name"] = "must not contain control characters"
+	}
+
+	if u.Email != "" {
+		if addr, err := mail.ParseAddress(u.Email); err != nil || addr.Address != u.Email {
+			problems["email"] = "must be an address like cj@example.com"
+		}
+	}
+
+	if len(problems) > 0 {
+		return ValidationError{Fields: problems}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package schemasync
+
+import (
+	"errors"
+	"fmt"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var ErrUnknownDriver = errors.New("schemasync: unknown driver")
+
+// Dialect is the Atlas support of a database. Diff and Plan work without a
+// connection, so plans can be computed and tested from schema values only.
+type Dialect struct {
+	Name string
+	Diff schema.Differ
+	Plan migrate.PlanApplier
+	// EvalHCL reads a schema.hcl document into a *schema.Schema
+	EvalHCL func(b []byte, v any, input map[string]cty.Value) error
+	// Open returns the Atlas driver of a connection
+	Open func(schema.ExecQuerier) (migrate.Driver, error)
+}
+
+var (
+	MySQL    = Dialect{Name: "mysql", Diff: mysql.DefaultDiff, Plan: mysql.DefaultPlan, EvalHCL: mysql.EvalHCLBytes, Open: mysql.Open}
+	Postgres = Dialect{Name: "postgres", Diff: postgres.DefaultDiff, Plan: postgres.DefaultPlan, EvalHCL: postgres.EvalHCLBytes, Open: postgres.Open}
+	SQLite   = Dialect{Name: "sqlite", Diff: sqlite.DefaultDiff, Plan: sqlite.DefaultPlan, EvalHCL: sqlite.EvalHCLBytes, Open: sqlite.Open}
+)
+
+// DialectFor returns the dialect of a database/sql driver name.
+func DialectFor(driver string) (Dialect, error) {
+	switch driver {
+	case "mysql":
+		return MySQL, nil
+	case "postgres", "pgx":
+		return Postgres, nil
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	}
+	return Dialect{}, fmt.Errorf("%w %q, want mysql, postgres or sqlite3", ErrUnknownDriver, driver)
+}
+
+// LoadSchema evaluates the schema.hcl document src. It must describe one
+// schema, like the schema "test_db" {} block of the Atlas example.
+func (d Dialect) LoadSchema(src []byte) (*schema.Schema, error) {
+	var s schema.Schema
+	if err := d.EvalHCL(src, &s, nil); err != nil {
+		return nil, fmt.Errorf("schemasync: evaluating HCL: %w", err)
+	}
+	return &s, nil
+}
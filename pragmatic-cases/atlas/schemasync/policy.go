@@ -0,0 +1,112 @@
+package schemasync
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+var ErrBlocked = errors.New("schemasync: destructive changes are not allowed")
+
+// Rule is a kind of destructive change the policy blocks.
+type Rule string
+
+const (
+	DropSchema Rule = "drop_schema"
+	DropTable  Rule = "drop_table"
+	DropColumn Rule = "drop_column"
+	// ModifyType is any column type change: Atlas does not tell widening
+	// from narrowing, so every one needs a look
+	ModifyType Rule = "modify_type"
+)
+
+// Policy lists the destructive changes that are allowed. An entry is a rule
+// for every object, or a rule and an object: "drop_column:users.legacy",
+// "drop_table:sessions". Nothing is allowed by default.
+type Policy struct {
+	Allow []string
+}
+
+// Violation is a destructive change the policy does not allow.
+type Violation struct {
+	Rule    Rule   `json:"rule"`
+	Object  string `json:"object"`
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string { return fmt.Sprintf("%s (%s:%s)", v.Message, v.Rule, v.Object) }
+
+// Check returns the changes that the policy blocks.
+func (p Policy) Check(changes []schema.Change) []Violation {
+	var violations []Violation
+	add := func(rule Rule, object, format string, args ...any) {
+		if !p.allows(rule, object) {
+			violations = append(violations, Violation{Rule: rule, Object: object, Message: fmt.Sprintf(format, args...)})
+		}
+	}
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.DropSchema:
+			add(DropSchema, c.S.Name, "drops schema %s and its tables", c.S.Name)
+		case *schema.DropTable:
+			add(DropTable, c.T.Name, "drops table %s", c.T.Name)
+		case *schema.ModifyTable:
+			for _, tc := range c.Changes {
+				switch tc := tc.(type) {
+				case *schema.DropColumn:
+					object := c.T.Name + "." + tc.C.Name
+					add(DropColumn, object, "drops column %s", object)
+				case *schema.ModifyColumn:
+					if tc.Change.Is(schema.ChangeType) {
+						object := c.T.Name + "." + tc.To.Name
+						add(ModifyType, object, "changes the type of %s from %s to %s", object, typeName(tc.From), typeName(tc.To))
+					}
+				}
+			}
+		}
+	}
+	return violations
+}
+
+func (p Policy) allows(rule Rule, object string) bool {
+	for _, a := range p.Allow {
+		r, o, ok := strings.Cut(a, ":")
+		if Rule(r) == rule && (!ok || o == object) {
+			return true
+		}
+	}
+	return false
+}
+
+// typeName names a column type for messages: the type as inspected, or as
+// written in HCL.
+func typeName(c *schema.Column) string {
+	if c.Type == nil || c.Type.Type == nil {
+		return "unknown"
+	}
+	if c.Type.Raw != "" {
+		return c.Type.Raw
+	}
+	switch t := c.Type.Type.(type) {
+	case *schema.StringType:
+		if t.Size > 0 {
+			return fmt.Sprintf("%s(%d)", t.T, t.Size)
+		}
+		return t.T
+	case *schema.IntegerType:
+		return t.T
+	case *schema.DecimalType:
+		return fmt.Sprintf("%s(%d,%d)", t.T, t.Precision, t.Scale)
+	case *schema.FloatType:
+		return t.T
+	case *schema.TimeType:
+		return t.T
+	case *schema.BoolType:
+		return t.T
+	case *schema.BinaryType:
+		return t.T
+	}
+	return fmt.Sprintf("%T", c.Type.Type)
+}
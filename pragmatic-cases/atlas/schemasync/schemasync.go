@@ -0,0 +1,176 @@
+// Package schemasync brings a live MySQL, Postgres or SQLite schema to the
+// one described in an Atlas schema.hcl, like the atlas example, with a
+// plan to review first, a policy that blocks destructive changes unless
+// they are allowed, and a lock so two applies never race.
+package schemasync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+var ErrLocked = errors.New("schemasync: another apply holds the lock")
+
+// Statement is a statement of a plan.
+type Statement struct {
+	SQL     string `json:"sql"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// Plan is the statements that bring a schema to the desired one, and the
+// destructive changes among them that the policy blocks.
+type Plan struct {
+	Schema     string      `json:"schema"`
+	Statements []Statement `json:"statements"`
+	Violations []Violation `json:"violations,omitempty"`
+
+	changes []schema.Change
+}
+
+func (p *Plan) Empty() bool { return len(p.changes) == 0 }
+
+// Err returns an ErrBlocked listing the violations, if any.
+func (p *Plan) Err() error {
+	if len(p.Violations) == 0 {
+		return nil
+	}
+	var msgs []string
+	for _, v := range p.Violations {
+		msgs = append(msgs, v.String())
+	}
+	return fmt.Errorf("%w: %s", ErrBlocked, strings.Join(msgs, "; "))
+}
+
+// SQL returns the statements as a migration file, each after its comment.
+func (p *Plan) SQL() string {
+	var b strings.Builder
+	for _, s := range p.Statements {
+		if s.Comment != "" {
+			fmt.Fprintf(&b, "-- %s\n", s.Comment)
+		}
+		fmt.Fprintf(&b, "%s;\n", s.SQL)
+	}
+	return b.String()
+}
+
+// Diff plans the changes from current to desired without a database, and
+// checks them against the policy. A nil current is a schema that does not
+// exist yet.
+func Diff(ctx context.Context, d Dialect, current, desired *schema.Schema, policy Policy) (*Plan, error) {
+	return diff(ctx, d.Diff, d.Plan, current, desired, policy)
+}
+
+func diff(ctx context.Context, differ schema.Differ, planner migrate.PlanApplier, current, desired *schema.Schema, policy Policy) (*Plan, error) {
+	var changes []schema.Change
+	if current == nil {
+		changes = append(changes, &schema.AddSchema{S: desired, Extra: []schema.Clause{&schema.IfNotExists{}}})
+		current = schema.New(desired.Name)
+	}
+	tableChanges, err := differ.SchemaDiff(current, desired)
+	if err != nil {
+		return nil, fmt.Errorf("schemasync: diffing schema %s: %w", desired.Name, err)
+	}
+	changes = append(changes, tableChanges...)
+	plan := &Plan{Schema: desired.Name, Statements: []Statement{}, Violations: policy.Check(changes), changes: changes}
+	if len(changes) == 0 {
+		return plan, nil
+	}
+	p, err := planner.PlanChanges(ctx, "schemasync", changes)
+	if err != nil {
+		return nil, fmt.Errorf("schemasync: planning: %w", err)
+	}
+	for _, c := range p.Changes {
+		plan.Statements = append(plan.Statements, Statement{SQL: c.Cmd, Comment: c.Comment})
+	}
+	return plan, nil
+}
+
+// Syncer plans and applies changes on a database.
+type Syncer struct {
+	driver      migrate.Driver
+	policy      Policy
+	lockName    string
+	lockTimeout time.Duration
+}
+
+type Option func(*Syncer)
+
+// WithPolicy sets the destructive changes Apply allows. The default allows
+// none.
+func WithPolicy(p Policy) Option {
+	return func(s *Syncer) { s.policy = p }
+}
+
+// WithLock sets the name of the lock Apply takes, "schemasync" by default,
+// and how long it waits for it, 10s by default. SQLite locks are files in
+// the temporary directory, named after the lock.
+func WithLock(name string, timeout time.Duration) Option {
+	return func(s *Syncer) { s.lockName, s.lockTimeout = name, timeout }
+}
+
+// New returns a Syncer of db, which must be a database of the dialect.
+func New(db *sql.DB, d Dialect, opts ...Option) (*Syncer, error) {
+	drv, err := d.Open(db)
+	if err != nil {
+		return nil, fmt.Errorf("schemasync: opening %s driver: %w", d.Name, err)
+	}
+	s := &Syncer{driver: drv, lockName: "schemasync", lockTimeout: 10 * time.Second}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// Inspect returns the live schema name, or nil if it does not exist.
+func (s *Syncer) Inspect(ctx context.Context, name string) (*schema.Schema, error) {
+	current, err := s.driver.InspectSchema(ctx, name, nil)
+	if schema.IsNotExistError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("schemasync: inspecting schema %s: %w", name, err)
+	}
+	return current, nil
+}
+
+// Plan compares the live schema with desired.
+func (s *Syncer) Plan(ctx context.Context, desired *schema.Schema) (*Plan, error) {
+	current, err := s.Inspect(ctx, desired.Name)
+	if err != nil {
+		return nil, err
+	}
+	return diff(ctx, s.driver, s.driver, current, desired, s.policy)
+}
+
+// Apply plans again under the lock, so a plan is never based on a schema
+// another apply is changing, and applies it unless the policy blocks it.
+// The plan is returned in both cases.
+func (s *Syncer) Apply(ctx context.Context, desired *schema.Schema) (*Plan, error) {
+	unlock, err := s.driver.Lock(ctx, s.lockName, s.lockTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrLocked, s.lockName, err)
+	}
+	defer unlock()
+
+	plan, err := s.Plan(ctx, desired)
+	if err != nil {
+		return nil, err
+	}
+	if err := plan.Err(); err != nil {
+		return plan, err
+	}
+	if plan.Empty() {
+		return plan, nil
+	}
+	if err := s.driver.ApplyChanges(ctx, plan.changes); err != nil {
+		return plan, fmt.Errorf("schemasync: applying: %w", err)
+	}
+	return plan, nil
+}
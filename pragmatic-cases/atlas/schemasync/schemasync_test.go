@@ -0,0 +1,246 @@
+package schemasync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"ariga.io/atlas/sql/schema"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// users is the table of the atlas example, with extra columns to change.
+func users(extra ...*schema.Column) *schema.Schema {
+	t := schema.NewTable("users").AddColumns(
+		schema.NewIntColumn("id", "int"),
+		schema.NewStringColumn("greeting", "text"),
+	)
+	t.AddColumns(extra...)
+	return schema.New("test_db").AddTables(t)
+}
+
+func TestDiff(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no changes", func(t *testing.T) {
+		plan, err := Diff(ctx, MySQL, users(), users(), Policy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !plan.Empty() || len(plan.Statements) != 0 || plan.Err() != nil {
+			t.Errorf("got %+v", plan)
+		}
+	})
+
+	t.Run("additive changes are allowed", func(t *testing.T) {
+		plan, err := Diff(ctx, MySQL, users(), users(schema.NewNullStringColumn("email", "varchar", schema.StringSize(255))), Policy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertStatements(t, plan, "ALTER TABLE `test_db`.`users` ADD COLUMN `email` varchar(255) NULL")
+		if err := plan.Err(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("a new schema is created", func(t *testing.T) {
+		plan, err := Diff(ctx, MySQL, nil, users(), Policy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(plan.Statements) != 2 || !strings.HasPrefix(plan.Statements[0].SQL, "CREATE DATABASE IF NOT EXISTS `test_db`") ||
+			!strings.HasPrefix(plan.Statements[1].SQL, "CREATE TABLE `test_db`.`users`") {
+			t.Errorf("got %+v", plan.Statements)
+		}
+	})
+
+	t.Run("destructive changes are blocked", func(t *testing.T) {
+		current := users(schema.NewStringColumn("legacy", "text"), schema.NewStringColumn("name", "varchar", schema.StringSize(255)))
+		current.AddTables(schema.NewTable("sessions").AddColumns(schema.NewIntColumn("id", "int")))
+		desired := users(schema.NewStringColumn("name", "varchar", schema.StringSize(100)))
+
+		plan, err := Diff(ctx, MySQL, current, desired, Policy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []Violation{
+			{Rule: DropColumn, Object: "users.legacy", Message: "drops column users.legacy"},
+			{Rule: ModifyType, Object: "users.name", Message: "changes the type of users.name from varchar(255) to varchar(100)"},
+			{Rule: DropTable, Object: "sessions", Message: "drops table sessions"},
+		}
+		assertViolations(t, plan.Violations, want)
+		if err := plan.Err(); !errors.Is(err, ErrBlocked) {
+			t.Errorf("got %v, want %v", err, ErrBlocked)
+		}
+
+		plan, err = Diff(ctx, MySQL, current, desired, Policy{Allow: []string{"drop_table", "drop_column:users.legacy"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertViolations(t, plan.Violations, want[1:2])
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		current := schema.New("public").AddTables(schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "integer")))
+		desired := schema.New("public").AddTables(schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "bigint")))
+		plan, err := Diff(ctx, Postgres, current, desired, Policy{Allow: []string{"modify_type:users.id"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertStatements(t, plan, `ALTER TABLE "public"."users" ALTER COLUMN "id" TYPE bigint`)
+		if err := plan.Err(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestLoadSchema(t *testing.T) {
+	s, err := MySQL.LoadSchema([]byte(`
+schema "test_db" {}
+table "users" {
+  schema = schema.test_db
+  column "id" {
+    type = int
+  }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "test_db" || len(s.Tables) != 1 || s.Tables[0].Columns[0].Name != "id" {
+		t.Errorf("got %+v", s)
+	}
+	if _, err := MySQL.LoadSchema([]byte(`table "users" {`)); err == nil {
+		t.Error("got no error for invalid HCL")
+	}
+}
+
+func TestDialectFor(t *testing.T) {
+	for _, name := range []string{"mysql", "postgres", "sqlite3"} {
+		if _, err := DialectFor(name); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := DialectFor("oracle"); !errors.Is(err, ErrUnknownDriver) {
+		t.Errorf("got %v, want %v", err, ErrUnknownDriver)
+	}
+}
+
+const sqliteSchema = `
+schema "main" {}
+table "users" {
+  schema = schema.main
+  column "id" {
+    type = int
+  }
+  column "greeting" {
+    type = text
+  }%s
+}`
+
+func TestSyncerSQLite(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	lock := "schemasync_test_" + strings.ReplaceAll(t.Name(), "/", "_")
+	s, err := New(db, SQLite, WithLock(lock, time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	desired := func(columns string) *schema.Schema {
+		t.Helper()
+		sch, err := SQLite.LoadSchema([]byte(strings.Replace(sqliteSchema, "%s", columns, 1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sch
+	}
+
+	plan, err := s.Apply(ctx, desired(`
+  column "legacy" {
+    type = text
+    null = true
+  }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertStatements(t, plan, "CREATE TABLE `users` (`id` int NOT NULL, `greeting` text NOT NULL, `legacy` text NULL)")
+
+	if plan, err := s.Plan(ctx, desired(`
+  column "legacy" {
+    type = text
+    null = true
+  }`)); err != nil || !plan.Empty() {
+		t.Fatalf("got %+v, %v after applying, want no changes", plan, err)
+	}
+
+	if _, err := s.Apply(ctx, desired("")); !errors.Is(err, ErrBlocked) {
+		t.Fatalf("got %v dropping legacy, want %v", err, ErrBlocked)
+	}
+	assertColumns(t, db, "id", "greeting", "legacy")
+
+	t.Run("concurrent applies", func(t *testing.T) {
+		unlock, err := s.driver.Lock(ctx, lock, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer unlock()
+		if _, err := s.Apply(ctx, desired("")); !errors.Is(err, ErrLocked) {
+			t.Errorf("got %v, want %v", err, ErrLocked)
+		}
+	})
+
+	s, err = New(db, SQLite, WithLock(lock, time.Second), WithPolicy(Policy{Allow: []string{"drop_column:users.legacy"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Apply(ctx, desired("")); err != nil {
+		t.Fatal(err)
+	}
+	assertColumns(t, db, "id", "greeting")
+}
+
+func assertStatements(t testing.TB, plan *Plan, want ...string) {
+	t.Helper()
+	var got []string
+	for _, s := range plan.Statements {
+		got = append(got, s.SQL)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func assertViolations(t testing.TB, got, want []Violation) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func assertColumns(t testing.TB, db *sql.DB, want ...string) {
+	t.Helper()
+	rows, err := db.Query("SELECT name FROM pragma_table_info('users')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, name)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got columns %v, want %v", got, want)
+	}
+}
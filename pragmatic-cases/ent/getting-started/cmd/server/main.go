@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"tmp/pragmatic-cases/ent/getting-started/ent"
+	"tmp/pragmatic-cases/ent/getting-started/ent/migrate"
+	"tmp/pragmatic-cases/ent/getting-started/graph"
+	"tmp/pragmatic-cases/health"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/playground"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const defaultPort = "8080"
+
+var (
+	driver string
+	dsn    string
+)
+
+func init() {
+	flag.StringVar(&driver, "driver", "sqlite3", "sqlite3 or postgres")
+	flag.StringVar(&dsn, "dsn", "file:ent?mode=memory&cache=shared&_fk=1", "data source name, e.g. host=localhost port=5432 user=postgres dbname=ent_getting_started password=postgres sslmode=disable")
+}
+
+func main() {
+	flag.Parse()
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = defaultPort
+	}
+
+	client, err := ent.Open(driver, dsn)
+	if err != nil {
+		log.Fatalf("failed opening connection to %s: %v", driver, err)
+	}
+	defer client.Close()
+	// Global unique IDs let node(id:) tell which table an ID is from.
+	if err := client.Schema.Create(context.Background(), migrate.WithGlobalUniqueID(true)); err != nil {
+		log.Fatalf("failed creating schema resources: %v", err)
+	}
+
+	srv := handler.NewDefaultServer(graph.NewSchema(client))
+
+	http.Handle("/", playground.Handler("ent GraphQL playground", "/query"))
+	http.Handle("/query", srv)
+	health.New().Mount(http.DefaultServeMux)
+
+	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
+	log.Fatal(http.ListenAndServe(":"+port, nil))
+}
@@ -9,6 +9,7 @@ import (
 	"tmp/pragmatic-cases/ent/getting-started/ent/car"
 	"tmp/pragmatic-cases/ent/getting-started/ent/user"
 
+	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 )
 
@@ -23,8 +24,9 @@ type Car struct {
 	RegisteredAt time.Time `json:"registered_at,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
 	// The values are being populated by the CarQuery when eager-loading is set.
-	Edges     CarEdges `json:"edges"`
-	user_cars *int
+	Edges        CarEdges `json:"edges"`
+	user_cars    *int
+	selectValues sql.SelectValues
 }
 
 // CarEdges holds the relations/edges for other nodes in the graph.
@@ -34,17 +36,17 @@ type CarEdges struct {
 	// loadedTypes holds the information for reporting if a
 	// type was loaded (or requested) in eager-loading or not.
 	loadedTypes [1]bool
+	// totalCount holds the count of the edges above.
+	totalCount [1]map[string]int
 }
 
 // OwnerOrErr returns the Owner value or an error if the edge
 // was not loaded in eager-loading, or loaded but was not found.
 func (e CarEdges) OwnerOrErr() (*User, error) {
-	if e.loadedTypes[0] {
-		if e.Owner == nil {
-			// Edge was loaded but was not found.
-			return nil, &NotFoundError{label: user.Label}
-		}
+	if e.Owner != nil {
 		return e.Owner, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: user.Label}
 	}
 	return nil, &NotLoadedError{edge: "owner"}
 }
@@ -63,7 +65,7 @@ func (*Car) scanValues(columns []string) ([]any, error) {
 		case car.ForeignKeys[0]: // user_cars
 			values[i] = new(sql.NullInt64)
 		default:
-			return nil, fmt.Errorf("unexpected column %q for type Car", columns[i])
+			values[i] = new(sql.UnknownType)
 		}
 	}
 	return values, nil
@@ -102,21 +104,29 @@ func (c *Car) assignValues(columns []string, values []any) error {
 				c.user_cars = new(int)
 				*c.user_cars = int(value.Int64)
 			}
+		default:
+			c.selectValues.Set(columns[i], values[i])
 		}
 	}
 	return nil
 }
 
+// Value returns the ent.Value that was dynamically selected and assigned to the Car.
+// This includes values selected through modifiers, order, etc.
+func (c *Car) Value(name string) (ent.Value, error) {
+	return c.selectValues.Get(name)
+}
+
 // QueryOwner queries the "owner" edge of the Car entity.
 func (c *Car) QueryOwner() *UserQuery {
-	return (&CarClient{config: c.config}).QueryOwner(c)
+	return NewCarClient(c.config).QueryOwner(c)
 }
 
 // Update returns a builder for updating this Car.
 // Note that you need to call Car.Unwrap() before calling this method if this Car
 // was returned from a transaction, and the transaction was committed or rolled back.
 func (c *Car) Update() *CarUpdateOne {
-	return (&CarClient{config: c.config}).UpdateOne(c)
+	return NewCarClient(c.config).UpdateOne(c)
 }
 
 // Unwrap unwraps the Car entity that was returned from a transaction after it was closed,
@@ -146,9 +156,3 @@ func (c *Car) String() string {
 
 // Cars is a parsable slice of Car.
 type Cars []*Car
-
-func (c Cars) config(cfg config) {
-	for _i := range c {
-		c[_i].config = cfg
-	}
-}
@@ -0,0 +1,29 @@
+//go:build ignore
+
+package main
+
+import (
+	"log"
+
+	"entgo.io/contrib/entgql"
+	"entgo.io/ent/entc"
+	"entgo.io/ent/entc/gen"
+)
+
+// entc generates the ent code, and with entgql the GraphQL schema of the
+// entities (graph/ent.graphql), their connections, where inputs and create
+// inputs, for gqlgen to bind.
+func main() {
+	ex, err := entgql.NewExtension(
+		entgql.WithSchemaGenerator(),
+		entgql.WithWhereInputs(true),
+		entgql.WithSchemaPath("../graph/ent.graphql"),
+		entgql.WithConfigPath("../gqlgen.yml"),
+	)
+	if err != nil {
+		log.Fatalf("creating entgql extension: %v", err)
+	}
+	if err := entc.Generate("./schema", &gen.Config{}, entc.Extensions(ex)); err != nil {
+		log.Fatalf("running ent codegen: %v", err)
+	}
+}
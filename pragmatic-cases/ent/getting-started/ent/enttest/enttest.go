@@ -4,6 +4,7 @@ package enttest
 
 import (
 	"context"
+
 	"tmp/pragmatic-cases/ent/getting-started/ent"
 	// required by schema hooks.
 	_ "tmp/pragmatic-cases/ent/getting-started/ent/runtime"
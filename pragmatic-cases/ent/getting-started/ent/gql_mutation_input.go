@@ -0,0 +1,77 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"time"
+)
+
+// CreateCarInput represents a mutation input for creating cars.
+type CreateCarInput struct {
+	Model        string
+	RegisteredAt time.Time
+	OwnerID      *int
+}
+
+// Mutate applies the CreateCarInput on the CarMutation builder.
+func (i *CreateCarInput) Mutate(m *CarMutation) {
+	m.SetModel(i.Model)
+	m.SetRegisteredAt(i.RegisteredAt)
+	if v := i.OwnerID; v != nil {
+		m.SetOwnerID(*v)
+	}
+}
+
+// SetInput applies the change-set in the CreateCarInput on the CarCreate builder.
+func (c *CarCreate) SetInput(i CreateCarInput) *CarCreate {
+	i.Mutate(c.Mutation())
+	return c
+}
+
+// CreateGroupInput represents a mutation input for creating groups.
+type CreateGroupInput struct {
+	Name    string
+	UserIDs []int
+}
+
+// Mutate applies the CreateGroupInput on the GroupMutation builder.
+func (i *CreateGroupInput) Mutate(m *GroupMutation) {
+	m.SetName(i.Name)
+	if v := i.UserIDs; len(v) > 0 {
+		m.AddUserIDs(v...)
+	}
+}
+
+// SetInput applies the change-set in the CreateGroupInput on the GroupCreate builder.
+func (c *GroupCreate) SetInput(i CreateGroupInput) *GroupCreate {
+	i.Mutate(c.Mutation())
+	return c
+}
+
+// CreateUserInput represents a mutation input for creating users.
+type CreateUserInput struct {
+	Age      int
+	Name     *string
+	CarIDs   []int
+	GroupIDs []int
+}
+
+// Mutate applies the CreateUserInput on the UserMutation builder.
+func (i *CreateUserInput) Mutate(m *UserMutation) {
+	m.SetAge(i.Age)
+	if v := i.Name; v != nil {
+		m.SetName(*v)
+	}
+	if v := i.CarIDs; len(v) > 0 {
+		m.AddCarIDs(v...)
+	}
+	if v := i.GroupIDs; len(v) > 0 {
+		m.AddGroupIDs(v...)
+	}
+}
+
+// SetInput applies the change-set in the CreateUserInput on the UserCreate builder.
+func (c *UserCreate) SetInput(i CreateUserInput) *UserCreate {
+	i.Mutate(c.Mutation())
+	return c
+}
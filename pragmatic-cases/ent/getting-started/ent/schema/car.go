@@ -1,7 +1,9 @@
 package schema
 
 import (
+	"entgo.io/contrib/entgql"
 	"entgo.io/ent"
+	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 )
@@ -14,8 +16,10 @@ type Car struct {
 // Fields of the Car.
 func (Car) Fields() []ent.Field {
 	return []ent.Field{
-		field.String("model"),
-		field.Time("registered_at"),
+		field.String("model").
+			Annotations(entgql.OrderField("MODEL")),
+		field.Time("registered_at").
+			Annotations(entgql.OrderField("REGISTERED_AT")),
 	}
 }
 
@@ -32,3 +36,12 @@ func (Car) Edges() []ent.Edge {
 			Unique(),
 	}
 }
+
+// Annotations of the Car.
+func (Car) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.QueryField(),
+		entgql.RelayConnection(),
+		entgql.Mutations(entgql.MutationCreate()),
+	}
+}
@@ -3,7 +3,9 @@ package schema
 import (
 	"regexp"
 
+	"entgo.io/contrib/entgql"
 	"entgo.io/ent"
+	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 )
@@ -18,13 +20,24 @@ func (Group) Fields() []ent.Field {
 	return []ent.Field{
 		field.String("name").
 			// Regexp validation for group name.
-			Match(regexp.MustCompile("[a-zA-Z_]+$")),
+			Match(regexp.MustCompile("[a-zA-Z_]+$")).
+			Annotations(entgql.OrderField("NAME")),
 	}
 }
 
 // Edges of the Group.
 func (Group) Edges() []ent.Edge {
 	return []ent.Edge{
-		edge.To("users", User.Type),
+		edge.To("users", User.Type).
+			Annotations(entgql.RelayConnection()),
+	}
+}
+
+// Annotations of the Group.
+func (Group) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.QueryField(),
+		entgql.RelayConnection(),
+		entgql.Mutations(entgql.MutationCreate()),
 	}
 }
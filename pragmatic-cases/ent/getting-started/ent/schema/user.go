@@ -1,7 +1,9 @@
 package schema
 
 import (
+	"entgo.io/contrib/entgql"
 	"entgo.io/ent"
+	"entgo.io/ent/schema"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 )
@@ -15,20 +17,34 @@ type User struct {
 func (User) Fields() []ent.Field {
 	return []ent.Field{
 		field.Int("age").
-			Positive(),
+			Positive().
+			Annotations(entgql.OrderField("AGE")),
 		field.String("name").
-			Default("unknown"),
+			Default("unknown").
+			Annotations(entgql.OrderField("NAME")),
 	}
 }
 
 // Edges of the User.
 func (User) Edges() []ent.Edge {
 	return []ent.Edge{
-		edge.To("cars", Car.Type),
+		edge.To("cars", Car.Type).
+			Annotations(entgql.RelayConnection()),
 		// Create an inverse-edge called "groups" of type `Group`
 		// and reference it to the "users" edge (in Group schema)
 		// explicitly using the `Ref` method.
 		edge.From("groups", Group.Type).
-			Ref("users"),
+			Ref("users").
+			Annotations(entgql.RelayConnection()),
+	}
+}
+
+// Annotations of the User: a users connection on Query, and a createUser
+// input.
+func (User) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entgql.QueryField(),
+		entgql.RelayConnection(),
+		entgql.Mutations(entgql.MutationCreate()),
 	}
 }
@@ -0,0 +1,21 @@
+package graph
+
+//go:generate go run github.com/99designs/gqlgen generate
+
+import (
+	"tmp/pragmatic-cases/ent/getting-started/ent"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// Resolver resolves the queries and mutations with the ent client. The
+// fields of the entities and their edges are methods of the ent types,
+// generated by entgql, so they need no resolver.
+type Resolver struct {
+	client *ent.Client
+}
+
+// NewSchema returns the executable schema of the ent graph.
+func NewSchema(client *ent.Client) graphql.ExecutableSchema {
+	return NewExecutableSchema(Config{Resolvers: &Resolver{client: client}})
+}
@@ -0,0 +1,215 @@
+package graph
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"tmp/pragmatic-cases/ent/getting-started/ent/enttest"
+	"tmp/pragmatic-cases/ent/getting-started/ent/migrate"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newClient(t *testing.T) *client.Client {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared&_fk=1", t.Name())
+	ec := enttest.Open(t, "sqlite3", dsn, enttest.WithMigrateOptions(migrate.WithGlobalUniqueID(true)))
+	t.Cleanup(func() { ec.Close() })
+	return client.New(handler.NewDefaultServer(NewSchema(ec)))
+}
+
+// createGraph builds the graph of start.CreateGraph with mutations: Ariel
+// owns a Tesla and a Mazda, Neta a Ford, both are in GitLab, and Ariel in
+// GitHub too.
+func createGraph(t *testing.T, c *client.Client) {
+	t.Helper()
+	var resp struct {
+		CreateCar   struct{ ID string }
+		CreateUser  struct{ ID string }
+		CreateGroup struct{ ID string }
+	}
+	car := func(model string) string {
+		c.MustPost(`mutation($model: String!) {
+			createCar(input: {model: $model, registeredAt: "2024-01-01T00:00:00Z"}) { id }
+		}`, &resp, client.Var("model", model))
+		return resp.CreateCar.ID
+	}
+	user := func(name string, age int, cars ...string) string {
+		c.MustPost(`mutation($name: String!, $age: Int!, $cars: [ID!]) {
+			createUser(input: {name: $name, age: $age, carIDs: $cars}) { id }
+		}`, &resp, client.Var("name", name), client.Var("age", age), client.Var("cars", cars))
+		return resp.CreateUser.ID
+	}
+	group := func(name string, users ...string) {
+		c.MustPost(`mutation($name: String!, $users: [ID!]) {
+			createGroup(input: {name: $name, userIDs: $users}) { id }
+		}`, &resp, client.Var("name", name), client.Var("users", users))
+	}
+
+	ariel := user("Ariel", 30, car("Tesla"), car("Mazda"))
+	neta := user("Neta", 28, car("Ford"))
+	group("GitLab", neta, ariel)
+	group("GitHub", ariel)
+}
+
+type carConnection struct {
+	TotalCount int
+	Edges      []struct{ Node struct{ Model string } }
+}
+
+func (c carConnection) models() []string {
+	var models []string
+	for _, e := range c.Edges {
+		models = append(models, e.Node.Model)
+	}
+	return models
+}
+
+func TestTraversals(t *testing.T) {
+	c := newClient(t)
+	createGraph(t, c)
+
+	t.Run("the cars of GitHub users", func(t *testing.T) {
+		// start.QueryGithub
+		var resp struct {
+			Groups struct {
+				Edges []struct {
+					Node struct {
+						Users struct {
+							Edges []struct{ Node struct{ Cars carConnection } }
+						}
+					}
+				}
+			}
+		}
+		c.MustPost(`{
+			groups(where: {name: "GitHub"}) { edges { node {
+				users { edges { node {
+					cars(orderBy: {field: MODEL}) { edges { node { model } } }
+				} } }
+			} } }
+		}`, &resp)
+		var models []string
+		for _, g := range resp.Groups.Edges {
+			for _, u := range g.Node.Users.Edges {
+				models = append(models, u.Node.Cars.models()...)
+			}
+		}
+		assertModels(t, models, "Mazda", "Tesla")
+	})
+
+	t.Run("the cars of Ariel's groups but Mazdas", func(t *testing.T) {
+		// start.QueryArielCars, as one where input
+		var resp struct{ Cars carConnection }
+		c.MustPost(`{
+			cars(
+				where: {
+					modelNEQ: "Mazda",
+					hasOwnerWith: {hasGroupsWith: {hasUsersWith: {name: "Ariel", hasCars: true}}}
+				},
+				orderBy: {field: MODEL}
+			) { totalCount edges { node { model } } }
+		}`, &resp)
+		assertModels(t, resp.Cars.models(), "Ford", "Tesla")
+		if resp.Cars.TotalCount != 2 {
+			t.Errorf("got totalCount %d, want 2", resp.Cars.TotalCount)
+		}
+	})
+
+	t.Run("groups with users", func(t *testing.T) {
+		// start.QueryGroupWithUsers
+		var resp struct {
+			Groups struct {
+				Edges []struct{ Node struct{ Name string } }
+			}
+		}
+		c.MustPost(`{ groups(where: {hasUsers: true}, orderBy: {field: NAME, direction: DESC}) { edges { node { name } } } }`, &resp)
+		var names []string
+		for _, e := range resp.Groups.Edges {
+			names = append(names, e.Node.Name)
+		}
+		assertModels(t, names, "GitLab", "GitHub")
+	})
+
+	t.Run("node", func(t *testing.T) {
+		var resp struct {
+			Cars struct {
+				Edges []struct{ Node struct{ ID string } }
+			}
+			Nodes []struct {
+				Typename string `json:"__typename"`
+				Model    string
+			}
+		}
+		c.MustPost(`{ cars(where: {model: "Ford"}) { edges { node { id } } } }`, &resp)
+		id := resp.Cars.Edges[0].Node.ID
+		c.MustPost(`query($id: ID!) { nodes(ids: [$id]) { __typename ... on Car { model } } }`, &resp, client.Var("id", id))
+		if len(resp.Nodes) != 1 || resp.Nodes[0].Typename != "Car" || resp.Nodes[0].Model != "Ford" {
+			t.Errorf("got %+v", resp.Nodes)
+		}
+	})
+}
+
+func TestPagination(t *testing.T) {
+	c := newClient(t)
+	createGraph(t, c)
+
+	type page struct {
+		Cars struct {
+			TotalCount int
+			Edges      []struct{ Node struct{ Model string } }
+			PageInfo   struct {
+				HasNextPage bool
+				EndCursor   *string
+			}
+		}
+	}
+	models := func(p page) []string {
+		return carConnection{Edges: p.Cars.Edges}.models()
+	}
+	query := `query($after: Cursor) {
+		cars(first: 2, after: $after, orderBy: {field: MODEL}) {
+			totalCount
+			edges { node { model } }
+			pageInfo { hasNextPage endCursor }
+		}
+	}`
+
+	var first page
+	c.MustPost(query, &first, client.Var("after", nil))
+	assertModels(t, models(first), "Ford", "Mazda")
+	if !first.Cars.PageInfo.HasNextPage || first.Cars.PageInfo.EndCursor == nil || first.Cars.TotalCount != 3 {
+		t.Fatalf("got %+v", first.Cars)
+	}
+
+	var second page
+	c.MustPost(query, &second, client.Var("after", *first.Cars.PageInfo.EndCursor))
+	assertModels(t, models(second), "Tesla")
+	if second.Cars.PageInfo.HasNextPage {
+		t.Errorf("got a next page after the last car")
+	}
+}
+
+func TestCreateValidation(t *testing.T) {
+	c := newClient(t)
+	var resp struct{ CreateGroup struct{ ID string } }
+	// the name of a group must match [a-zA-Z_]+$
+	err := c.Post(`mutation { createGroup(input: {name: "123"}) { id } }`, &resp)
+	if err == nil {
+		t.Error("got no error for an invalid group name")
+	}
+	// the age of a user must be positive
+	err = c.Post(`mutation { createUser(input: {name: "a8m", age: 0}) { id } }`, &resp)
+	if err == nil {
+		t.Error("got no error for a user of age 0")
+	}
+}
+
+func assertModels(t testing.TB, got []string, want ...string) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,158 @@
+// Package dataloader batches the loads of one request: the keys asked for
+// within a short wait are fetched with one call, and each result is cached
+// for the rest of the request.
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrNotFound = errors.New("dataloader: not found")
+
+// FetchFunc returns the values of keys. Keys it leaves out of the map are
+// not found.
+type FetchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader loads values by key in batches. It is safe for concurrent use, and
+// is meant to live as long as a request, as its cache is never invalidated.
+type Loader[K comparable, V any] struct {
+	fetch    FetchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	cache map[K]*result[V]
+	batch *batch[K, V]
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	ctx     context.Context
+	keys    []K
+	results map[K]*result[V]
+	timer   *time.Timer
+}
+
+type Option func(*options)
+
+type options struct {
+	wait     time.Duration
+	maxBatch int
+}
+
+// WithWait sets how long a batch waits for more keys, 2ms by default.
+func WithWait(d time.Duration) Option {
+	return func(o *options) { o.wait = d }
+}
+
+// WithMaxBatch sets the most keys of a fetch, 100 by default.
+func WithMaxBatch(n int) Option {
+	return func(o *options) { o.maxBatch = n }
+}
+
+func New[K comparable, V any](fetch FetchFunc[K, V], opts ...Option) *Loader[K, V] {
+	o := options{wait: 2 * time.Millisecond, maxBatch: 100}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Loader[K, V]{fetch: fetch, wait: o.wait, maxBatch: o.maxBatch, cache: map[K]*result[V]{}}
+}
+
+// Load returns the value of key, fetched with the other keys loaded within
+// the wait. A key not returned by the fetch is an ErrNotFound.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	r, ok := l.cache[key]
+	if !ok {
+		r = &result[V]{done: make(chan struct{})}
+		l.cache[key] = r
+		l.add(ctx, key, r)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// LoadAll loads keys in one batch, and returns the values in their order.
+func (l *Loader[K, V]) LoadAll(ctx context.Context, keys []K) ([]V, error) {
+	values := make([]V, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = l.Load(ctx, key)
+		}()
+	}
+	wg.Wait()
+	return values, errors.Join(errs...)
+}
+
+// add puts key in the current batch, and starts the batch when it is the
+// first key or runs it when it is full. l.mu is held.
+func (l *Loader[K, V]) add(ctx context.Context, key K, r *result[V]) {
+	if l.batch == nil {
+		// the fetch must not be cancelled with the load that started the
+		// batch, when others wait on it too
+		b := &batch[K, V]{ctx: context.WithoutCancel(ctx), results: map[K]*result[V]{}}
+		b.timer = time.AfterFunc(l.wait, func() { l.run(b) })
+		l.batch = b
+	}
+	b := l.batch
+	b.keys = append(b.keys, key)
+	b.results[key] = r
+	if len(b.keys) >= l.maxBatch && b.timer.Stop() {
+		l.batch = nil
+		go l.do(b)
+	}
+}
+
+// run is the end of the wait of b.
+func (l *Loader[K, V]) run(b *batch[K, V]) {
+	l.mu.Lock()
+	if l.batch == b {
+		l.batch = nil
+	}
+	l.mu.Unlock()
+	l.do(b)
+}
+
+func (l *Loader[K, V]) do(b *batch[K, V]) {
+	values, err := l.fetch(b.ctx, b.keys)
+	if err != nil {
+		// a failed fetch is not cached: the next load tries again
+		l.mu.Lock()
+		for _, key := range b.keys {
+			delete(l.cache, key)
+		}
+		l.mu.Unlock()
+	}
+	for _, key := range b.keys {
+		r := b.results[key]
+		switch v, ok := values[key]; {
+		case err != nil:
+			r.err = err
+		case !ok:
+			r.err = fmt.Errorf("%w: %v", ErrNotFound, key)
+		default:
+			r.value = v
+		}
+		close(r.done)
+	}
+}
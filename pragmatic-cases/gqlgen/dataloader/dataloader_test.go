@@ -0,0 +1,124 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// spyFetch records the batches it is called with.
+type spyFetch struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (s *spyFetch) fetch(ctx context.Context, keys []int) (map[int]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	batch := append([]int(nil), keys...)
+	sort.Ints(batch)
+	s.batches = append(s.batches, batch)
+	if s.err != nil {
+		return nil, s.err
+	}
+	values := map[int]string{}
+	for _, k := range keys {
+		if k > 0 {
+			values[k] = string(rune('a' + k - 1))
+		}
+	}
+	return values, nil
+}
+
+func (s *spyFetch) calls() [][]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.batches
+}
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("concurrent loads are one fetch", func(t *testing.T) {
+		spy := &spyFetch{}
+		l := New(spy.fetch, WithWait(10*time.Millisecond))
+		got, err := l.LoadAll(ctx, []int{1, 2, 3, 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, got, []string{"a", "b", "c", "b"})
+		assertEqual(t, spy.calls(), [][]int{{1, 2, 3}})
+
+		// cached for the rest of the request
+		if v, err := l.Load(ctx, 2); v != "b" || err != nil {
+			t.Errorf("got %q, %v", v, err)
+		}
+		assertEqual(t, spy.calls(), [][]int{{1, 2, 3}})
+	})
+
+	t.Run("full batches do not wait", func(t *testing.T) {
+		spy := &spyFetch{}
+		l := New(spy.fetch, WithWait(time.Hour), WithMaxBatch(2))
+		if _, err := l.LoadAll(ctx, []int{1, 2}); err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, spy.calls(), [][]int{{1, 2}})
+	})
+
+	t.Run("missing keys are not found", func(t *testing.T) {
+		spy := &spyFetch{}
+		l := New(spy.fetch)
+		if _, err := l.Load(ctx, -1); !errors.Is(err, ErrNotFound) {
+			t.Errorf("got %v, want %v", err, ErrNotFound)
+		}
+	})
+
+	t.Run("failed fetches are retried", func(t *testing.T) {
+		spy := &spyFetch{err: errors.New("connection refused")}
+		l := New(spy.fetch)
+		if _, err := l.Load(ctx, 1); !errors.Is(err, spy.err) {
+			t.Errorf("got %v, want %v", err, spy.err)
+		}
+		spy.mu.Lock()
+		spy.err = nil
+		spy.mu.Unlock()
+		if v, err := l.Load(ctx, 1); v != "a" || err != nil {
+			t.Errorf("got %q, %v after the fetch recovered", v, err)
+		}
+	})
+
+	t.Run("a cancelled load does not cancel the batch", func(t *testing.T) {
+		spy := &spyFetch{}
+		l := New(spy.fetch, WithWait(20*time.Millisecond))
+		cancelled, cancel := context.WithCancel(ctx)
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			cancel()
+		}()
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := l.Load(cancelled, 1); !errors.Is(err, context.Canceled) {
+				t.Errorf("got %v, want %v", err, context.Canceled)
+			}
+		}()
+		time.Sleep(time.Millisecond)
+		if v, err := l.Load(ctx, 1); v != "a" || err != nil {
+			t.Errorf("got %q, %v", v, err)
+		}
+		wg.Wait()
+	})
+}
+
+func assertEqual[T any](t testing.TB, got, want T) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,33 @@
+package graph
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+const (
+	defaultFirst = 20
+	maxFirst     = 100
+
+	cursorPrefix = "todo:"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// Cursors are opaque to clients, so the store can change what it pages by.
+func encodeCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + id))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", errInvalidCursor
+	}
+	id, ok := strings.CutPrefix(string(b), cursorPrefix)
+	if !ok || id == "" {
+		return "", errInvalidCursor
+	}
+	return id, nil
+}
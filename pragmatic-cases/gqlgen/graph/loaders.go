@@ -0,0 +1,35 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"tmp/pragmatic-cases/gqlgen/dataloader"
+	"tmp/pragmatic-cases/gqlgen/graph/model"
+	"tmp/pragmatic-cases/gqlgen/store"
+)
+
+type loadersKey struct{}
+
+// Loaders batches the loads of one request, so the user of every todo in a
+// list is one query instead of one per todo.
+type Loaders struct {
+	User *dataloader.Loader[string, *model.User]
+}
+
+func NewLoaders(s *store.Store) *Loaders {
+	return &Loaders{User: dataloader.New(s.UsersByIDs)}
+}
+
+// Middleware gives every request its own loaders: the cache of a loader is
+// never invalidated, so it must not outlive the request.
+func Middleware(s *store.Store, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), loadersKey{}, NewLoaders(s))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// For returns the loaders of the request of ctx.
+func For(ctx context.Context) *Loaders {
+	return ctx.Value(loadersKey{}).(*Loaders)
+}
@@ -2,11 +2,47 @@
 
 package model
 
+type Mutation struct {
+}
+
 type NewTodo struct {
 	Text   string `json:"text"`
 	UserID string `json:"userId"`
 }
 
+type NewUser struct {
+	Name string `json:"name"`
+}
+
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor,omitempty"`
+}
+
+type Query struct {
+}
+
+type TodoConnection struct {
+	Edges    []*TodoEdge `json:"edges"`
+	PageInfo *PageInfo   `json:"pageInfo"`
+}
+
+type TodoEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Todo  `json:"node"`
+}
+
+type TodoFilter struct {
+	Done         *bool   `json:"done,omitempty"`
+	UserID       *string `json:"userId,omitempty"`
+	TextContains *string `json:"textContains,omitempty"`
+}
+
+type UpdateTodo struct {
+	Text *string `json:"text,omitempty"`
+	Done *bool   `json:"done,omitempty"`
+}
+
 type User struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
@@ -1,9 +1,10 @@
 package model
 
+// Todo has the ID of its user only: the user field is resolved through the
+// user dataloader.
 type Todo struct {
 	ID     string `json:"id"`
 	Text   string `json:"text"`
 	Done   bool   `json:"done"`
 	UserID string `json:"userId"`
-	User   *User  `json:"user"`
 }
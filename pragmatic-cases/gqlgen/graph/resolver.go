@@ -2,8 +2,21 @@ package graph
 
 //go:generate go run github.com/99designs/gqlgen generate
 
-import "tmp/pragmatic-cases/gqlgen/graph/model"
+import (
+	"net/http"
+	"tmp/pragmatic-cases/gqlgen/store"
 
+	"github.com/99designs/gqlgen/graphql/handler"
+)
+
+// Resolver keeps no state of its own: the todos are in the store, so
+// concurrent requests see the same data and a restart keeps it.
 type Resolver struct {
-	todos []*model.Todo
+	Store *store.Store
+}
+
+// NewHandler serves the schema over s, with a user loader per request.
+func NewHandler(s *store.Store) http.Handler {
+	srv := handler.NewDefaultServer(NewExecutableSchema(Config{Resolvers: &Resolver{Store: s}}))
+	return Middleware(s, srv)
 }
@@ -2,35 +2,87 @@ package graph
 
 // This file will be automatically regenerated based on the schema, any resolver implementations
 // will be copied through when generating and any unknown code will be moved to the end.
-// Code generated by github.com/99designs/gqlgen version v0.17.38
+// Code generated by github.com/99designs/gqlgen version v0.17.66
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"math/rand"
 	"tmp/pragmatic-cases/gqlgen/graph/model"
+	"tmp/pragmatic-cases/gqlgen/store"
 )
 
+// CreateUser is the resolver for the createUser field.
+func (r *mutationResolver) CreateUser(ctx context.Context, input model.NewUser) (*model.User, error) {
+	return r.Store.CreateUser(ctx, input.Name)
+}
+
 // CreateTodo is the resolver for the createTodo field.
 func (r *mutationResolver) CreateTodo(ctx context.Context, input model.NewTodo) (*model.Todo, error) {
-	todo := &model.Todo{
-		Text:   input.Text,
-		ID:     fmt.Sprintf("T%d", rand.Int()),
-		User:   &model.User{ID: input.UserID, Name: "user " + input.UserID},
-		UserID: input.UserID,
-	}
-	r.todos = append(r.todos, todo)
-	return todo, nil
+	return r.Store.CreateTodo(ctx, input)
+}
+
+// UpdateTodo is the resolver for the updateTodo field.
+func (r *mutationResolver) UpdateTodo(ctx context.Context, id string, input model.UpdateTodo) (*model.Todo, error) {
+	return r.Store.UpdateTodo(ctx, id, input)
+}
+
+// DeleteTodo is the resolver for the deleteTodo field.
+func (r *mutationResolver) DeleteTodo(ctx context.Context, id string) (*model.Todo, error) {
+	return r.Store.DeleteTodo(ctx, id)
+}
+
+// ToggleDone is the resolver for the toggleDone field.
+func (r *mutationResolver) ToggleDone(ctx context.Context, id string) (*model.Todo, error) {
+	return r.Store.ToggleDone(ctx, id)
 }
 
 // Todos is the resolver for the todos field.
-func (r *queryResolver) Todos(ctx context.Context) ([]*model.Todo, error) {
-	return r.todos, nil
+func (r *queryResolver) Todos(ctx context.Context, filter *model.TodoFilter, first *int, after *string) (*model.TodoConnection, error) {
+	n := defaultFirst
+	if first != nil {
+		n = *first
+	}
+	if n < 0 || n > maxFirst {
+		return nil, fmt.Errorf("first must be between 0 and %d", maxFirst)
+	}
+	var afterID string
+	if after != nil {
+		id, err := decodeCursor(*after)
+		if err != nil {
+			return nil, err
+		}
+		afterID = id
+	}
+	page, err := r.Store.Todos(ctx, filter, n, afterID)
+	if err != nil {
+		return nil, err
+	}
+	conn := &model.TodoConnection{
+		Edges:    make([]*model.TodoEdge, 0, len(page.Todos)),
+		PageInfo: &model.PageInfo{HasNextPage: page.HasNext},
+	}
+	for _, todo := range page.Todos {
+		conn.Edges = append(conn.Edges, &model.TodoEdge{Cursor: encodeCursor(todo.ID), Node: todo})
+	}
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.EndCursor = &conn.Edges[len(conn.Edges)-1].Cursor
+	}
+	return conn, nil
+}
+
+// Todo is the resolver for the todo field.
+func (r *queryResolver) Todo(ctx context.Context, id string) (*model.Todo, error) {
+	todo, err := r.Store.Todo(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
+	return todo, err
 }
 
 // User is the resolver for the user field.
 func (r *todoResolver) User(ctx context.Context, obj *model.Todo) (*model.User, error) {
-	panic(fmt.Errorf("not implemented: User - user"))
+	return For(ctx).User.Load(ctx, obj.UserID)
 }
 
 // Mutation returns MutationResolver implementation.
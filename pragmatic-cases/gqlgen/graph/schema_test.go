@@ -0,0 +1,145 @@
+package graph
+
+import (
+	"testing"
+	"tmp/pragmatic-cases/gqlgen/store"
+
+	"github.com/99designs/gqlgen/client"
+)
+
+func newClient(t *testing.T) *client.Client {
+	t.Helper()
+	s, err := store.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return client.New(NewHandler(s))
+}
+
+type todo struct {
+	ID   string
+	Text string
+	Done bool
+	User struct {
+		ID   string
+		Name string
+	}
+}
+
+type connection struct {
+	Todos struct {
+		Edges []struct {
+			Cursor string
+			Node   todo
+		}
+		PageInfo struct {
+			HasNextPage bool
+			EndCursor   *string
+		}
+	}
+}
+
+const todosQuery = `query($filter: TodoFilter, $first: Int, $after: String) {
+	todos(filter: $filter, first: $first, after: $after) {
+		edges { cursor node { id text done user { id name } } }
+		pageInfo { hasNextPage endCursor }
+	}
+}`
+
+func createUser(c *client.Client, name string) string {
+	var resp struct{ CreateUser struct{ ID string } }
+	c.MustPost(`mutation($name: String!) { createUser(input: { name: $name }) { id } }`, &resp, client.Var("name", name))
+	return resp.CreateUser.ID
+}
+
+func createTodo(c *client.Client, text, userID string) todo {
+	var resp struct{ CreateTodo todo }
+	c.MustPost(`mutation($text: String!, $user: String!) {
+		createTodo(input: { text: $text, userId: $user }) { id text done user { id name } }
+	}`, &resp, client.Var("text", text), client.Var("user", userID))
+	return resp.CreateTodo
+}
+
+func TestTodos(t *testing.T) {
+	c := newClient(t)
+	alice := createUser(c, "alice")
+	bob := createUser(c, "bob")
+
+	milk := createTodo(c, "Buy milk", alice)
+	if milk.User.Name != "alice" || milk.Done {
+		t.Errorf("created %+v", milk)
+	}
+	tests := createTodo(c, "Write tests", bob)
+	createTodo(c, "Buy bread", alice)
+
+	var toggled struct{ ToggleDone todo }
+	c.MustPost(`mutation($id: ID!) { toggleDone(id: $id) { done } }`, &toggled, client.Var("id", milk.ID))
+	if !toggled.ToggleDone.Done {
+		t.Error("toggleDone did not set done")
+	}
+
+	var updated struct{ UpdateTodo todo }
+	c.MustPost(`mutation($id: ID!) { updateTodo(id: $id, input: { text: "Write more tests" }) { text done } }`,
+		&updated, client.Var("id", tests.ID))
+	if updated.UpdateTodo.Text != "Write more tests" || updated.UpdateTodo.Done {
+		t.Errorf("updated %+v", updated.UpdateTodo)
+	}
+
+	// pages of two, each user loaded through the loader
+	var texts []string
+	var after *string
+	for pages := 0; ; pages++ {
+		if pages > 2 {
+			t.Fatal("too many pages")
+		}
+		var resp connection
+		c.MustPost(todosQuery, &resp, client.Var("first", 2), client.Var("after", after))
+		for _, e := range resp.Todos.Edges {
+			texts = append(texts, e.Node.Text+" by "+e.Node.User.Name)
+		}
+		if !resp.Todos.PageInfo.HasNextPage {
+			break
+		}
+		after = resp.Todos.PageInfo.EndCursor
+	}
+	want := []string{"Buy milk by alice", "Write more tests by bob", "Buy bread by alice"}
+	if len(texts) != len(want) {
+		t.Fatalf("got %v, want %v", texts, want)
+	}
+	for i := range want {
+		if texts[i] != want[i] {
+			t.Errorf("got %v, want %v", texts, want)
+			break
+		}
+	}
+
+	var filtered connection
+	c.MustPost(todosQuery, &filtered, client.Var("filter", map[string]any{"userId": alice, "done": false}))
+	if n := len(filtered.Todos.Edges); n != 1 || filtered.Todos.Edges[0].Node.Text != "Buy bread" {
+		t.Errorf("got %+v for alice's open todos", filtered.Todos.Edges)
+	}
+
+	var deleted struct{ DeleteTodo todo }
+	c.MustPost(`mutation($id: ID!) { deleteTodo(id: $id) { id } }`, &deleted, client.Var("id", milk.ID))
+	var resp struct{ Todo *todo }
+	c.MustPost(`query($id: ID!) { todo(id: $id) { id } }`, &resp, client.Var("id", milk.ID))
+	if resp.Todo != nil {
+		t.Errorf("got %+v after delete", resp.Todo)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	c := newClient(t)
+	var resp struct{}
+	for name, query := range map[string]string{
+		"unknown user":   `mutation { createTodo(input: { text: "x", userId: "42" }) { id } }`,
+		"unknown todo":   `mutation { toggleDone(id: "42") { id } }`,
+		"invalid cursor": `query { todos(after: "nope") { pageInfo { hasNextPage } } }`,
+		"first too big":  `query { todos(first: 1000) { pageInfo { hasNextPage } } }`,
+	} {
+		if err := c.Post(query, &resp); err == nil {
+			t.Errorf("%s: got no error", name)
+		}
+	}
+}
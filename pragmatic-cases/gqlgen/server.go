@@ -5,12 +5,16 @@ import (
 	"net/http"
 	"os"
 	"tmp/pragmatic-cases/gqlgen/graph"
+	"tmp/pragmatic-cases/gqlgen/store"
+	"tmp/pragmatic-cases/health"
 
-	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/playground"
 )
 
-const defaultPort = "8080"
+const (
+	defaultPort = "8080"
+	defaultDSN  = "todos.db"
+)
 
 func main() {
 	port := os.Getenv("PORT")
@@ -18,10 +22,21 @@ func main() {
 		port = defaultPort
 	}
 
-	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}))
+	dsn := os.Getenv("DATABASE_DSN")
+	if dsn == "" {
+		dsn = defaultDSN
+	}
+	s, err := store.Open(dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer s.Close()
 
 	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	http.Handle("/query", srv)
+	http.Handle("/query", graph.NewHandler(s))
+	h := health.New()
+	h.Readiness.Register("store", health.CheckFunc(s.Ping))
+	h.Mount(http.DefaultServeMux)
 
 	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
 	log.Fatal(http.ListenAndServe(":"+port, nil))
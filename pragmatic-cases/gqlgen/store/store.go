@@ -0,0 +1,259 @@
+// Package store keeps the users and todos of the gqlgen example in SQLite.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"tmp/pragmatic-cases/gqlgen/graph/model"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+var (
+	ErrNotFound = errors.New("store: not found")
+	ErrInvalid  = errors.New("store: invalid input")
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS users (
+	id   INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS todos (
+	id      INTEGER PRIMARY KEY AUTOINCREMENT,
+	text    TEXT NOT NULL,
+	done    BOOLEAN NOT NULL DEFAULT FALSE,
+	user_id INTEGER NOT NULL REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS todos_user_id ON todos (user_id);
+`
+
+// Store is safe for concurrent use: SQLite serializes the writes, and every
+// change is one statement, so there is no read-modify-write to race.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the SQLite database at dsn, and creates the tables. A dsn of
+// ":memory:" is a database per Store.
+func Open(dsn string) (*Store, error) {
+	if dsn == ":memory:" {
+		// one connection, as each opens its own in-memory database
+		dsn = "file::memory:"
+	}
+	if !strings.Contains(dsn, "_fk=") {
+		dsn += sep(dsn) + "_fk=1"
+	}
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows one writer: more connections only fail with SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("store: creating tables: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+func sep(dsn string) string {
+	if strings.Contains(dsn, "?") {
+		return "&"
+	}
+	return "?"
+}
+
+func (s *Store) Close() error { return s.db.Close() }
+
+// Ping checks the database still answers, for the readiness of the server.
+func (s *Store) Ping(ctx context.Context) error { return s.db.PingContext(ctx) }
+
+func (s *Store) CreateUser(ctx context.Context, name string) (*model.User, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("%w: empty name", ErrInvalid)
+	}
+	u := &model.User{Name: name}
+	var id int64
+	if err := s.db.QueryRowContext(ctx, "INSERT INTO users (name) VALUES (?) RETURNING id", name).Scan(&id); err != nil {
+		return nil, err
+	}
+	u.ID = formatID(id)
+	return u, nil
+}
+
+// UsersByIDs returns the users of ids that exist, for the user dataloader.
+func (s *Store) UsersByIDs(ctx context.Context, ids []string) (map[string]*model.User, error) {
+	users := map[string]*model.User{}
+	var args []any
+	for _, id := range ids {
+		if n, ok := parseID(id); ok {
+			args = append(args, n)
+		}
+	}
+	if len(args) == 0 {
+		return users, nil
+	}
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, name FROM users WHERE id IN (?"+strings.Repeat(", ?", len(args)-1)+")", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		u := &model.User{}
+		if err := rows.Scan(&id, &u.Name); err != nil {
+			return nil, err
+		}
+		u.ID = formatID(id)
+		users[u.ID] = u
+	}
+	return users, rows.Err()
+}
+
+// CreateTodo adds a todo to an existing user.
+func (s *Store) CreateTodo(ctx context.Context, input model.NewTodo) (*model.Todo, error) {
+	if strings.TrimSpace(input.Text) == "" {
+		return nil, fmt.Errorf("%w: empty text", ErrInvalid)
+	}
+	userID, ok := parseID(input.UserID)
+	if !ok {
+		return nil, fmt.Errorf("%w: user %s", ErrNotFound, input.UserID)
+	}
+	t, err := scanTodo(s.db.QueryRowContext(ctx,
+		"INSERT INTO todos (text, user_id) VALUES (?, ?) RETURNING "+todoColumns, input.Text, userID))
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+		return nil, fmt.Errorf("%w: user %s", ErrNotFound, input.UserID)
+	}
+	return t, err
+}
+
+func (s *Store) Todo(ctx context.Context, id string) (*model.Todo, error) {
+	n, ok := parseID(id)
+	if !ok {
+		return nil, notFound(id)
+	}
+	return s.todo(ctx, id, "SELECT "+todoColumns+" FROM todos WHERE id = ?", n)
+}
+
+// UpdateTodo sets the fields of input that are not nil.
+func (s *Store) UpdateTodo(ctx context.Context, id string, input model.UpdateTodo) (*model.Todo, error) {
+	n, ok := parseID(id)
+	if !ok {
+		return nil, notFound(id)
+	}
+	if input.Text != nil && strings.TrimSpace(*input.Text) == "" {
+		return nil, fmt.Errorf("%w: empty text", ErrInvalid)
+	}
+	return s.todo(ctx, id,
+		"UPDATE todos SET text = coalesce(?, text), done = coalesce(?, done) WHERE id = ? RETURNING "+todoColumns,
+		input.Text, input.Done, n)
+}
+
+// ToggleDone flips done in one statement, so concurrent toggles all count.
+func (s *Store) ToggleDone(ctx context.Context, id string) (*model.Todo, error) {
+	n, ok := parseID(id)
+	if !ok {
+		return nil, notFound(id)
+	}
+	return s.todo(ctx, id, "UPDATE todos SET done = NOT done WHERE id = ? RETURNING "+todoColumns, n)
+}
+
+// DeleteTodo returns the todo it deleted.
+func (s *Store) DeleteTodo(ctx context.Context, id string) (*model.Todo, error) {
+	n, ok := parseID(id)
+	if !ok {
+		return nil, notFound(id)
+	}
+	return s.todo(ctx, id, "DELETE FROM todos WHERE id = ? RETURNING "+todoColumns, n)
+}
+
+// Page is a page of todos, in the order they were created.
+type Page struct {
+	Todos   []*model.Todo
+	HasNext bool
+}
+
+// Todos returns the first todos after the todo with the ID after, or from
+// the start when after is empty, that match the filter.
+func (s *Store) Todos(ctx context.Context, filter *model.TodoFilter, first int, after string) (*Page, error) {
+	if first < 0 {
+		return nil, fmt.Errorf("%w: first must not be negative", ErrInvalid)
+	}
+	where, args := []string{"true"}, []any{}
+	if after != "" {
+		n, ok := parseID(after)
+		if !ok {
+			return nil, fmt.Errorf("%w: cursor", ErrInvalid)
+		}
+		where, args = append(where, "id > ?"), append(args, n)
+	}
+	if filter != nil {
+		if filter.Done != nil {
+			where, args = append(where, "done = ?"), append(args, *filter.Done)
+		}
+		if filter.UserID != nil {
+			n, _ := parseID(*filter.UserID)
+			where, args = append(where, "user_id = ?"), append(args, n)
+		}
+		if filter.TextContains != nil {
+			where, args = append(where, "instr(lower(text), lower(?)) > 0"), append(args, *filter.TextContains)
+		}
+	}
+	// one more than asked tells whether there is a next page
+	args = append(args, first+1)
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+todoColumns+" FROM todos WHERE "+strings.Join(where, " AND ")+" ORDER BY id LIMIT ?", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	page := &Page{Todos: []*model.Todo{}}
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		page.Todos = append(page.Todos, t)
+	}
+	if len(page.Todos) > first {
+		page.Todos, page.HasNext = page.Todos[:first], true
+	}
+	return page, rows.Err()
+}
+
+const todoColumns = "id, text, done, user_id"
+
+func (s *Store) todo(ctx context.Context, id, query string, args ...any) (*model.Todo, error) {
+	t, err := scanTodo(s.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound(id)
+	}
+	return t, err
+}
+
+func scanTodo(row interface{ Scan(...any) error }) (*model.Todo, error) {
+	var id, userID int64
+	t := &model.Todo{}
+	if err := row.Scan(&id, &t.Text, &t.Done, &userID); err != nil {
+		return nil, err
+	}
+	t.ID, t.UserID = formatID(id), formatID(userID)
+	return t, nil
+}
+
+func notFound(id string) error { return fmt.Errorf("%w: todo %s", ErrNotFound, id) }
+
+// IDs are the integers of SQLite as strings, the ID scalar of GraphQL.
+func formatID(id int64) string { return strconv.FormatInt(id, 10) }
+
+func parseID(id string) (int64, bool) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	return n, err == nil && n > 0
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"tmp/pragmatic-cases/gqlgen/graph/model"
+)
+
+func open(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestTodos(t *testing.T) {
+	ctx := context.Background()
+	s := open(t)
+	alice, err := s.CreateUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := s.CreateUser(ctx, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.CreateTodo(ctx, model.NewTodo{Text: "nobody's", UserID: "42"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v for an unknown user, want %v", err, ErrNotFound)
+	}
+	if _, err := s.CreateTodo(ctx, model.NewTodo{Text: " ", UserID: alice.ID}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("got %v for an empty text, want %v", err, ErrInvalid)
+	}
+
+	var todos []*model.Todo
+	for _, in := range []model.NewTodo{
+		{Text: "Buy milk", UserID: alice.ID},
+		{Text: "Write tests", UserID: bob.ID},
+		{Text: "Buy bread", UserID: alice.ID},
+	} {
+		todo, err := s.CreateTodo(ctx, in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		todos = append(todos, todo)
+	}
+
+	done := true
+	got, err := s.UpdateTodo(ctx, todos[0].ID, model.UpdateTodo{Done: &done})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Done || got.Text != "Buy milk" {
+		t.Errorf("got %+v after setting done only", got)
+	}
+
+	ids := func(p *Page) (ids []string) {
+		for _, todo := range p.Todos {
+			ids = append(ids, todo.ID)
+		}
+		return ids
+	}
+	buy, notDone := "BUY", false
+	for _, tt := range []struct {
+		name    string
+		filter  *model.TodoFilter
+		first   int
+		after   string
+		want    []string
+		hasNext bool
+	}{
+		{name: "all", first: 10, want: []string{todos[0].ID, todos[1].ID, todos[2].ID}},
+		{name: "first page", first: 2, want: []string{todos[0].ID, todos[1].ID}, hasNext: true},
+		{name: "last page", first: 2, after: todos[1].ID, want: []string{todos[2].ID}},
+		{name: "user", filter: &model.TodoFilter{UserID: &alice.ID}, first: 10, want: []string{todos[0].ID, todos[2].ID}},
+		{name: "not done", filter: &model.TodoFilter{Done: &notDone}, first: 10, want: []string{todos[1].ID, todos[2].ID}},
+		{name: "text", filter: &model.TodoFilter{TextContains: &buy, Done: &notDone}, first: 10, want: []string{todos[2].ID}},
+		{name: "none", first: 0, hasNext: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := s.Todos(ctx, tt.filter, tt.first, tt.after)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(p); !slices.Equal(got, tt.want) || p.HasNext != tt.hasNext {
+				t.Errorf("got %v, next %t, want %v, next %t", got, p.HasNext, tt.want, tt.hasNext)
+			}
+		})
+	}
+
+	deleted, err := s.DeleteTodo(ctx, todos[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted.Text != "Write tests" {
+		t.Errorf("deleted %+v", deleted)
+	}
+	if _, err := s.Todo(ctx, todos[1].ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v after delete, want %v", err, ErrNotFound)
+	}
+	if _, err := s.ToggleDone(ctx, todos[1].ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v toggling a deleted todo, want %v", err, ErrNotFound)
+	}
+
+	users, err := s.UsersByIDs(ctx, []string{alice.ID, "42", "x", bob.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[alice.ID].Name != "alice" || users[bob.ID].Name != "bob" {
+		t.Errorf("got users %v", users)
+	}
+}
+
+func TestPing(t *testing.T) {
+	s, err := Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := s.Ping(ctx); err != nil {
+		t.Errorf("got %v, want a ping", err)
+	}
+	s.Close()
+	if err := s.Ping(ctx); err == nil {
+		t.Error("got a ping from a closed store")
+	}
+}
+
+func TestConcurrentToggle(t *testing.T) {
+	ctx := context.Background()
+	s, err := Open(filepath.Join(t.TempDir(), "todos.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	u, err := s.CreateUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	todo, err := s.CreateTodo(ctx, model.NewTodo{Text: "flip", UserID: u.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an even number of toggles leaves done as it was, unless one is lost
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.ToggleDone(ctx, todo.ID); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	got, err := s.Todo(ctx, todo.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Done {
+		t.Error("got done after 50 toggles")
+	}
+}
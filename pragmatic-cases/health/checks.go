@@ -0,0 +1,63 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Pinger is satisfied by *sql.DB.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// DB checks a database answers a ping.
+func DB(db Pinger) Checker {
+	return CheckFunc(func(ctx context.Context) error {
+		if err := db.PingContext(ctx); err != nil {
+			return fmt.Errorf("ping: %w", err)
+		}
+		return nil
+	})
+}
+
+// GRPCConn checks a client connection is, or can get, ready. An idle
+// connection is asked to connect and given until the check times out.
+func GRPCConn(conn *grpc.ClientConn) Checker {
+	return CheckFunc(func(ctx context.Context) error {
+		for {
+			state := conn.GetState()
+			switch state {
+			case connectivity.Ready:
+				return nil
+			case connectivity.Shutdown:
+				return errors.New("grpc connection is shut down")
+			case connectivity.Idle:
+				conn.Connect()
+			}
+			if !conn.WaitForStateChange(ctx, state) {
+				return fmt.Errorf("grpc connection is %s: %w", state, ctx.Err())
+			}
+		}
+	})
+}
+
+// GRPCHealth asks the server on conn for the status of service with the
+// standard grpc.health.v1 protocol; "" is the server as a whole.
+func GRPCHealth(conn grpc.ClientConnInterface, service string) Checker {
+	client := healthpb.NewHealthClient(conn)
+	return CheckFunc(func(ctx context.Context) error {
+		res, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return err
+		}
+		if status := res.GetStatus(); status != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("grpc service %q is %s", service, status)
+		}
+		return nil
+	})
+}
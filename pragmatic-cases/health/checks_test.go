@@ -0,0 +1,83 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type fakePinger struct{ err error }
+
+func (f fakePinger) PingContext(ctx context.Context) error { return f.err }
+
+func TestDB(t *testing.T) {
+	if err := DB(fakePinger{}).Check(context.Background()); err != nil {
+		t.Errorf("got %v, want no error", err)
+	}
+
+	refused := errors.New("connection refused")
+	err := DB(fakePinger{err: refused}).Check(context.Background())
+	if !errors.Is(err, refused) {
+		t.Errorf("got %v, want %v", err, refused)
+	}
+}
+
+func TestGRPC(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	status := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(server, status)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	check := func(c Checker) error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		return c.Check(ctx)
+	}
+
+	t.Run("idle connection gets ready", func(t *testing.T) {
+		if err := check(GRPCConn(conn)); err != nil {
+			t.Errorf("got %v, want no error", err)
+		}
+	})
+
+	t.Run("serving service", func(t *testing.T) {
+		if err := check(GRPCHealth(conn, "")); err != nil {
+			t.Errorf("got %v, want no error", err)
+		}
+	})
+
+	t.Run("not serving service", func(t *testing.T) {
+		status.SetServingStatus("greeter", healthpb.HealthCheckResponse_NOT_SERVING)
+		err := check(GRPCHealth(conn, "greeter"))
+		if err == nil || !strings.Contains(err.Error(), "NOT_SERVING") {
+			t.Errorf("got %v, want a NOT_SERVING error", err)
+		}
+	})
+
+	t.Run("closed connection", func(t *testing.T) {
+		conn.Close()
+		if err := check(GRPCConn(conn)); err == nil {
+			t.Error("got no error, want one")
+		}
+	})
+}
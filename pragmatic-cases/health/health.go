@@ -0,0 +1,219 @@
+// Package health serves liveness and readiness reports built from pluggable
+// checks, each with its own timeout and cached result.
+//
+//	h := health.New()
+//	h.Readiness.Register("db", health.DB(db), health.WithTimeout(time.Second))
+//	h.Mount(mux) // /livez, /readyz and /health
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTimeout = 2 * time.Second
+	defaultTTL     = 5 * time.Second
+)
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckFunc is a Checker from a func, for custom checks.
+type CheckFunc func(ctx context.Context) error
+
+func (f CheckFunc) Check(ctx context.Context) error { return f(ctx) }
+
+// Result is the outcome of one check.
+type Result struct {
+	Status    Status    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Latency   string    `json:"latency"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report is up when all its checks are.
+type Report struct {
+	Status Status            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+type checkOptions struct {
+	timeout time.Duration
+	ttl     time.Duration
+}
+
+type CheckOption func(*checkOptions)
+
+// WithTimeout fails the check when it takes longer than d. Defaults to 2s.
+func WithTimeout(d time.Duration) CheckOption {
+	return func(o *checkOptions) { o.timeout = d }
+}
+
+// WithCacheTTL reuses a result for d, so probes hitting the endpoint every
+// second do not hit the database every second. 0 runs the check every time.
+// Defaults to 5s.
+func WithCacheTTL(d time.Duration) CheckOption {
+	return func(o *checkOptions) { o.ttl = d }
+}
+
+type check struct {
+	checker Checker
+	checkOptions
+
+	mu     sync.Mutex // held while running, so concurrent probes share a run
+	last   Result
+	expiry time.Time
+}
+
+// Registry is a set of named checks, run together.
+type Registry struct {
+	mu     sync.RWMutex
+	checks map[string]*check
+	now    func() time.Time
+}
+
+func NewRegistry() *Registry {
+	return &Registry{checks: map[string]*check{}, now: time.Now}
+}
+
+// Register adds c under name, replacing any check of that name.
+func (r *Registry) Register(name string, c Checker, opts ...CheckOption) {
+	o := checkOptions{timeout: defaultTimeout, ttl: defaultTTL}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = &check{checker: c, checkOptions: o}
+}
+
+// Names returns the names of the checks, sorted.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.checks))
+	for name := range r.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run runs every check concurrently, or reuses its cached result.
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := make(map[string]*check, len(r.checks))
+	for name, c := range r.checks {
+		checks[name] = c
+	}
+	r.mu.RUnlock()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := r.run(ctx, c)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, c *check) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	start := r.now()
+	if start.Before(c.expiry) {
+		return c.last
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- c.checker.Check(checkCtx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-checkCtx.Done():
+		// a check ignoring ctx must not hold the probe
+		err = checkCtx.Err()
+	}
+
+	result := Result{Status: StatusUp, Latency: r.now().Sub(start).String(), CheckedAt: start}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	// a caller giving up says nothing about the check, only its own timeout
+	// does
+	if ctx.Err() == nil {
+		c.last, c.expiry = result, start.Add(c.ttl)
+	}
+	return result
+}
+
+// ServeHTTP answers the JSON report of the registry, with 503 when it is
+// down. ?verbose=false leaves the checks out.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	report := r.Run(req.Context())
+	if req.URL.Query().Get("verbose") == "false" {
+		report.Checks = nil
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.Header().Set("cache-control", "no-store")
+	if report.Status != StatusUp {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// Health is the liveness and readiness of a server. Liveness failing means
+// the process should be restarted, readiness failing that it should get no
+// traffic for now, e.g. while its database is unreachable.
+type Health struct {
+	Liveness  *Registry
+	Readiness *Registry
+}
+
+func New() *Health {
+	return &Health{Liveness: NewRegistry(), Readiness: NewRegistry()}
+}
+
+// Mux is what Mount needs: *http.ServeMux, or a wrapper such as the traced
+// mux of dd-trace-go.
+type Mux interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// Mount serves liveness on /livez and readiness on /readyz, and also on
+// /health for platforms probing a single path like Cloud Run.
+func (h *Health) Mount(mux Mux) {
+	mux.Handle("/livez", h.Liveness)
+	mux.Handle("/readyz", h.Readiness)
+	mux.Handle("/health", h.Readiness)
+}
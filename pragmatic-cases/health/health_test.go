@@ -0,0 +1,214 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeCheck fails with err and counts its calls.
+type fakeCheck struct {
+	err   error
+	calls atomic.Int32
+}
+
+func (f *fakeCheck) Check(ctx context.Context) error {
+	f.calls.Add(1)
+	return f.err
+}
+
+type fakeClock struct{ t time.Time }
+
+func (f *fakeClock) now() time.Time          { return f.t }
+func (f *fakeClock) advance(d time.Duration) { f.t = f.t.Add(d) }
+
+func newTestRegistry() (*Registry, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	r := NewRegistry()
+	r.now = clock.now
+	return r, clock
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("empty registry is up", func(t *testing.T) {
+		r, _ := newTestRegistry()
+		assertStatus(t, r.Run(context.Background()).Status, StatusUp)
+	})
+
+	t.Run("one failing check takes it down", func(t *testing.T) {
+		r, _ := newTestRegistry()
+		r.Register("db", &fakeCheck{})
+		r.Register("cache", &fakeCheck{err: errors.New("connection refused")})
+
+		report := r.Run(context.Background())
+
+		assertStatus(t, report.Status, StatusDown)
+		assertStatus(t, report.Checks["db"].Status, StatusUp)
+		assertStatus(t, report.Checks["cache"].Status, StatusDown)
+		if got := report.Checks["cache"].Error; got != "connection refused" {
+			t.Errorf("got error %q, want %q", got, "connection refused")
+		}
+	})
+
+	t.Run("results are cached for their TTL", func(t *testing.T) {
+		r, clock := newTestRegistry()
+		check := &fakeCheck{}
+		r.Register("db", check, WithCacheTTL(10*time.Second))
+
+		r.Run(context.Background())
+		clock.advance(9 * time.Second)
+		r.Run(context.Background())
+		assertCalls(t, check, 1)
+
+		clock.advance(time.Second)
+		r.Run(context.Background())
+		assertCalls(t, check, 2)
+	})
+
+	t.Run("no TTL runs the check every time", func(t *testing.T) {
+		r, _ := newTestRegistry()
+		check := &fakeCheck{}
+		r.Register("db", check, WithCacheTTL(0))
+
+		r.Run(context.Background())
+		r.Run(context.Background())
+		assertCalls(t, check, 2)
+	})
+
+	t.Run("a check ignoring its context times out", func(t *testing.T) {
+		r := NewRegistry()
+		block := make(chan struct{})
+		defer close(block)
+		r.Register("slow", CheckFunc(func(ctx context.Context) error {
+			<-block
+			return nil
+		}), WithTimeout(10*time.Millisecond))
+
+		report := r.Run(context.Background())
+
+		assertStatus(t, report.Status, StatusDown)
+		if got := report.Checks["slow"].Error; got != context.DeadlineExceeded.Error() {
+			t.Errorf("got error %q, want %q", got, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("a cancelled request is not cached", func(t *testing.T) {
+		r, _ := newTestRegistry()
+		r.Register("db", CheckFunc(func(ctx context.Context) error {
+			return ctx.Err()
+		}), WithCacheTTL(10*time.Second))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assertStatus(t, r.Run(ctx).Status, StatusDown)
+		assertStatus(t, r.Run(context.Background()).Status, StatusUp)
+	})
+
+	t.Run("checks run concurrently", func(t *testing.T) {
+		r := NewRegistry()
+		started := make(chan struct{})
+		for _, name := range []string{"a", "b"} {
+			r.Register(name, CheckFunc(func(ctx context.Context) error {
+				// each check waits for the other to start
+				select {
+				case started <- struct{}{}:
+				case <-started:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				return nil
+			}), WithTimeout(time.Second))
+		}
+
+		assertStatus(t, r.Run(context.Background()).Status, StatusUp)
+	})
+
+	t.Run("names are sorted", func(t *testing.T) {
+		r := NewRegistry()
+		r.Register("b", &fakeCheck{})
+		r.Register("a", &fakeCheck{})
+		if got := r.Names(); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+			t.Errorf("got %v, want [a b]", got)
+		}
+	})
+}
+
+func TestHandler(t *testing.T) {
+	h := New()
+	db := &fakeCheck{}
+	h.Readiness.Register("db", db, WithCacheTTL(0))
+	mux := http.NewServeMux()
+	h.Mount(mux)
+
+	t.Run("liveness with no checks is up", func(t *testing.T) {
+		res, report := get(t, mux, "/livez")
+		assertCode(t, res.Code, http.StatusOK)
+		assertStatus(t, report.Status, StatusUp)
+	})
+
+	t.Run("readiness reports its checks", func(t *testing.T) {
+		res, report := get(t, mux, "/readyz")
+		assertCode(t, res.Code, http.StatusOK)
+		if got := res.Header().Get("content-type"); got != "application/json" {
+			t.Errorf("got content-type %q, want application/json", got)
+		}
+		assertStatus(t, report.Checks["db"].Status, StatusUp)
+	})
+
+	t.Run("failing readiness is a 503 on /readyz and /health", func(t *testing.T) {
+		db.err = errors.New("connection refused")
+		defer func() { db.err = nil }()
+
+		for _, path := range []string{"/readyz", "/health"} {
+			res, report := get(t, mux, path)
+			assertCode(t, res.Code, http.StatusServiceUnavailable)
+			assertStatus(t, report.Status, StatusDown)
+		}
+		res, _ := get(t, mux, "/livez")
+		assertCode(t, res.Code, http.StatusOK)
+	})
+
+	t.Run("verbose=false leaves out the checks", func(t *testing.T) {
+		_, report := get(t, mux, "/readyz?verbose=false")
+		if report.Checks != nil {
+			t.Errorf("got checks %v, want none", report.Checks)
+		}
+	})
+}
+
+func get(t testing.TB, h http.Handler, target string) (*httptest.ResponseRecorder, Report) {
+	t.Helper()
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, httptest.NewRequest(http.MethodGet, target, nil))
+	var report Report
+	if err := json.NewDecoder(res.Body).Decode(&report); err != nil {
+		t.Fatalf("cannot decode report %q: %v", res.Body, err)
+	}
+	return res, report
+}
+
+func assertStatus(t testing.TB, got, want Status) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got status %q, want %q", got, want)
+	}
+}
+
+func assertCode(t testing.TB, got, want int) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got code %d, want %d", got, want)
+	}
+}
+
+func assertCalls(t testing.TB, f *fakeCheck, want int32) {
+	t.Helper()
+	if got := f.calls.Load(); got != want {
+		t.Errorf("got %d calls, want %d", got, want)
+	}
+}
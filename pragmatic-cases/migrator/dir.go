@@ -0,0 +1,248 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is the layout of a migration directory.
+type Format string
+
+const (
+	// FormatMigrate is golang-migrate: 1_name.up.sql and 1_name.down.sql.
+	FormatMigrate Format = "migrate"
+	// FormatAtlas is Atlas: 20240101000000_name.sql, up only, and atlas.sum.
+	FormatAtlas Format = "atlas"
+)
+
+var (
+	migrateFile = regexp.MustCompile(`^(\d+)_(.*)\.(up|down)\.sql$`)
+	atlasFile   = regexp.MustCompile(`^(\d+)(?:_(.*))?\.sql$`)
+)
+
+var (
+	ErrUnknownFormat  = errors.New("migrator: unknown directory format")
+	ErrUnknownVersion = errors.New("migrator: no migration with this version")
+	ErrIrreversible   = errors.New("migrator: migration has no down file")
+)
+
+// Migration is one version of a directory.
+type Migration struct {
+	Version  uint64
+	ID       string // the file name without extension: "01_create_table"
+	Name     string // "create_table"
+	UpFile   string
+	DownFile string // empty when the migration cannot be reverted
+}
+
+func (m Migration) String() string { return m.ID }
+
+// Dir is a migration directory, its migrations sorted by version.
+type Dir struct {
+	Path       string
+	Format     Format
+	Migrations []Migration
+
+	fsys     fs.FS
+	problems []error // kept for Validate, so Open fails only when unusable
+}
+
+// OpenDir reads the migrations at path. An empty format is detected:
+// *.up.sql or *.down.sql files are golang-migrate, other *.sql files Atlas.
+func OpenDir(path string, format Format) (*Dir, error) {
+	fsys := os.DirFS(path)
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	if format == "" {
+		format = detect(fsys, names)
+	}
+
+	d := &Dir{Path: path, Format: format, fsys: fsys}
+	switch format {
+	case FormatMigrate:
+		d.readMigrate(names)
+	case FormatAtlas:
+		d.readAtlas(names)
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+	return d, nil
+}
+
+func detect(fsys fs.FS, names []string) Format {
+	for _, name := range names {
+		if migrateFile.MatchString(name) {
+			return FormatMigrate
+		}
+	}
+	if _, err := fs.Stat(fsys, SumFile); err == nil || len(names) > 0 {
+		return FormatAtlas
+	}
+	return FormatMigrate
+}
+
+func (d *Dir) readMigrate(names []string) {
+	byVersion := map[uint64]*Migration{}
+	for _, name := range names {
+		match := migrateFile.FindStringSubmatch(name)
+		if match == nil {
+			d.problems = append(d.problems, fmt.Errorf("%s: not a golang-migrate file name", name))
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			d.problems = append(d.problems, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		id := match[1] + "_" + match[2]
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, ID: id, Name: match[2]}
+			byVersion[version] = m
+		} else if m.ID != id {
+			d.problems = append(d.problems, fmt.Errorf("%s: version %d is also %s", name, version, m.ID))
+			continue
+		}
+		if match[3] == "up" {
+			m.UpFile = name
+		} else {
+			m.DownFile = name
+		}
+	}
+	for _, m := range byVersion {
+		d.Migrations = append(d.Migrations, *m)
+	}
+	d.sort()
+}
+
+func (d *Dir) readAtlas(names []string) {
+	seen := map[uint64]string{}
+	for _, name := range names {
+		match := atlasFile.FindStringSubmatch(name)
+		if match == nil {
+			d.problems = append(d.problems, fmt.Errorf("%s: not an atlas file name", name))
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			d.problems = append(d.problems, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		if other, ok := seen[version]; ok {
+			d.problems = append(d.problems, fmt.Errorf("%s: version %d is also %s", name, version, other))
+			continue
+		}
+		seen[version] = name
+		d.Migrations = append(d.Migrations, Migration{Version: version, ID: strings.TrimSuffix(name, ".sql"), Name: match[2], UpFile: name})
+	}
+	d.sort()
+}
+
+func (d *Dir) sort() {
+	sort.Slice(d.Migrations, func(i, j int) bool { return d.Migrations[i].Version < d.Migrations[j].Version })
+}
+
+// Read returns the content of a file of the directory.
+func (d *Dir) Read(name string) (string, error) {
+	b, err := fs.ReadFile(d.fsys, name)
+	return string(b), err
+}
+
+// Index returns the position of version in Migrations.
+func (d *Dir) Index(version uint64) (int, bool) {
+	i := sort.Search(len(d.Migrations), func(i int) bool { return d.Migrations[i].Version >= version })
+	return i, i < len(d.Migrations) && d.Migrations[i].Version == version
+}
+
+// Validate reports every problem of the directory: file names that do not
+// parse, duplicate versions, golang-migrate up files without a down file or
+// the other way around, and an atlas.sum that does not match.
+func (d *Dir) Validate() error {
+	problems := append([]error(nil), d.problems...)
+	for _, m := range d.Migrations {
+		if d.Format != FormatMigrate {
+			break
+		}
+		if m.UpFile == "" {
+			problems = append(problems, fmt.Errorf("%s: down file without an up file", m.ID))
+		}
+		if m.DownFile == "" {
+			problems = append(problems, fmt.Errorf("%s: up file without a down file", m.ID))
+		}
+	}
+	if d.Format == FormatAtlas {
+		if err := d.VerifySum(); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// Create adds an empty migration named name: the next sequence number for a
+// golang-migrate directory numbered 1, 2, 3..., or the time for timestamped
+// ones and Atlas. It returns the paths of the new files, and rehashes
+// atlas.sum.
+func (d *Dir) Create(name string, now time.Time) ([]string, error) {
+	name = strings.Join(strings.Fields(name), "_")
+	if name == "" || strings.ContainsAny(name, `/\.`) {
+		return nil, fmt.Errorf("migrator: invalid migration name %q", name)
+	}
+
+	version := now.UTC().Format("20060102150405")
+	if d.Format == FormatMigrate && d.sequential() {
+		width := 6
+		if n := len(d.Migrations); n > 0 {
+			last := d.Migrations[n-1]
+			width = len(strings.SplitN(last.ID, "_", 2)[0])
+			version = fmt.Sprintf("%0*d", width, last.Version+1)
+		} else {
+			version = fmt.Sprintf("%0*d", width, 1)
+		}
+	}
+
+	var files []string
+	switch d.Format {
+	case FormatMigrate:
+		files = []string{version + "_" + name + ".up.sql", version + "_" + name + ".down.sql"}
+	case FormatAtlas:
+		files = []string{version + "_" + name + ".sql"}
+	}
+	var paths []string
+	for _, f := range files {
+		path := filepath.Join(d.Path, f)
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	reread, err := OpenDir(d.Path, d.Format)
+	if err != nil {
+		return nil, err
+	}
+	*d = *reread
+	if d.Format == FormatAtlas {
+		if err := d.WriteSum(); err != nil {
+			return nil, err
+		}
+		paths = append(paths, filepath.Join(d.Path, SumFile))
+	}
+	return paths, nil
+}
+
+// sequential tells a directory numbered 1, 2, 3... from a timestamped one.
+func (d *Dir) sequential() bool {
+	n := len(d.Migrations)
+	return n == 0 || d.Migrations[n-1].Version < 1e9
+}
@@ -0,0 +1,173 @@
+package migrator
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpenDir(t *testing.T) {
+	t.Run("golang-migrate", func(t *testing.T) {
+		d, err := OpenDir("testdata/migrate", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Format != FormatMigrate {
+			t.Errorf("got format %s, want %s", d.Format, FormatMigrate)
+		}
+		want := []Migration{
+			{Version: 1, ID: "01_create_users", Name: "create_users", UpFile: "01_create_users.up.sql", DownFile: "01_create_users.down.sql"},
+			{Version: 2, ID: "02_add_email", Name: "add_email", UpFile: "02_add_email.up.sql", DownFile: "02_add_email.down.sql"},
+			{Version: 3, ID: "03_seed", Name: "seed", UpFile: "03_seed.up.sql", DownFile: "03_seed.down.sql"},
+		}
+		if !reflect.DeepEqual(d.Migrations, want) {
+			t.Errorf("got %+v, want %+v", d.Migrations, want)
+		}
+		if err := d.Validate(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("atlas", func(t *testing.T) {
+		d, err := OpenDir(entMigrations, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Format != FormatAtlas {
+			t.Errorf("got format %s, want %s", d.Format, FormatAtlas)
+		}
+		if got := d.Migrations[2]; got.Version != 20240410023634 || got.Name != "add_animal_again" || got.DownFile != "" {
+			t.Errorf("got %+v", got)
+		}
+		if err := d.Validate(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("the repo's golang-migrate directories are valid", func(t *testing.T) {
+		for _, path := range []string{"../migrate/mysql/migrations", "../migrate/postgres/migrations"} {
+			d, err := OpenDir(path, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := d.Validate(); err != nil || d.Format != FormatMigrate {
+				t.Errorf("%s: got %s, %v", path, d.Format, err)
+			}
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		if _, err := OpenDir("testdata/migrate", "flyway"); !errors.Is(err, ErrUnknownFormat) {
+			t.Errorf("got %v, want %v", err, ErrUnknownFormat)
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	d := openCopy(t, "testdata/migrate")
+	os.Remove(filepath.Join(d.Path, "02_add_email.down.sql"))
+	writeFile(t, d.Path, "03_other.up.sql", "")
+	writeFile(t, d.Path, "notes.sql", "")
+	d, err := OpenDir(d.Path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.Validate()
+	for _, want := range []string{"02_add_email: up file without a down file", "03_seed.up.sql: version 3 is also 03_other", "notes.sql: not a golang-migrate file name"} {
+		if err == nil || !contains(err.Error(), want) {
+			t.Errorf("got %v, want %q", err, want)
+		}
+	}
+}
+
+func TestCreate(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	t.Run("next sequence number", func(t *testing.T) {
+		d := openCopy(t, "testdata/migrate")
+		files, err := d.Create("add phone", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertFiles(t, files, "04_add_phone.up.sql", "04_add_phone.down.sql")
+		if n := len(d.Migrations); n != 4 {
+			t.Errorf("got %d migrations, want 4", n)
+		}
+	})
+
+	t.Run("first migration", func(t *testing.T) {
+		d, err := OpenDir(t.TempDir(), FormatMigrate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files, err := d.Create("init", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertFiles(t, files, "000001_init.up.sql", "000001_init.down.sql")
+	})
+
+	t.Run("atlas rehashes the sum", func(t *testing.T) {
+		d := openCopy(t, "testdata/atlas")
+		files, err := d.Create("add_groups", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertFiles(t, files, "20240506070809_add_groups.sql", SumFile)
+		if err := d.VerifySum(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		d := openCopy(t, "testdata/migrate")
+		if _, err := d.Create("../escape", now); err == nil {
+			t.Error("got no error, want one")
+		}
+	})
+}
+
+// openCopy copies the directory at src to a temporary one and opens it.
+func openCopy(t *testing.T, src string) *Dir {
+	t.Helper()
+	dst := t.TempDir()
+	if err := os.CopyFS(dst, os.DirFS(src)); err != nil {
+		t.Fatal(err)
+	}
+	// CopyFS keeps the read-only modes of the module cache, if any
+	filepath.WalkDir(dst, func(path string, _ fs.DirEntry, _ error) error { return os.Chmod(path, 0o755) })
+	d, err := OpenDir(dst, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func writeFile(t testing.TB, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertFiles(t testing.TB, paths []string, want ...string) {
+	t.Helper()
+	var got []string
+	for _, p := range paths {
+		if _, err := os.Stat(p); err != nil {
+			t.Error(err)
+		}
+		got = append(got, filepath.Base(p))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func contains(s, substr string) bool { return strings.Contains(s, substr) }
@@ -0,0 +1,128 @@
+package lint
+
+import (
+	"strings"
+	"unicode"
+
+	"tmp/pragmatic-cases/migrator"
+)
+
+type tokenKind int
+
+const (
+	word   tokenKind = iota // keyword or unquoted identifier
+	quoted                  // "identifier" or `identifier`
+	str                     // 'string'
+	number
+	punct
+)
+
+type token struct {
+	kind tokenKind
+	text string // unquoted for quoted and str
+	line int    // from the start of the statement, counting from 0
+}
+
+// is reports whether t is one of the keywords, case-insensitively.
+func (t token) is(keywords ...string) bool {
+	if t.kind != word {
+		return false
+	}
+	for _, k := range keywords {
+		if strings.EqualFold(t.text, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// name is the identifier of t: quoted identifiers keep their case, others
+// are lower-cased as databases fold them.
+func (t token) name() string {
+	if t.kind == quoted {
+		return t.text
+	}
+	return strings.ToLower(t.text)
+}
+
+// lex tokenizes one statement of dialect d. The comments are returned apart,
+// for their lint:ignore directives.
+func lex(d migrator.Dialect, stmt string) (tokens []token, comments []string) {
+	line := 0
+	for i := 0; i < len(stmt); {
+		c := stmt[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(stmt[i:], "--") || c == '#' && d.HashComments():
+			end := strings.IndexByte(stmt[i:], '\n')
+			if end < 0 {
+				end = len(stmt) - i
+			}
+			comments = append(comments, stmt[i:i+end])
+			i += end
+		case strings.HasPrefix(stmt[i:], "/*"):
+			end := strings.Index(stmt[i+2:], "*/")
+			if end < 0 {
+				end = len(stmt) - i - 2
+			}
+			comment := stmt[i : i+end+2]
+			comments = append(comments, comment)
+			line += strings.Count(comment, "\n")
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`':
+			text, n := unquote(stmt[i:], d.BackslashEscapes(stmt, i))
+			kind := quoted
+			if c == '\'' {
+				kind = str
+			}
+			tokens = append(tokens, token{kind: kind, text: text, line: line})
+			line += strings.Count(stmt[i:i+n], "\n")
+			i += n
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(stmt) && (stmt[j] >= '0' && stmt[j] <= '9' || stmt[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: number, text: stmt[i:j], line: line})
+			i = j
+		case c == '_' || c == '$' || unicode.IsLetter(rune(c)) || c >= 0x80:
+			j := i
+			for j < len(stmt) && (stmt[j] == '_' || stmt[j] == '$' || stmt[j] >= 0x80 ||
+				unicode.IsLetter(rune(stmt[j])) || unicode.IsDigit(rune(stmt[j]))) {
+				j++
+			}
+			tokens = append(tokens, token{kind: word, text: stmt[i:j], line: line})
+			i = j
+		default:
+			tokens = append(tokens, token{kind: punct, text: string(c), line: line})
+			i++
+		}
+	}
+	return tokens, comments
+}
+
+// unquote returns the content of the quoted text at the start of s, and its
+// length with the quotes. Backslashes escape when backslash is set.
+func unquote(s string, backslash bool) (string, int) {
+	q := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && backslash && i+1 < len(s):
+			i++
+			b.WriteByte(s[i])
+		case s[i] == q && i+1 < len(s) && s[i+1] == q:
+			i++
+			b.WriteByte(q)
+		case s[i] == q:
+			return b.String(), i + 1
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), len(s)
+}
@@ -0,0 +1,390 @@
+// Package lint checks migration directories for changes that lose data or
+// fail on a live database, down files that do not revert their up file, and
+// edited Atlas migrations, so CI can stop them before they are merged.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"tmp/pragmatic-cases/migrator"
+)
+
+// Severity is how bad a finding is: errors should fail the build.
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+)
+
+// Rule is a check of the linter.
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Description string
+}
+
+// Rules are the checks of Lint. A statement preceded by the comment
+// "-- lint:ignore" is not checked; "-- lint:ignore drop-column,rename" skips
+// only these rules. Atlas's "-- atlas:nolint" works the same.
+var Rules = []Rule{
+	{"drop-table", Error, "Dropping a table deletes its data."},
+	{"drop-column", Error, "Dropping a column deletes its data, and breaks the code still reading it during a deploy."},
+	{"drop-schema", Error, "Dropping a schema or database deletes every table in it."},
+	{"delete-all", Error, "TRUNCATE, or DELETE without WHERE, deletes every row of a table."},
+	{"narrowing-type", Error, "Changing a column to a smaller or different type fails, or truncates values, when the rows do not fit."},
+	{"not-null-without-default", Error, "Adding a NOT NULL column without a default fails on a table that has rows."},
+	{"asymmetric-down", Error, "The down file does not revert the up file, or reverts changes the up file does not make."},
+	{"checksum", Error, "atlas.sum does not match the migration files: a migration was edited after it was hashed."},
+	{"invalid-dir", Error, "The directory has misnamed files, duplicate versions, or up files without a down file."},
+	{"type-change", Warning, "Changing the type of a column can rewrite and lock the table."},
+	{"set-not-null", Warning, "SET NOT NULL fails when the column has NULLs, and scans the table under a lock."},
+	{"rewrite-data", Warning, "UPDATE without WHERE rewrites every row of a table."},
+	{"rename", Warning, "Renaming a table or column breaks the code still using the old name during a deploy."},
+}
+
+var severities = func() map[string]Severity {
+	m := make(map[string]Severity, len(Rules))
+	for _, r := range Rules {
+		m[r.ID] = r.Severity
+	}
+	return m
+}()
+
+// Finding is a problem found in a migration file.
+type Finding struct {
+	Rule      string   `json:"rule"`
+	Severity  Severity `json:"severity"`
+	File      string   `json:"file"`
+	Line      int      `json:"line,omitempty"` // 0 when about the whole file
+	Message   string   `json:"message"`
+	Statement string   `json:"statement,omitempty"`
+}
+
+func (f Finding) String() string {
+	pos := f.File
+	if f.Line > 0 {
+		pos = fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", pos, f.Severity, f.Message, f.Rule)
+}
+
+// HasErrors reports whether a finding has the Error severity.
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint checks every migration of d, written for dialect. The up files are
+// replayed in order to know the column types a later file changes; tables
+// created in the same file can be changed or dropped freely, since they have
+// no rows yet.
+func Lint(d *migrator.Dir, dialect migrator.Dialect) ([]Finding, error) {
+	l := &linter{dir: d, dialect: dialect, schema: map[string]map[string]string{}}
+	l.validate()
+	for _, m := range d.Migrations {
+		if m.UpFile == "" {
+			continue
+		}
+		up, err := l.read(m.UpFile)
+		if err != nil {
+			return nil, err
+		}
+		l.up(up)
+		if d.Format != migrator.FormatMigrate || m.DownFile == "" {
+			continue
+		}
+		down, err := l.read(m.DownFile)
+		if err != nil {
+			return nil, err
+		}
+		l.symmetric(m, up, down)
+	}
+	return l.findings, nil
+}
+
+type linter struct {
+	dir      *migrator.Dir
+	dialect  migrator.Dialect
+	schema   map[string]map[string]string // table, column: type
+	findings []Finding
+}
+
+type statement struct {
+	file    string
+	line    int
+	sql     string
+	changes []change
+	ignore  []string // rules of lint:ignore, empty to ignore them all
+	ignored bool
+}
+
+func (l *linter) read(name string) ([]statement, error) {
+	src, err := l.dir.Read(name)
+	if err != nil {
+		return nil, err
+	}
+	var stmts []statement
+	for _, s := range migrator.ParseStatements(l.dialect, src) {
+		toks, comments := lex(l.dialect, s.SQL)
+		st := statement{file: name, line: s.Line, sql: strings.Join(strings.Fields(s.SQL), " "), changes: parse(toks)}
+		if len(toks) > 0 {
+			st.line += toks[0].line
+			st.sql = strings.Join(strings.Fields(s.SQL[tokenOffset(s.SQL, comments):]), " ")
+		}
+		st.ignore, st.ignored = ignores(comments)
+		stmts = append(stmts, st)
+	}
+	return stmts, nil
+}
+
+// tokenOffset skips the comments leading a statement, so findings quote the
+// SQL only.
+func tokenOffset(sql string, comments []string) int {
+	i := 0
+	for _, c := range comments {
+		rest := strings.TrimLeft(sql[i:], " \t\r\n")
+		if !strings.HasPrefix(rest, c) {
+			break
+		}
+		i = len(sql) - len(rest) + len(c)
+	}
+	return i
+}
+
+// ignores parses the lint:ignore and atlas:nolint directives of comments.
+func ignores(comments []string) (rules []string, ok bool) {
+	for _, c := range comments {
+		for _, directive := range []string{"lint:ignore", "atlas:nolint"} {
+			i := strings.Index(c, directive)
+			if i < 0 {
+				continue
+			}
+			rest := strings.TrimSuffix(strings.TrimSpace(c[i+len(directive):]), "*/")
+			ok = true
+			rules = append(rules, strings.FieldsFunc(rest, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })...)
+		}
+	}
+	return rules, ok
+}
+
+func (l *linter) report(st statement, rule, format string, args ...any) {
+	if st.ignored && (len(st.ignore) == 0 || contains(st.ignore, rule)) {
+		return
+	}
+	l.findings = append(l.findings, Finding{
+		Rule:      rule,
+		Severity:  severities[rule],
+		File:      filepath.Join(l.dir.Path, st.file),
+		Line:      st.line,
+		Message:   fmt.Sprintf(format, args...),
+		Statement: st.sql,
+	})
+}
+
+// validate reports the problems of Dir.Validate, each on its own.
+func (l *linter) validate() {
+	err := l.dir.Validate()
+	if err == nil {
+		return
+	}
+	problems := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		problems = joined.Unwrap()
+	}
+	for _, p := range problems {
+		f := Finding{Rule: "invalid-dir", File: l.dir.Path, Message: p.Error()}
+		if errors.Is(p, migrator.ErrChecksumMismatch) {
+			f.Rule, f.File = "checksum", filepath.Join(l.dir.Path, migrator.SumFile)
+		}
+		f.Severity = severities[f.Rule]
+		l.findings = append(l.findings, f)
+	}
+}
+
+// up checks the statements of an up file, and applies them to the schema.
+func (l *linter) up(stmts []statement) {
+	created := map[string]bool{}
+	for _, st := range stmts {
+		for _, c := range st.changes {
+			fresh := created[c.table]
+			switch c.kind {
+			case createTable:
+				created[c.table] = true
+				cols := map[string]string{}
+				for _, col := range c.columns {
+					cols[col.name] = col.typ
+				}
+				l.schema[c.table] = cols
+			case dropTable:
+				if !fresh {
+					l.report(st, "drop-table", "drops table %s", c.table)
+				}
+				delete(l.schema, c.table)
+			case renameTable:
+				if !fresh {
+					l.report(st, "rename", "renames table %s to %s", c.table, c.to)
+				}
+				l.schema[c.to], created[c.to] = l.schema[c.table], fresh
+				delete(l.schema, c.table)
+			case addColumn:
+				if c.notNull && !c.hasDefault && !fresh {
+					l.report(st, "not-null-without-default", "adds NOT NULL column %s.%s without a default", c.table, c.name)
+				}
+				l.setColumn(c.table, c.name, c.typ)
+			case dropColumn:
+				if !fresh {
+					l.report(st, "drop-column", "drops column %s.%s", c.table, c.name)
+				}
+				delete(l.schema[c.table], c.name)
+			case renameColumn:
+				if !fresh {
+					l.report(st, "rename", "renames column %s.%s to %s", c.table, c.name, c.to)
+				}
+				l.setColumn(c.table, c.to, l.schema[c.table][c.name])
+				delete(l.schema[c.table], c.name)
+			case alterType:
+				old := l.schema[c.table][c.name]
+				switch {
+				case fresh || old == c.typ:
+				case old == "":
+					l.report(st, "type-change", "changes the type of %s.%s to %s", c.table, c.name, c.typ)
+				case narrows(l.dialect, old, c.typ):
+					l.report(st, "narrowing-type", "changes %s.%s from %s to %s, which not every value fits", c.table, c.name, old, c.typ)
+				default:
+					l.report(st, "type-change", "changes %s.%s from %s to %s", c.table, c.name, old, c.typ)
+				}
+				l.setColumn(c.table, c.name, c.typ)
+			case setNotNull:
+				if !fresh {
+					l.report(st, "set-not-null", "sets %s.%s NOT NULL", c.table, c.name)
+				}
+			case dropSchema:
+				l.report(st, "drop-schema", "drops %s and every table in it", c.name)
+			case deleteAll:
+				if !fresh {
+					l.report(st, "delete-all", "deletes every row of %s", c.table)
+				}
+			case updateAll:
+				if !fresh {
+					l.report(st, "rewrite-data", "updates every row of %s", c.table)
+				}
+			}
+		}
+	}
+}
+
+func (l *linter) setColumn(table, name, typ string) {
+	if l.schema[table] == nil {
+		l.schema[table] = map[string]string{}
+	}
+	l.schema[table][name] = typ
+}
+
+// symmetric checks that the down file undoes what the up file does, and
+// drops nothing the up file did not create.
+func (l *linter) symmetric(m migrator.Migration, up, down []statement) {
+	did := map[string]bool{}
+	for _, st := range down {
+		for _, c := range st.changes {
+			did[key(c)] = true
+		}
+	}
+	if len(down) == 0 {
+		if len(up) > 0 {
+			l.report(statement{file: m.DownFile}, "asymmetric-down", "is empty: reverting %s leaves its changes", m.ID)
+		}
+		return
+	}
+
+	made := map[string]bool{}
+	for _, st := range up {
+		for _, c := range st.changes {
+			made[key(c)] = true
+			tableDropped := did[key(change{kind: dropTable, table: c.table})]
+			var undo string
+			switch c.kind {
+			case createTable:
+				if !tableDropped {
+					undo = "drop table " + c.table
+				}
+			case addColumn:
+				if !tableDropped && !did[key(change{kind: dropColumn, table: c.table, name: c.name})] {
+					undo = fmt.Sprintf("drop column %s.%s", c.table, c.name)
+				}
+			case createIndex:
+				if c.name != "" && !tableDropped && !did[key(change{kind: dropIndex, name: c.name})] {
+					undo = "drop index " + c.name
+				}
+			case createRole:
+				if !did[key(change{kind: dropRole, name: c.name})] {
+					undo = "drop role " + c.name
+				}
+			case createView:
+				if !did[key(change{kind: dropView, name: c.name})] {
+					undo = "drop view " + c.name
+				}
+			case dropTable:
+				if !did[key(change{kind: createTable, table: c.table})] {
+					undo = "create table " + c.table + " again"
+				}
+			case dropColumn:
+				if !tableDropped && !did[key(change{kind: createTable, table: c.table})] &&
+					!did[key(change{kind: addColumn, table: c.table, name: c.name})] {
+					undo = fmt.Sprintf("add column %s.%s again", c.table, c.name)
+				}
+			case renameTable:
+				if !did[key(change{kind: renameTable, table: c.to, to: c.table})] {
+					undo = fmt.Sprintf("rename table %s back to %s", c.to, c.table)
+				}
+			case renameColumn:
+				if !did[key(change{kind: renameColumn, table: c.table, name: c.to, to: c.name})] {
+					undo = fmt.Sprintf("rename column %s.%s back to %s", c.table, c.to, c.name)
+				}
+			}
+			if undo != "" {
+				l.report(st, "asymmetric-down", "%s does not %s", m.DownFile, undo)
+			}
+		}
+	}
+
+	for _, st := range down {
+		for _, c := range st.changes {
+			switch {
+			case c.kind == dropTable && !made[key(change{kind: createTable, table: c.table})] &&
+				!made[key(change{kind: renameTable, to: c.table})]:
+				l.report(st, "asymmetric-down", "drops table %s, which %s does not create", c.table, m.UpFile)
+			case c.kind == dropColumn && !made[key(change{kind: addColumn, table: c.table, name: c.name})] &&
+				!made[key(change{kind: createTable, table: c.table})]:
+				l.report(st, "asymmetric-down", "drops column %s.%s, which %s does not add", c.table, c.name, m.UpFile)
+			}
+		}
+	}
+}
+
+// key identifies a change for symmetric, without its column details.
+func key(c change) string {
+	switch c.kind {
+	case renameTable:
+		return fmt.Sprintf("%d %s", c.kind, c.to) // matched on the new name only
+	case createTable, dropTable:
+		return fmt.Sprintf("%d %s", c.kind, c.table)
+	}
+	return fmt.Sprintf("%d %s %s %s", c.kind, c.table, c.name, c.to)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,234 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"tmp/pragmatic-cases/migrator"
+)
+
+const entMigrations = "../../ent/getting-started-with-versioned-migrations/ent/migrate/migrations"
+
+func TestLint(t *testing.T) {
+	cases := []struct {
+		name    string
+		dialect migrator.Dialect // Postgres when not set
+		files   map[string]string
+		want    []string // file:line rule
+	}{
+		{
+			name: "additive changes",
+			files: map[string]string{
+				"1_users.up.sql":   "CREATE TABLE users (id serial PRIMARY KEY, name text NOT NULL);\nCREATE INDEX users_name ON users (name);",
+				"1_users.down.sql": "DROP TABLE users;",
+				"2_email.up.sql":   "ALTER TABLE users ADD COLUMN email varchar(255) NOT NULL DEFAULT '', ADD COLUMN bio text;",
+				"2_email.down.sql": "ALTER TABLE users DROP COLUMN email, DROP COLUMN bio;",
+			},
+		},
+		{
+			name: "destructive changes",
+			files: map[string]string{
+				"1_users.up.sql":   "CREATE TABLE users (id int, name varchar(255), age bigint, score numeric(10,2));",
+				"1_users.down.sql": "DROP TABLE users;",
+				"2_break.up.sql": `ALTER TABLE users DROP COLUMN name;
+ALTER TABLE users ALTER COLUMN age TYPE int;
+ALTER TABLE users ALTER COLUMN score TYPE numeric(12,2), ALTER COLUMN id TYPE bigint;
+ALTER TABLE users ADD COLUMN email text NOT NULL;
+ALTER TABLE users ALTER COLUMN age SET NOT NULL;
+DELETE FROM users;
+UPDATE users SET age = 0;
+ALTER TABLE users RENAME COLUMN email TO mail;
+DROP TABLE users;
+DROP SCHEMA public CASCADE;`,
+				"2_break.down.sql": "SELECT 1;",
+			},
+			want: []string{
+				"2_break.up.sql:1 drop-column",
+				"2_break.up.sql:1 asymmetric-down",
+				"2_break.up.sql:2 narrowing-type",
+				"2_break.up.sql:3 type-change",
+				"2_break.up.sql:3 type-change",
+				"2_break.up.sql:4 not-null-without-default",
+				"2_break.up.sql:4 asymmetric-down",
+				"2_break.up.sql:5 set-not-null",
+				"2_break.up.sql:6 delete-all",
+				"2_break.up.sql:7 rewrite-data",
+				"2_break.up.sql:8 rename",
+				"2_break.up.sql:8 asymmetric-down",
+				"2_break.up.sql:9 drop-table",
+				"2_break.up.sql:9 asymmetric-down",
+				"2_break.up.sql:10 drop-schema",
+			},
+		},
+		{
+			name:    "MySQL syntax",
+			dialect: migrator.MySQL,
+			files: map[string]string{
+				"1_t.up.sql":   "CREATE TABLE `t` (`id` INT UNSIGNED AUTO_INCREMENT, `name` VARCHAR(100) CHARACTER SET utf8mb4 NOT NULL, `body` MEDIUMTEXT, PRIMARY KEY (`id`));",
+				"1_t.down.sql": "DROP TABLE `t`;",
+				"2_t.up.sql": "ALTER TABLE `t` MODIFY COLUMN `name` VARCHAR(50) NOT NULL;\n" +
+					"ALTER TABLE `t` CHANGE `body` `content` LONGTEXT;\n" +
+					"ALTER TABLE `t` MODIFY `id` INT;",
+				"2_t.down.sql": "ALTER TABLE `t` MODIFY COLUMN `name` VARCHAR(100) NOT NULL;\n" +
+					"ALTER TABLE `t` CHANGE `content` `body` MEDIUMTEXT;\n" +
+					"ALTER TABLE `t` MODIFY `id` INT UNSIGNED;",
+			},
+			want: []string{
+				"2_t.up.sql:1 narrowing-type",
+				"2_t.up.sql:2 type-change",
+				"2_t.up.sql:2 rename",
+				"2_t.up.sql:3 narrowing-type",
+			},
+		},
+		{
+			name: "tables created in the same file have no rows",
+			files: map[string]string{
+				"1_t.up.sql":   "CREATE TABLE t (a int);\nALTER TABLE t ADD COLUMN b int NOT NULL;\nALTER TABLE t DROP COLUMN a;\nDELETE FROM t;",
+				"1_t.down.sql": "DROP TABLE t;",
+			},
+		},
+		{
+			name: "down files",
+			files: map[string]string{
+				"1_t.up.sql":   "CREATE TABLE t (a int);\nCREATE ROLE app;\nCREATE VIEW v AS SELECT a FROM t;",
+				"1_t.down.sql": "DROP VIEW v;\nDROP TABLE other;",
+				"2_t.up.sql":   "CREATE INDEX t_a ON t (a);",
+				"2_t.down.sql": "-- nothing to do\n",
+			},
+			want: []string{
+				"1_t.up.sql:1 asymmetric-down",
+				"1_t.up.sql:2 asymmetric-down",
+				"1_t.down.sql:2 asymmetric-down",
+				"2_t.down.sql asymmetric-down",
+			},
+		},
+		{
+			name: "lint:ignore",
+			files: map[string]string{
+				"1_t.up.sql":   "CREATE TABLE t (a int, b int);",
+				"1_t.down.sql": "DROP TABLE t;",
+				"2_t.up.sql": `-- b is unused since v2
+-- lint:ignore drop-column
+ALTER TABLE t DROP COLUMN b;
+/* lint:ignore */ DELETE FROM t;
+-- lint:ignore rename
+DROP TABLE t;`,
+				"2_t.down.sql": "CREATE TABLE t (a int, b int);",
+			},
+			want: []string{"2_t.up.sql:6 drop-table"},
+		},
+		{
+			name: "invalid directory",
+			files: map[string]string{
+				"1_t.up.sql": "CREATE TABLE t (a int);",
+			},
+			want: []string{" invalid-dir"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := writeDir(t, c.files)
+			if c.dialect.Name == "" {
+				c.dialect = migrator.Postgres
+			}
+			findings, err := Lint(d, c.dialect)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertFindings(t, d, findings, c.want)
+		})
+	}
+}
+
+func TestLintAtlas(t *testing.T) {
+	d, err := migrator.OpenDir(entMigrations, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err := Lint(d, migrator.Postgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertFindings(t, d, findings, []string{
+		"20240410023634_add_animal_again.sql:2 not-null-without-default",
+		"20240410023634_add_animal_again.sql:2 not-null-without-default",
+	})
+	if !HasErrors(findings) {
+		t.Error("got no errors")
+	}
+
+	t.Run("edited migration", func(t *testing.T) {
+		d := writeDir(t, map[string]string{
+			"20240101000000_init.sql": "CREATE TABLE t (a int);",
+			migrator.SumFile:          "h1:AAAA=\n20240101000000_init.sql h1:AAAA=\n",
+		})
+		findings, err := Lint(d, migrator.Postgres)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertFindings(t, d, findings, []string{"atlas.sum checksum"})
+	})
+}
+
+func TestLintRepoMigrations(t *testing.T) {
+	for path, dialect := range map[string]migrator.Dialect{
+		"../../migrate/mysql/migrations":    migrator.MySQL,
+		"../../migrate/postgres/migrations": migrator.Postgres,
+		"../testdata/migrate":               migrator.SQLite,
+		"../testdata/atlas":                 migrator.SQLite,
+	} {
+		d, err := migrator.OpenDir(path, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		findings, err := Lint(d, dialect)
+		if err != nil || len(findings) > 0 {
+			t.Errorf("%s: got %v, %v", path, findings, err)
+		}
+	}
+}
+
+func writeDir(t *testing.T, files map[string]string) *migrator.Dir {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d, err := migrator.OpenDir(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// assertFindings compares findings as "file:line rule", the file relative
+// to the directory and without a line when 0, in any order.
+func assertFindings(t testing.TB, d *migrator.Dir, findings []Finding, want []string) {
+	t.Helper()
+	got := map[string]int{}
+	for _, f := range findings {
+		rel, _ := filepath.Rel(d.Path, f.File)
+		if rel == "." {
+			rel = ""
+		}
+		if f.Line > 0 {
+			rel += ":" + strconv.Itoa(f.Line)
+		}
+		got[rel+" "+f.Rule]++
+	}
+	wanted := map[string]int{}
+	for _, w := range want {
+		wanted[w]++
+	}
+	if !reflect.DeepEqual(got, wanted) {
+		for _, f := range findings {
+			t.Log(f)
+		}
+		t.Errorf("got %v, want %v", got, wanted)
+	}
+}
@@ -0,0 +1,397 @@
+package lint
+
+import "strings"
+
+type changeKind int
+
+const (
+	createTable changeKind = iota
+	dropTable
+	renameTable
+	addColumn
+	dropColumn
+	renameColumn
+	alterType
+	setNotNull
+	createIndex
+	dropIndex
+	createRole
+	dropRole
+	createView
+	dropView
+	dropSchema
+	deleteAll
+	updateAll
+)
+
+// change is what a statement does to the schema or its data, as far as the
+// rules care. A statement can make several, like ALTER TABLE with many
+// actions.
+type change struct {
+	kind    changeKind
+	table   string
+	name    string // column, index, role, view or schema
+	to      string // new name of a rename
+	typ     string // column type, lower-cased: "varchar(255)"
+	notNull bool
+	// hasDefault is also set for values the database fills in: identity,
+	// serial and AUTO_INCREMENT columns
+	hasDefault bool
+	columns    []column // of createTable
+}
+
+type column struct {
+	name string
+	typ  string
+}
+
+// parser walks the tokens of one statement.
+type parser struct {
+	toks []token
+	i    int
+}
+
+func (p *parser) done() bool { return p.i >= len(p.toks) }
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{kind: punct}
+	}
+	return p.toks[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.i++
+	return t
+}
+
+// accept consumes the keywords if the next tokens are them, in order.
+func (p *parser) accept(keywords ...string) bool {
+	for j, k := range keywords {
+		if p.i+j >= len(p.toks) || !p.toks[p.i+j].is(k) {
+			return false
+		}
+	}
+	p.i += len(keywords)
+	return true
+}
+
+func (p *parser) acceptAny(keywords ...string) bool {
+	if p.peek().is(keywords...) {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) ifExists() {
+	if !p.accept("IF", "EXISTS") {
+		p.accept("IF", "NOT", "EXISTS")
+	}
+}
+
+// qualifiedName reads schema.table, or 'user'@'host' whose host is dropped.
+func (p *parser) qualifiedName() string {
+	parts := []string{p.next().name()}
+	for p.peek().text == "." {
+		p.next()
+		parts = append(parts, p.next().name())
+	}
+	if p.peek().text == "@" {
+		p.next()
+		p.next()
+	}
+	return strings.Join(parts, ".")
+}
+
+// names reads a comma separated list of names, as in DROP TABLE a, b.
+func (p *parser) names() []string {
+	names := []string{p.qualifiedName()}
+	for p.peek().text == "," {
+		p.next()
+		names = append(names, p.qualifiedName())
+	}
+	return names
+}
+
+// hasWord reports whether a keyword appears outside of parentheses.
+func (p *parser) hasWord(keyword string) bool {
+	depth := 0
+	for _, t := range p.toks[p.i:] {
+		switch {
+		case t.text == "(" && t.kind == punct:
+			depth++
+		case t.text == ")" && t.kind == punct:
+			depth--
+		case depth == 0 && t.is(keyword):
+			return true
+		}
+	}
+	return false
+}
+
+// parse returns the changes of a statement, none for those the rules do not
+// look at.
+func parse(toks []token) []change {
+	p := &parser{toks: toks}
+	switch {
+	case p.accept("CREATE"):
+		return p.create()
+	case p.accept("DROP"):
+		return p.drop()
+	case p.accept("ALTER", "TABLE"):
+		p.ifExists()
+		p.acceptAny("ONLY")
+		table := p.qualifiedName()
+		var changes []change
+		for _, action := range splitTopLevel(p.toks[p.i:]) {
+			changes = append(changes, alterAction(table, action)...)
+		}
+		return changes
+	case p.accept("RENAME", "TABLE"):
+		from := p.qualifiedName()
+		p.acceptAny("TO")
+		return []change{{kind: renameTable, table: from, to: p.qualifiedName()}}
+	case p.accept("TRUNCATE"):
+		p.acceptAny("TABLE")
+		p.acceptAny("ONLY")
+		var changes []change
+		for _, name := range p.names() {
+			changes = append(changes, change{kind: deleteAll, table: name})
+		}
+		return changes
+	case p.accept("DELETE", "FROM"):
+		table := p.qualifiedName()
+		if !p.hasWord("WHERE") {
+			return []change{{kind: deleteAll, table: table}}
+		}
+	case p.accept("UPDATE"):
+		table := p.qualifiedName()
+		if !p.hasWord("WHERE") {
+			return []change{{kind: updateAll, table: table}}
+		}
+	}
+	return nil
+}
+
+func (p *parser) create() []change {
+	p.accept("OR", "REPLACE")
+	p.acceptAny("TEMPORARY", "TEMP", "UNIQUE", "UNLOGGED")
+	switch {
+	case p.accept("TABLE"):
+		p.ifExists()
+		c := change{kind: createTable, table: p.qualifiedName()}
+		if p.peek().text != "(" {
+			return []change{c} // CREATE TABLE ... AS SELECT
+		}
+		p.next()
+		for _, def := range splitTopLevel(p.toks[p.i:]) {
+			if col, ok := columnDef(def); ok {
+				c.columns = append(c.columns, column{name: col.name, typ: col.typ})
+			}
+		}
+		return []change{c}
+	case p.accept("INDEX"):
+		p.acceptAny("CONCURRENTLY")
+		p.ifExists()
+		var name string
+		if !p.peek().is("ON") {
+			name = p.qualifiedName()
+		}
+		p.accept("ON")
+		p.acceptAny("ONLY")
+		return []change{{kind: createIndex, name: name, table: p.qualifiedName()}}
+	case p.acceptAny("ROLE", "USER"):
+		p.ifExists()
+		var changes []change
+		for _, name := range p.names() {
+			changes = append(changes, change{kind: createRole, name: name})
+		}
+		return changes
+	case p.accept("VIEW"), p.accept("MATERIALIZED", "VIEW"):
+		p.ifExists()
+		return []change{{kind: createView, name: p.qualifiedName()}}
+	}
+	return nil
+}
+
+func (p *parser) drop() []change {
+	var kind changeKind
+	switch {
+	case p.accept("TABLE"):
+		kind = dropTable
+	case p.accept("INDEX"):
+		p.acceptAny("CONCURRENTLY")
+		p.ifExists()
+		return []change{{kind: dropIndex, name: p.qualifiedName()}}
+	case p.acceptAny("ROLE", "USER"):
+		kind = dropRole
+	case p.accept("VIEW"), p.accept("MATERIALIZED", "VIEW"):
+		kind = dropView
+	case p.acceptAny("SCHEMA", "DATABASE"):
+		kind = dropSchema
+	default:
+		return nil
+	}
+	p.ifExists()
+	var changes []change
+	for _, name := range p.names() {
+		c := change{kind: kind, name: name}
+		if kind == dropTable {
+			c = change{kind: kind, table: name}
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// alterAction returns the changes of one action of ALTER TABLE, in the
+// syntax of Postgres, MySQL or SQLite.
+func alterAction(table string, toks []token) []change {
+	p := &parser{toks: toks}
+	switch {
+	case p.accept("ADD"):
+		if p.peek().is("CONSTRAINT", "INDEX", "KEY", "UNIQUE", "PRIMARY", "FOREIGN", "CHECK", "FULLTEXT", "SPATIAL") {
+			return nil
+		}
+		p.acceptAny("COLUMN")
+		p.ifExists()
+		col, ok := columnDef(p.toks[p.i:])
+		if !ok {
+			return nil
+		}
+		col.kind, col.table = addColumn, table
+		return []change{col}
+	case p.accept("DROP"):
+		if p.acceptAny("INDEX", "KEY") {
+			p.ifExists()
+			return []change{{kind: dropIndex, name: p.next().name()}}
+		}
+		if p.peek().is("CONSTRAINT", "PRIMARY", "FOREIGN", "CHECK", "DEFAULT") {
+			return nil
+		}
+		p.acceptAny("COLUMN")
+		p.ifExists()
+		return []change{{kind: dropColumn, table: table, name: p.next().name()}}
+	case p.accept("ALTER"):
+		p.acceptAny("COLUMN")
+		name := p.next().name()
+		switch {
+		case p.accept("SET", "DATA", "TYPE"), p.accept("TYPE"):
+			return []change{{kind: alterType, table: table, name: name, typ: typeName(p.toks[p.i:])}}
+		case p.accept("SET", "NOT", "NULL"):
+			return []change{{kind: setNotNull, table: table, name: name}}
+		}
+	case p.accept("MODIFY"):
+		p.acceptAny("COLUMN")
+		col, ok := columnDef(p.toks[p.i:])
+		if !ok {
+			return nil
+		}
+		return []change{{kind: alterType, table: table, name: col.name, typ: col.typ}}
+	case p.accept("CHANGE"):
+		p.acceptAny("COLUMN")
+		from := p.next().name()
+		col, ok := columnDef(p.toks[p.i:])
+		if !ok {
+			return nil
+		}
+		changes := []change{{kind: alterType, table: table, name: from, typ: col.typ}}
+		if col.name != from {
+			changes = append(changes, change{kind: renameColumn, table: table, name: from, to: col.name})
+		}
+		return changes
+	case p.accept("RENAME", "COLUMN"):
+		from := p.next().name()
+		p.acceptAny("TO")
+		return []change{{kind: renameColumn, table: table, name: from, to: p.next().name()}}
+	case p.accept("RENAME"):
+		if !p.acceptAny("TO", "AS") {
+			// RENAME a TO b is a column in Postgres
+			from := p.next().name()
+			p.acceptAny("TO")
+			return []change{{kind: renameColumn, table: table, name: from, to: p.next().name()}}
+		}
+		return []change{{kind: renameTable, table: table, to: p.qualifiedName()}}
+	}
+	return nil
+}
+
+// columnDef reads "name type constraints...", and tells constraints of
+// CREATE TABLE apart from columns.
+func columnDef(toks []token) (change, bool) {
+	if len(toks) < 2 || toks[0].is("CONSTRAINT", "PRIMARY", "FOREIGN", "UNIQUE", "KEY", "INDEX", "CHECK", "FULLTEXT", "SPATIAL", "EXCLUDE", "LIKE") {
+		return change{}, false
+	}
+	c := change{name: toks[0].name(), typ: typeName(toks[1:])}
+	if strings.Contains(c.typ, "serial") {
+		c.hasDefault = true
+	}
+	for i, t := range toks[1:] {
+		switch {
+		case t.is("NOT") && i+2 < len(toks) && toks[i+2].is("NULL"):
+			c.notNull = true
+		case t.is("PRIMARY"):
+			c.notNull = true
+		case t.is("DEFAULT", "GENERATED", "AUTO_INCREMENT", "AUTOINCREMENT", "IDENTITY", "AS"):
+			c.hasDefault = true
+		}
+	}
+	return c, true
+}
+
+// typeName reads the type at the start of toks, until its first constraint,
+// as lower-cased text without spaces around parentheses and commas.
+func typeName(toks []token) string {
+	var b strings.Builder
+	depth := 0
+	for i, t := range toks {
+		if depth == 0 {
+			if t.text == "," || t.text == ")" ||
+				t.is("NOT", "NULL", "DEFAULT", "PRIMARY", "REFERENCES", "UNIQUE", "CHECK", "CONSTRAINT", "GENERATED",
+					"AUTO_INCREMENT", "AUTOINCREMENT", "COLLATE", "COMMENT", "ON", "FIRST", "AFTER", "USING", "IDENTITY", "CHARSET", "AS") ||
+				t.is("CHARACTER") && i+1 < len(toks) && toks[i+1].is("SET") {
+				break
+			}
+		}
+		switch t.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+		if b.Len() > 0 && t.kind != punct && !strings.HasSuffix(b.String(), "(") && !strings.HasSuffix(b.String(), ",") {
+			b.WriteByte(' ')
+		}
+		b.WriteString(strings.ToLower(t.text))
+	}
+	return b.String()
+}
+
+// splitTopLevel splits tokens at the commas outside of parentheses, and
+// stops at the parenthesis closing the list, if any.
+func splitTopLevel(toks []token) [][]token {
+	var parts [][]token
+	depth, start := 0, 0
+	for i, t := range toks {
+		if t.kind != punct {
+			continue
+		}
+		switch t.text {
+		case "(":
+			depth++
+		case ")":
+			if depth == 0 {
+				return append(parts, toks[start:i])
+			}
+			depth--
+		case ",":
+			if depth == 0 {
+				parts = append(parts, toks[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, toks[start:])
+}
@@ -0,0 +1,90 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var findings = []Finding{
+	{Rule: "drop-column", Severity: Error, File: "migrations/2_t.up.sql", Line: 3, Message: "drops column t.b", Statement: "ALTER TABLE t DROP COLUMN b"},
+	{Rule: "checksum", Severity: Error, File: "migrations/atlas.sum", Message: "checksum mismatch"},
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteText(&buf, findings); err != nil {
+		t.Fatal(err)
+	}
+	want := "migrations/2_t.up.sql:3: error: drops column t.b (drop-column)\nmigrations/atlas.sum: error: checksum mismatch (checksum)\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "[]" {
+		t.Errorf("got %s for no findings, want []", got)
+	}
+
+	buf.Reset()
+	if err := WriteJSON(&buf, findings); err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got[0]["rule"] != "drop-column" || got[0]["line"] != 3.0 || got[1]["line"] != nil {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, findings); err != nil {
+		t.Fatal(err)
+	}
+	var log struct {
+		Version string
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct{ ID string }
+				}
+			}
+			Results []struct {
+				RuleID    string
+				Level     string
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string }
+						Region           *struct{ StartLine int }
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Tool.Driver.Rules) != len(Rules) {
+		t.Fatalf("got %s", buf.String())
+	}
+	results := log.Runs[0].Results
+	if len(results) != 2 || results[0].RuleID != "drop-column" || results[0].Level != "error" {
+		t.Fatalf("got %+v", results)
+	}
+	loc := results[0].Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "migrations/2_t.up.sql" || loc.Region == nil || loc.Region.StartLine != 3 {
+		t.Errorf("got %+v", loc)
+	}
+	if results[1].Locations[0].PhysicalLocation.Region != nil {
+		t.Error("got a region for a finding without a line")
+	}
+}
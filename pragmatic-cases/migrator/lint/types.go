@@ -0,0 +1,131 @@
+package lint
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"tmp/pragmatic-cases/migrator"
+)
+
+// sqlType is a column type reduced to what narrowing compares: types of a
+// family convert into each other, and a larger size holds every value of a
+// smaller one.
+type sqlType struct {
+	family string
+	// bytes of integers, rank of floats, length of strings and times,
+	// precision of numerics
+	size     int64
+	scale    int64 // of numerics
+	unsigned bool
+}
+
+const unbounded = 1 << 40
+
+var integers = map[string]int64{
+	"tinyint": 1, "smallint": 2, "int2": 2, "smallserial": 2, "mediumint": 3,
+	"int": 4, "integer": 4, "int4": 4, "serial": 4,
+	"bigint": 8, "int8": 8, "bigserial": 8,
+}
+
+var strs = map[string]int64{
+	"tinytext": 255, "text": 65535, "mediumtext": 1<<24 - 1, "longtext": 1<<32 - 1,
+	"varchar": unbounded, "character varying": unbounded, "nvarchar": unbounded,
+	"char": 1, "character": 1, "nchar": 1, "bpchar": 1, "clob": unbounded,
+}
+
+var floats = map[string]int64{
+	"real": 1, "float4": 1, "float": 2, "double": 2, "double precision": 2, "float8": 2,
+}
+
+var times = map[string]int64{
+	"date": 1, "datetime": 2, "timestamp": 2, "timestamp without time zone": 2,
+	"timestamptz": 3, "timestamp with time zone": 3,
+}
+
+// parseType reads a type of dialect d as typeName writes it: "varchar(255)",
+// "int unsigned", "numeric(10,2)".
+func parseType(d migrator.Dialect, s string) sqlType {
+	base, args := s, []int64(nil)
+	if i := strings.IndexByte(s, '('); i >= 0 {
+		base = strings.TrimSpace(s[:i])
+		end := strings.IndexByte(s, ')')
+		if end < i {
+			end = len(s)
+		}
+		for _, a := range strings.Split(s[i+1:end], ",") {
+			n, err := strconv.ParseInt(strings.TrimSpace(a), 10, 64)
+			if err == nil {
+				args = append(args, n)
+			}
+		}
+		if end < len(s) {
+			base = strings.TrimSpace(base + s[end+1:])
+		}
+	}
+	unsigned := strings.HasSuffix(base, " unsigned")
+	base = strings.TrimSuffix(base, " unsigned")
+
+	switch {
+	case integers[base] > 0:
+		return sqlType{family: "integer", size: integers[base], unsigned: unsigned}
+	case strs[base] > 0:
+		t := sqlType{family: "string", size: strs[base]}
+		if len(args) > 0 {
+			t.size = args[0]
+		}
+		return t
+	case floats[base] > 0:
+		return sqlType{family: "float", size: floats[base]}
+	case times[base] > 0:
+		return sqlType{family: "time", size: times[base]}
+	case base == "numeric" || base == "decimal":
+		// a bare numeric holds any value in Postgres and SQLite, and is
+		// decimal(10,0) in MySQL
+		t := sqlType{family: "numeric", size: unbounded, scale: unbounded, unsigned: unsigned}
+		if d.Name == "mysql" {
+			t.size, t.scale = 10, 0
+		}
+		if len(args) > 0 {
+			t.size, t.scale = args[0], 0
+		}
+		if len(args) > 1 {
+			t.scale = args[1]
+		}
+		return t
+	}
+	return sqlType{family: base}
+}
+
+// narrows reports whether converting a column from the type from to the
+// type to of dialect d can fail or lose values. Converting to a string long
+// enough for any value never does.
+func narrows(d migrator.Dialect, from, to string) bool {
+	f, t := parseType(d, from), parseType(d, to)
+	if f.family != t.family {
+		return t.family != "string" || t.size < unbounded && t.size < 65535
+	}
+	switch {
+	case f.family == "integer":
+		fmin, fmax := f.intRange()
+		tmin, tmax := t.intRange()
+		return tmin > fmin || tmax < fmax
+	case t.unsigned && !f.unsigned:
+		return true
+	case t.size < f.size:
+		return true
+	case f.family == "numeric" && t.size < unbounded:
+		// digits left of the point must fit too
+		return t.scale < f.scale || t.size-t.scale < f.size-f.scale
+	}
+	return false
+}
+
+// intRange is the range of an integer type, its maximum excluded.
+func (t sqlType) intRange() (min, max float64) {
+	bits := int(t.size) * 8
+	if t.unsigned {
+		return 0, math.Ldexp(1, bits)
+	}
+	return -math.Ldexp(1, bits-1), math.Ldexp(1, bits-1)
+}
@@ -0,0 +1,66 @@
+package lint
+
+import (
+	"testing"
+
+	"tmp/pragmatic-cases/migrator"
+)
+
+func TestNarrows(t *testing.T) {
+	cases := []struct {
+		dialect  migrator.Dialect
+		from, to string
+		want     bool
+	}{
+		{migrator.Postgres, "int", "bigint", false},
+		{migrator.Postgres, "bigint", "int", true},
+		{migrator.Postgres, "integer", "int4", false},
+		{migrator.Postgres, "int", "int unsigned", true},
+		{migrator.Postgres, "varchar(255)", "varchar(100)", true},
+		{migrator.Postgres, "varchar(100)", "varchar(255)", false},
+		{migrator.Postgres, "varchar(255)", "text", false},
+		{migrator.Postgres, "text", "varchar(255)", true},
+		{migrator.Postgres, "character varying", "varchar(255)", true},
+		{migrator.Postgres, "longtext", "mediumtext", true},
+		{migrator.Postgres, "numeric(10,2)", "numeric(12,2)", false},
+		{migrator.Postgres, "numeric(10,2)", "numeric(10,4)", true},
+		{migrator.Postgres, "numeric(10,2)", "numeric", false},
+		{migrator.Postgres, "numeric", "numeric(10,2)", true},
+		{migrator.Postgres, "double precision", "real", true},
+		{migrator.Postgres, "timestamp", "date", true},
+		{migrator.Postgres, "timestamp", "timestamptz", false},
+		{migrator.Postgres, "int", "text", false},
+		{migrator.Postgres, "int", "varchar(5)", true},
+		{migrator.Postgres, "text", "int", true},
+		{migrator.Postgres, "uuid", "uuid", false},
+		{migrator.MySQL, "int unsigned", "bigint", false},
+		{migrator.MySQL, "int unsigned", "int", true},
+		{migrator.MySQL, "bigint unsigned", "bigint", true},
+		{migrator.MySQL, "tinyint unsigned", "smallint", false},
+		{migrator.MySQL, "mediumint", "int", false},
+		{migrator.MySQL, "decimal", "decimal(12,2)", false},
+		{migrator.MySQL, "decimal(12,2)", "decimal", true},
+		{migrator.MySQL, "decimal(5,0)", "decimal", false},
+		{migrator.MySQL, "decimal(10,2) unsigned", "decimal(10,2)", false},
+		{migrator.MySQL, "decimal(10,2)", "decimal(10,2) unsigned", true},
+		{migrator.Postgres, "numeric", "numeric(12,2)", true},
+	}
+	for _, c := range cases {
+		if got := narrows(c.dialect, c.from, c.to); got != c.want {
+			t.Errorf("%s: narrows(%q, %q) = %v, want %v", c.dialect.Name, c.from, c.to, got, c.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	toks, _ := lex(migrator.Postgres, `ALTER TABLE "public"."Users" ADD COLUMN IF NOT EXISTS age numeric(5, 1) NOT NULL DEFAULT 0, DROP CONSTRAINT users_pkey`)
+	got := parse(toks)
+	if len(got) != 1 {
+		t.Fatalf("got %+v", got)
+	}
+	want := change{kind: addColumn, table: "public.Users", name: "age", typ: "numeric(5,1)", notNull: true, hasDefault: true}
+	if got[0].kind != want.kind || got[0].table != want.table || got[0].name != want.name ||
+		got[0].typ != want.typ || got[0].notNull != want.notNull || got[0].hasDefault != want.hasDefault {
+		t.Errorf("got %+v, want %+v", got[0], want)
+	}
+}
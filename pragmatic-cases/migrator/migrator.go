@@ -0,0 +1,297 @@
+// Package migrator runs golang-migrate and Atlas migration directories
+// against MySQL, Postgres or SQLite.
+//
+// Both formats record the version in the table golang-migrate uses,
+// schema_migrations (version, dirty), so a database migrated with the
+// migrate CLI or pragmatic-cases/migrate can be taken over. Atlas
+// directories are checked against atlas.sum before they run, but their
+// version is not recorded in atlas_schema_revisions: do not also run
+// `atlas migrate apply` on the same database.
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	ErrNoChange = errors.New("migrator: no change")
+	ErrDirty    = errors.New("migrator: database is dirty, fix it and force the version")
+)
+
+// Migrator migrates db with the migrations of dir.
+type Migrator struct {
+	db      *sql.DB
+	dialect Dialect
+	dir     *Dir
+	table   string
+	log     io.Writer
+}
+
+type Option func(*Migrator)
+
+// WithTable records the version in table instead of schema_migrations.
+func WithTable(table string) Option {
+	return func(m *Migrator) { m.table = table }
+}
+
+// WithLog writes a line per migration run to w.
+func WithLog(w io.Writer) Option {
+	return func(m *Migrator) { m.log = w }
+}
+
+func New(db *sql.DB, dialect Dialect, dir *Dir, opts ...Option) *Migrator {
+	m := &Migrator{db: db, dialect: dialect, dir: dir, table: "schema_migrations", log: io.Discard}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Status is the version of the database against the directory.
+type Status struct {
+	Version uint64 // 0 when nothing was applied
+	Dirty   bool
+	Applied []Migration
+	Pending []Migration
+	// Unknown is set when Version is not a migration of the directory, e.g.
+	// a file was removed after it was applied.
+	Unknown bool
+}
+
+func (m *Migrator) Status(ctx context.Context) (Status, error) {
+	var status Status
+	err := m.withConn(ctx, func(conn *sql.Conn) error {
+		var err error
+		status.Version, status.Dirty, err = m.version(ctx, conn)
+		return err
+	})
+	if err != nil {
+		return Status{}, err
+	}
+	for _, mig := range m.dir.Migrations {
+		if mig.Version <= status.Version {
+			status.Applied = append(status.Applied, mig)
+		} else {
+			status.Pending = append(status.Pending, mig)
+		}
+	}
+	_, found := m.dir.Index(status.Version)
+	status.Unknown = status.Version != 0 && !found
+	return status, nil
+}
+
+// Up applies the next n pending migrations, all of them when n <= 0.
+func (m *Migrator) Up(ctx context.Context, n int) error {
+	return m.migrate(ctx, func(current uint64) (uint64, error) {
+		i, _ := m.dir.Index(current + 1)
+		pending := len(m.dir.Migrations) - i
+		if pending == 0 {
+			return 0, ErrNoChange
+		}
+		if n <= 0 || n > pending {
+			n = pending
+		}
+		return m.dir.Migrations[i+n-1].Version, nil
+	})
+}
+
+// Down reverts the last n applied migrations, all of them when n <= 0.
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	return m.migrate(ctx, func(current uint64) (uint64, error) {
+		if current == 0 {
+			return 0, ErrNoChange
+		}
+		i, ok := m.dir.Index(current)
+		if !ok {
+			return 0, fmt.Errorf("%w: %d is applied", ErrUnknownVersion, current)
+		}
+		if n <= 0 || n > i {
+			return 0, nil
+		}
+		return m.dir.Migrations[i-n].Version, nil
+	})
+}
+
+// Goto migrates up or down to version, 0 being before the first migration.
+func (m *Migrator) Goto(ctx context.Context, version uint64) error {
+	if _, ok := m.dir.Index(version); !ok && version != 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+	return m.migrate(ctx, func(current uint64) (uint64, error) {
+		if current == version {
+			return 0, ErrNoChange
+		}
+		return version, nil
+	})
+}
+
+// Force sets the version and clears the dirty flag without running any
+// migration, after a failed migration was fixed by hand. 0 forgets the
+// version.
+func (m *Migrator) Force(ctx context.Context, version uint64) error {
+	if _, ok := m.dir.Index(version); !ok && version != 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+	return m.withConn(ctx, func(conn *sql.Conn) error {
+		return m.setVersion(ctx, conn, version, false)
+	})
+}
+
+// migrate runs the migrations between the current version and the one
+// target returns, up or down.
+func (m *Migrator) migrate(ctx context.Context, target func(current uint64) (uint64, error)) error {
+	if m.dir.Format == FormatAtlas {
+		if err := m.dir.VerifySum(); err != nil {
+			return err
+		}
+	}
+	return m.withConn(ctx, func(conn *sql.Conn) error {
+		current, dirty, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w: version %d", ErrDirty, current)
+		}
+		to, err := target(current)
+		if err != nil {
+			return err
+		}
+
+		if to > current {
+			for _, mig := range m.dir.Migrations {
+				if mig.Version > current && mig.Version <= to {
+					if err := m.run(ctx, conn, mig, mig.UpFile, mig.Version); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+
+		for i := len(m.dir.Migrations) - 1; i >= 0; i-- {
+			mig := m.dir.Migrations[i]
+			if mig.Version > current || mig.Version <= to {
+				continue
+			}
+			if mig.DownFile == "" {
+				return fmt.Errorf("%w: %s", ErrIrreversible, mig)
+			}
+			var previous uint64
+			if i > 0 {
+				previous = m.dir.Migrations[i-1].Version
+			}
+			if err := m.run(ctx, conn, mig, mig.DownFile, previous); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// run runs the statements of file and records version, in one transaction
+// when the dialect can. Otherwise the version is recorded dirty first, and
+// stays so if a statement fails.
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, mig Migration, file string, version uint64) error {
+	src, err := m.dir.Read(file)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+
+	if m.dialect.Transactional {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		if err := m.exec(ctx, tx, file, src); err != nil {
+			return err
+		}
+		if err := m.setVersion(ctx, tx, version, false); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	} else {
+		if err := m.setVersion(ctx, conn, version, true); err != nil {
+			return err
+		}
+		if err := m.exec(ctx, conn, file, src); err != nil {
+			return err
+		}
+		if err := m.setVersion(ctx, conn, version, false); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(m.log, "%s (%s)\n", file, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (m *Migrator) exec(ctx context.Context, db execer, file, src string) error {
+	for i, stmt := range SplitStatements(m.dialect, src) {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("%s: statement %d: %w", file, i+1, err)
+		}
+	}
+	return nil
+}
+
+// withConn runs f on one connection holding the migration lock, with the
+// version table created.
+func (m *Migrator) withConn(ctx context.Context, f func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	unlock, err := m.dialect.lock(ctx, conn, "migrator:"+m.table)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	create := "CREATE TABLE IF NOT EXISTS " + m.dialect.quote(m.table) + " (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)"
+	if _, err := conn.ExecContext(ctx, create); err != nil {
+		return fmt.Errorf("create %s: %w", m.table, err)
+	}
+	return f(conn)
+}
+
+func (m *Migrator) version(ctx context.Context, conn *sql.Conn) (uint64, bool, error) {
+	var version int64
+	var dirty bool
+	err := conn.QueryRowContext(ctx, "SELECT version, dirty FROM "+m.dialect.quote(m.table)+" LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return uint64(version), dirty, nil
+}
+
+// setVersion records version the way golang-migrate does: the table has at
+// most one row, and none before the first migration.
+func (m *Migrator) setVersion(ctx context.Context, db execer, version uint64, dirty bool) error {
+	table := m.dialect.quote(m.table)
+	if _, err := db.ExecContext(ctx, "DELETE FROM "+table); err != nil {
+		return err
+	}
+	if version == 0 && !dirty {
+		return nil
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (version, dirty) VALUES (%s, %s)", table, m.dialect.placeholder(1), m.dialect.placeholder(2))
+	_, err := db.ExecContext(ctx, insert, int64(version), dirty)
+	return err
+}
@@ -0,0 +1,134 @@
+package migrator
+
+import "strings"
+
+// Statement is a statement of a migration file and the line it starts on,
+// counting from 1.
+type Statement struct {
+	SQL  string
+	Line int
+}
+
+// SplitStatements splits a migration file into its statements at the
+// semicolons outside of strings, quoted identifiers, comments and Postgres
+// dollar-quoted bodies. Statements that are only comments are dropped. The
+// trailing semicolon is not kept. # comments and backslash escapes are
+// recognized where the dialect has them.
+//
+// Bodies with semicolons delimited otherwise, like MySQL triggers with
+// BEGIN ... END, are not supported: put them alone in their own file.
+func SplitStatements(d Dialect, src string) []string {
+	var stmts []string
+	for _, stmt := range ParseStatements(d, src) {
+		stmts = append(stmts, stmt.SQL)
+	}
+	return stmts
+}
+
+// ParseStatements is SplitStatements with the line of each statement.
+func ParseStatements(d Dialect, src string) []Statement {
+	var stmts []Statement
+	start := 0
+	flush := func(end int) {
+		raw := src[start:end]
+		stmt := strings.TrimSpace(raw)
+		if stmt != "" && !onlyComments(d, stmt) {
+			leading := len(raw) - len(strings.TrimLeft(raw, " \t\r\n"))
+			line := strings.Count(src[:start+leading], "\n") + 1
+			stmts = append(stmts, Statement{SQL: stmt, Line: line})
+		}
+	}
+
+	for i := 0; i < len(src); i++ {
+		switch c := src[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(src, i, d.BackslashEscapes(src, i))
+		case c == '-' && strings.HasPrefix(src[i:], "--"):
+			i = skipLine(src, i)
+		case c == '#' && d.HashComments():
+			i = skipLine(src, i)
+		case c == '/' && strings.HasPrefix(src[i:], "/*"):
+			if end := strings.Index(src[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(src)
+			}
+		case c == '$':
+			i = skipDollarQuoted(src, i)
+		case c == ';':
+			flush(i)
+			start = i + 1
+		}
+	}
+	flush(len(src))
+	return stmts
+}
+
+// skipQuoted returns the index of the quote closing the one at src[i].
+// Doubled quotes escape it, and backslashes too when backslash is set.
+func skipQuoted(src string, i int, backslash bool) int {
+	quote := src[i]
+	for i++; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			if backslash {
+				i++
+			}
+		case quote:
+			if i+1 < len(src) && src[i+1] == quote {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return len(src)
+}
+
+func skipLine(src string, i int) int {
+	if end := strings.IndexByte(src[i:], '\n'); end >= 0 {
+		return i + end
+	}
+	return len(src)
+}
+
+// skipDollarQuoted skips $$...$$ and $tag$...$tag$, and leaves $1 alone.
+func skipDollarQuoted(src string, i int) int {
+	end := strings.IndexByte(src[i+1:], '$')
+	if end < 0 {
+		return i
+	}
+	tag := src[i : i+end+2]
+	for _, r := range tag[1 : len(tag)-1] {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return i
+		}
+	}
+	if len(tag) > 2 && tag[1] >= '0' && tag[1] <= '9' {
+		return i
+	}
+	body := i + len(tag)
+	if close := strings.Index(src[body:], tag); close >= 0 {
+		return body + close + len(tag) - 1
+	}
+	return len(src)
+}
+
+func onlyComments(d Dialect, stmt string) bool {
+	for stmt != "" {
+		stmt = strings.TrimSpace(stmt)
+		switch {
+		case strings.HasPrefix(stmt, "--"), d.HashComments() && strings.HasPrefix(stmt, "#"):
+			stmt = stmt[skipLine(stmt, 0):]
+		case strings.HasPrefix(stmt, "/*"):
+			end := strings.Index(stmt, "*/")
+			if end < 0 {
+				return true
+			}
+			stmt = stmt[end+2:]
+		default:
+			return stmt == ""
+		}
+	}
+	return true
+}
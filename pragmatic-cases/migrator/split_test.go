@@ -0,0 +1,48 @@
+package migrator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	cases := []struct {
+		name    string
+		dialect Dialect
+		src     string
+		want    []string
+	}{
+		{"one", Postgres, "CREATE TABLE t (id int);\n", []string{"CREATE TABLE t (id int)"}},
+		{"no trailing semicolon", Postgres, "SELECT 1; SELECT 2", []string{"SELECT 1", "SELECT 2"}},
+		{"strings", MySQL, `INSERT INTO t VALUES ('a;b', 'it''s;', "c;d", 'back\';slash');`, []string{`INSERT INTO t VALUES ('a;b', 'it''s;', "c;d", 'back\';slash')`}},
+		{"identifiers", MySQL, "CREATE TABLE `a;b` (id int); SELECT 1", []string{"CREATE TABLE `a;b` (id int)", "SELECT 1"}},
+		{"comments", MySQL, "-- drop; it\nDROP TABLE t; /* a; b */ SELECT 1; # c;\n", []string{"-- drop; it\nDROP TABLE t", "/* a; b */ SELECT 1"}},
+		{"only comments", Postgres, "-- nothing here;\n/* nor; here */", nil},
+		{"dollar quoted", Postgres, "CREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql; CREATE FUNCTION g() RETURNS int AS $body$ SELECT $1; $body$ LANGUAGE sql;",
+			[]string{"CREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql", "CREATE FUNCTION g() RETURNS int AS $body$ SELECT $1; $body$ LANGUAGE sql"}},
+		{"placeholders", Postgres, "SELECT $1; SELECT $2", []string{"SELECT $1", "SELECT $2"}},
+		{"Postgres backslash", Postgres, `'C:\'; SELECT 1`, []string{`'C:\'`, "SELECT 1"}},
+		{"Postgres escape string", Postgres, `SELECT E'it\'s;'; SELECT 1`, []string{`SELECT E'it\'s;'`, "SELECT 1"}},
+		{"Postgres hash operator", Postgres, "x #>> '{a}'; SELECT 1", []string{"x #>> '{a}'", "SELECT 1"}},
+		{"SQLite backslash", SQLite, `'C:\'; SELECT 1`, []string{`'C:\'`, "SELECT 1"}},
+		{"MySQL backslash", MySQL, `'C:\'; SELECT 1`, []string{`'C:\'; SELECT 1`}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := SplitStatements(c.dialect, c.src); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestParseStatements(t *testing.T) {
+	src := "-- header\n\nCREATE TABLE t (\n  id int\n);\n\n  DROP TABLE t;\n"
+	want := []Statement{
+		{SQL: "-- header\n\nCREATE TABLE t (\n  id int\n)", Line: 1},
+		{SQL: "DROP TABLE t", Line: 7},
+	}
+	if got := ParseStatements(Postgres, src); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,139 @@
+package migrator
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SumFile is the integrity file of an Atlas directory.
+const SumFile = "atlas.sum"
+
+var ErrChecksumMismatch = errors.New("migrator: checksum mismatch")
+
+// HashFile is the content of atlas.sum: the hash of the directory, then one
+// hash per file. A file hash covers the file and every file before it, so
+// editing an applied migration or inserting one before it changes the sums
+// of the files after it too.
+type HashFile struct {
+	Sum   string
+	Files []FileHash
+}
+
+type FileHash struct {
+	Name string
+	Hash string
+}
+
+// ComputeSum hashes the *.sql files of fsys the way Atlas does.
+func ComputeSum(fsys fs.FS) (HashFile, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return HashFile{}, err
+	}
+	sort.Strings(names)
+
+	var hf HashFile
+	h := sha256.New()
+	for _, name := range names {
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return HashFile{}, err
+		}
+		h.Write([]byte(name))
+		h.Write(b)
+		hf.Files = append(hf.Files, FileHash{Name: name, Hash: base64.StdEncoding.EncodeToString(h.Sum(nil))})
+	}
+
+	sum := sha256.New()
+	for _, f := range hf.Files {
+		sum.Write([]byte(f.Name))
+		sum.Write([]byte(f.Hash))
+	}
+	hf.Sum = base64.StdEncoding.EncodeToString(sum.Sum(nil))
+	return hf, nil
+}
+
+func (hf HashFile) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "h1:%s\n", hf.Sum)
+	for _, f := range hf.Files {
+		fmt.Fprintf(&buf, "%s h1:%s\n", f.Name, f.Hash)
+	}
+	return buf.Bytes(), nil
+}
+
+func (hf *HashFile) UnmarshalText(text []byte) error {
+	*hf = HashFile{}
+	s := bufio.NewScanner(bytes.NewReader(text))
+	if !s.Scan() {
+		return fmt.Errorf("migrator: empty %s", SumFile)
+	}
+	sum, ok := strings.CutPrefix(s.Text(), "h1:")
+	if !ok {
+		return fmt.Errorf("migrator: %s does not start with h1:", SumFile)
+	}
+	hf.Sum = sum
+	for s.Scan() {
+		name, hash, ok := strings.Cut(s.Text(), " h1:")
+		if !ok {
+			return fmt.Errorf("migrator: %s: invalid line %q", SumFile, s.Text())
+		}
+		hf.Files = append(hf.Files, FileHash{Name: name, Hash: hash})
+	}
+	return s.Err()
+}
+
+// VerifySum compares atlas.sum with the files of the directory, and names
+// the first file that differs.
+func (d *Dir) VerifySum() error {
+	data, err := fs.ReadFile(d.fsys, SumFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s is missing", ErrChecksumMismatch, SumFile)
+	}
+	if err != nil {
+		return err
+	}
+	var want HashFile
+	if err := want.UnmarshalText(data); err != nil {
+		return err
+	}
+	got, err := ComputeSum(d.fsys)
+	if err != nil {
+		return err
+	}
+	if got.Sum == want.Sum {
+		return nil
+	}
+	for i, f := range got.Files {
+		if i >= len(want.Files) {
+			return fmt.Errorf("%w: %s is not in %s", ErrChecksumMismatch, f.Name, SumFile)
+		}
+		if f != want.Files[i] {
+			return fmt.Errorf("%w: %s was edited, added or removed", ErrChecksumMismatch, f.Name)
+		}
+	}
+	if len(want.Files) > len(got.Files) {
+		return fmt.Errorf("%w: %s was removed", ErrChecksumMismatch, want.Files[len(got.Files)].Name)
+	}
+	return fmt.Errorf("%w: %s", ErrChecksumMismatch, SumFile)
+}
+
+// WriteSum rehashes the directory into atlas.sum, after a migration was
+// added on purpose.
+func (d *Dir) WriteSum() error {
+	hf, err := ComputeSum(d.fsys)
+	if err != nil {
+		return err
+	}
+	text, _ := hf.MarshalText()
+	return os.WriteFile(filepath.Join(d.Path, SumFile), text, 0o644)
+}
@@ -0,0 +1,78 @@
+package migrator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// the directory generated by ent and atlas in
+// ../ent/getting-started-with-versioned-migrations
+const entMigrations = "../ent/getting-started-with-versioned-migrations/ent/migrate/migrations"
+
+func TestComputeSumMatchesAtlas(t *testing.T) {
+	want, err := os.ReadFile(filepath.Join(entMigrations, SumFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hf, err := ComputeSum(os.DirFS(entMigrations))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := hf.MarshalText()
+	if string(got) != string(want) {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	var parsed HashFile
+	if err := parsed.UnmarshalText(want); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Sum != hf.Sum || len(parsed.Files) != len(hf.Files) {
+		t.Errorf("got %+v, want %+v", parsed, hf)
+	}
+}
+
+func TestVerifySum(t *testing.T) {
+	t.Run("untouched", func(t *testing.T) {
+		d := openCopy(t, "testdata/atlas")
+		if err := d.VerifySum(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("edited migration", func(t *testing.T) {
+		d := openCopy(t, "testdata/atlas")
+		writeFile(t, d.Path, "20240101000000_init.sql", "DROP TABLE users;")
+		assertChecksumMismatch(t, d.VerifySum(), "20240101000000_init.sql")
+	})
+
+	t.Run("added migration", func(t *testing.T) {
+		d := openCopy(t, "testdata/atlas")
+		writeFile(t, d.Path, "20240103000000_more.sql", "SELECT 1;")
+		assertChecksumMismatch(t, d.VerifySum(), "20240103000000_more.sql")
+	})
+
+	t.Run("removed migration", func(t *testing.T) {
+		d := openCopy(t, "testdata/atlas")
+		os.Remove(filepath.Join(d.Path, "20240102000000_add_cars.sql"))
+		assertChecksumMismatch(t, d.VerifySum(), "20240102000000_add_cars.sql")
+	})
+
+	t.Run("missing sum", func(t *testing.T) {
+		d := openCopy(t, "testdata/atlas")
+		os.Remove(filepath.Join(d.Path, SumFile))
+		assertChecksumMismatch(t, d.VerifySum(), SumFile)
+	})
+}
+
+func assertChecksumMismatch(t testing.TB, err error, file string) {
+	t.Helper()
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("got %v, want %v", err, ErrChecksumMismatch)
+	}
+	if !contains(err.Error(), file) {
+		t.Errorf("got %v, want it to name %s", err, file)
+	}
+}
@@ -0,0 +1,2 @@
+-- Create "users" table
+CREATE TABLE `users` (`id` integer NOT NULL, `name` text NOT NULL, PRIMARY KEY (`id`));
@@ -0,0 +1,2 @@
+-- Create "cars" table
+CREATE TABLE `cars` (`id` integer NOT NULL, `model` text NOT NULL, `user_cars` integer NULL, PRIMARY KEY (`id`));
@@ -0,0 +1,4 @@
+CREATE TABLE users (
+    id integer PRIMARY KEY,
+    name varchar(255) NOT NULL
+);
@@ -0,0 +1,3 @@
+-- emails are optional for existing users
+ALTER TABLE users ADD COLUMN email varchar(255);
+CREATE INDEX users_email ON users (email);
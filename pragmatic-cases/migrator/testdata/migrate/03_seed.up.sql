@@ -0,0 +1 @@
+INSERT INTO users (id, name, email) VALUES (1, 'o''brien; not a statement', 'ob@example.com');
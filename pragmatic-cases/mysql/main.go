@@ -6,10 +6,11 @@ import (
 	"fmt"
 	"log"
 	"time"
-
-	_ "github.com/go-sql-driver/mysql"
+	"tmp/pragmatic-cases/mysql/users"
 )
 
+const dsn = "root:password@tcp(localhost:3306)/"
+
 func main() {
 	ctx := context.Background()
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
@@ -33,51 +34,35 @@ func main() {
 		log.Fatal(err)
 	}
 	fmt.Printf("test_user exists: %t\n", res)
-}
-
-func Connect(ctx context.Context) (*sql.DB, error) {
-	data := make(chan *sql.DB, 1)
 
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			default:
-				db, err := sql.Open("mysql", "root:password@tcp(localhost:3306)/")
-				if err != nil {
-					time.Sleep(10 * time.Second)
-					continue
-				}
-				err = db.Ping()
-				if err != nil {
-					db.Close()
-					continue
-				}
-				data <- db
-				return
-			}
+	// the second Ensure finds nothing to do
+	spec := users.Spec{
+		Account:  users.Account{User: "app", Host: "%"},
+		Password: "app-password",
+		Grants: []users.Grant{
+			{Privilege: "SELECT", Database: "mysql", Table: "user"},
+			{Privilege: "PROCESS"},
+		},
+	}
+	for range 2 {
+		plan, err := users.New(db).Ensure(ctx, spec)
+		if err != nil {
+			log.Fatal(err)
 		}
-	}()
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case db := <-data:
-		return db, nil
+		fmt.Printf("plan for %s (empty: %t):\n%s\n", spec.Account, plan.Empty(), plan)
 	}
 }
 
+// Connect waits for the server to accept connections, backing off between
+// attempts.
+func Connect(ctx context.Context) (*sql.DB, error) {
+	return users.Connect(ctx, dsn)
+}
+
 func CheckMySQLHasUser(db *sql.DB, mysqluser string) (bool, error) {
-	row := db.QueryRow("SELECT COUNT(*) FROM mysql.user where User = '" + mysqluser + "'")
-	var count int
-	if err := row.Scan(&count); err != nil {
-		return false, err
-	} else {
-		return count > 0, nil
-	}
+	return users.New(db).HasUser(context.Background(), mysqluser)
 }
 
 func CreateMySQLUser(db *sql.DB, mysqluser string) error {
-	_, err := db.Exec("CREATE USER IF NOT EXISTS '" + mysqluser + "'")
-	return err
+	return users.New(db).Create(context.Background(), users.Account{User: mysqluser}, "")
 }
@@ -0,0 +1,72 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// Backoff is the wait between attempts: Initial, then multiplied by Factor up
+// to Max, each with up to Jitter of it added at random.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	Factor  float64
+	Jitter  float64
+}
+
+var DefaultBackoff = Backoff{Initial: 100 * time.Millisecond, Max: 5 * time.Second, Factor: 2, Jitter: 0.2}
+
+// Delay is the wait after the given failed attempt, counting from 0.
+func (b Backoff) Delay(attempt int) time.Duration {
+	d := float64(b.Initial)
+	for i := 0; i < attempt && d < float64(b.Max); i++ {
+		d *= b.Factor
+	}
+	d = min(d, float64(b.Max))
+	if b.Jitter > 0 {
+		d += d * b.Jitter * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// Pinger is satisfied by *sql.DB.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Wait pings db until it answers, waiting between attempts as b says, or
+// until ctx is done. The error then wraps the last ping failure.
+func Wait(ctx context.Context, db Pinger, b Backoff) error {
+	for attempt := 0; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		timer := time.NewTimer(b.Delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w after %d attempts: %w", ctx.Err(), attempt+1, err)
+		case <-timer.C:
+		}
+	}
+}
+
+// Connect opens dsn with the mysql driver and waits for the server with
+// DefaultBackoff, e.g. while its container starts.
+func Connect(ctx context.Context, dsn string) (*sql.DB, error) {
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := Wait(ctx, db, DefaultBackoff); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
@@ -0,0 +1,74 @@
+package users_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"tmp/pragmatic-cases/mysql/users"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	b := users.Backoff{Initial: 100 * time.Millisecond, Max: time.Second, Factor: 2}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for attempt, w := range want {
+		if got := b.Delay(attempt); got != w {
+			t.Errorf("attempt %d: got %v, want %v", attempt, got, w)
+		}
+	}
+
+	b.Jitter = 0.5
+	for attempt := range 10 {
+		if got := b.Delay(attempt); got < b.Initial || got > b.Max*3/2 {
+			t.Errorf("attempt %d: got %v, want between %v and %v", attempt, got, b.Initial, b.Max*3/2)
+		}
+	}
+}
+
+func TestWait(t *testing.T) {
+	backoff := users.Backoff{Initial: time.Millisecond, Max: time.Millisecond, Factor: 2}
+	refused := errors.New("connection refused")
+
+	t.Run("retries until the server answers", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		mock.ExpectPing().WillReturnError(refused)
+		mock.ExpectPing().WillReturnError(refused)
+		mock.ExpectPing()
+
+		if err := users.Wait(context.Background(), db, backoff); err != nil {
+			t.Fatal(err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("gives up with the last error when ctx is done", func(t *testing.T) {
+		pings := 0
+		pinger := pingFunc(func(ctx context.Context) error {
+			pings++
+			return refused
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := users.Wait(ctx, pinger, users.Backoff{Initial: 5 * time.Millisecond, Max: 5 * time.Millisecond, Factor: 1})
+		if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, refused) {
+			t.Errorf("got %v, want %v and %v", err, context.DeadlineExceeded, refused)
+		}
+		// no busy loop: about one ping per 5ms
+		if pings > 10 {
+			t.Errorf("got %d pings in 20ms, want a wait between them", pings)
+		}
+	})
+}
+
+type pingFunc func(ctx context.Context) error
+
+func (f pingFunc) PingContext(ctx context.Context) error { return f(ctx) }
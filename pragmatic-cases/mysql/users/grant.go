@@ -0,0 +1,113 @@
+package users
+
+import (
+	"fmt"
+	"strings"
+)
+
+// privileges are the static privileges GRANT accepts. Privileges are
+// keywords, not strings, so anything else is rejected instead of quoted.
+// USAGE and GRANT OPTION are left out: information_schema does not list them
+// as privileges, so they would never be seen as granted.
+var privileges = map[string]bool{
+	"ALL PRIVILEGES": true, "ALTER": true, "ALTER ROUTINE": true, "CREATE": true,
+	"CREATE ROLE": true, "CREATE ROUTINE": true, "CREATE TABLESPACE": true,
+	"CREATE TEMPORARY TABLES": true, "CREATE USER": true, "CREATE VIEW": true,
+	"DELETE": true, "DROP": true, "DROP ROLE": true, "EVENT": true, "EXECUTE": true,
+	"FILE": true, "INDEX": true, "INSERT": true,
+	"LOCK TABLES": true, "PROCESS": true, "REFERENCES": true, "RELOAD": true,
+	"REPLICATION CLIENT": true, "REPLICATION SLAVE": true, "SELECT": true,
+	"SHOW DATABASES": true, "SHOW VIEW": true, "SHUTDOWN": true, "SUPER": true,
+	"TRIGGER": true, "UPDATE": true,
+}
+
+// allOnTable and allOnDatabase are what ALL PRIVILEGES stands for on a table
+// and a database, the way information_schema lists them. On *.* it stands
+// for every static privilege.
+var (
+	allOnTable = []string{
+		"ALTER", "CREATE", "CREATE VIEW", "DELETE", "DROP", "INDEX", "INSERT",
+		"REFERENCES", "SELECT", "SHOW VIEW", "TRIGGER", "UPDATE",
+	}
+	allOnDatabase = append([]string{
+		"ALTER ROUTINE", "CREATE ROUTINE", "CREATE TEMPORARY TABLES", "EVENT",
+		"EXECUTE", "LOCK TABLES",
+	}, allOnTable...)
+)
+
+// Grant is one privilege on a database and table. "*" is every database or
+// table, and is what an empty Database or Table means.
+type Grant struct {
+	Privilege string
+	Database  string
+	Table     string
+}
+
+// Normalize upper-cases the privilege, spells ALL as ALL PRIVILEGES and fills
+// an empty Database or Table with "*".
+func (g Grant) Normalize() Grant {
+	g.Privilege = strings.Join(strings.Fields(strings.ToUpper(g.Privilege)), " ")
+	if g.Privilege == "ALL" {
+		g.Privilege = "ALL PRIVILEGES"
+	}
+	if g.Database == "" {
+		g.Database = "*"
+	}
+	if g.Table == "" {
+		g.Table = "*"
+	}
+	return g
+}
+
+func (g Grant) Validate() error {
+	g = g.Normalize()
+	if !privileges[g.Privilege] {
+		return fmt.Errorf("%w: privilege %q", ErrInvalidName, g.Privilege)
+	}
+	if g.Database == "*" && g.Table != "*" {
+		return fmt.Errorf("%w: table %q needs a database", ErrInvalidName, g.Table)
+	}
+	if err := validName("database", g.Database, 64); err != nil {
+		return err
+	}
+	return validName("table", g.Table, 64)
+}
+
+// Target is the ON clause of the grant: *.*, `db`.* or `db`.`table`.
+func (g Grant) Target() string {
+	g = g.Normalize()
+	return QuoteIdentifier(g.Database) + "." + QuoteIdentifier(g.Table)
+}
+
+func (g Grant) String() string {
+	g = g.Normalize()
+	return g.Privilege + " ON " + g.Target()
+}
+
+// Expand returns the grants ALL PRIVILEGES stands for on the target of g,
+// sorted, or g alone for any other privilege.
+func (g Grant) Expand() []Grant {
+	g = g.Normalize()
+	if g.Privilege != "ALL PRIVILEGES" {
+		return []Grant{g}
+	}
+	var names []string
+	switch {
+	case g.Table != "*":
+		names = allOnTable
+	case g.Database != "*":
+		names = allOnDatabase
+	default:
+		for name := range privileges {
+			if name != "ALL PRIVILEGES" {
+				names = append(names, name)
+			}
+		}
+	}
+	grants := make([]Grant, len(names))
+	for i, name := range names {
+		grants[i] = Grant{Privilege: name, Database: g.Database, Table: g.Table}
+	}
+	sortGrants(grants)
+	return grants
+}
@@ -0,0 +1,55 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidName = errors.New("users: invalid name")
+
+// QuoteString quotes s as a MySQL string literal. Quotes are doubled and
+// backslashes escaped, so the literal cannot be closed early whether or not
+// NO_BACKSLASH_ESCAPES is set. Its value can still differ: under
+// NO_BACKSLASH_ESCAPES an escaped backslash is two backslashes, which is why
+// Validate and ValidatePassword reject them.
+func QuoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// QuoteIdentifier quotes a database or table name with backticks; "*" is
+// left alone as the wildcard of GRANT.
+func QuoteIdentifier(s string) string {
+	if s == "*" {
+		return s
+	}
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+// validName rejects what MySQL would reject anyway, or silently truncate.
+func validName(kind, s string, max int) error {
+	switch {
+	case s == "":
+		return fmt.Errorf("%w: empty %s", ErrInvalidName, kind)
+	case !utf8.ValidString(s) || strings.ContainsRune(s, 0):
+		return fmt.Errorf("%w: %s %q", ErrInvalidName, kind, s)
+	case utf8.RuneCountInString(s) > max:
+		return fmt.Errorf("%w: %s %q is longer than %d characters", ErrInvalidName, kind, s, max)
+	}
+	return nil
+}
+
+// ValidatePassword rejects a password QuoteString cannot write with the same
+// value in every sql_mode.
+func ValidatePassword(password string) error {
+	return noBackslash("password", password)
+}
+
+func noBackslash(kind, s string) error {
+	if strings.ContainsRune(s, '\\') {
+		return fmt.Errorf("%w: %s contains a backslash, which NO_BACKSLASH_ESCAPES would change", ErrInvalidName, kind)
+	}
+	return nil
+}
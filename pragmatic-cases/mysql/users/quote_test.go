@@ -0,0 +1,87 @@
+package users
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestQuoteString(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"test_user", `'test_user'`},
+		{"o'brien", `'o''brien'`},
+		{`x' OR '1'='1`, `'x'' OR ''1''=''1'`},
+		{`back\`, `'back\\'`},
+		{`\'; DROP USER root; --`, `'\\''; DROP USER root; --'`},
+	}
+	for _, c := range cases {
+		if got := QuoteString(c.in); got != c.want {
+			t.Errorf("QuoteString(%q) = %s, want %s", c.in, got, c.want)
+		}
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"app", "`app`"},
+		{"*", "*"},
+		{"a`b", "`a``b`"},
+	}
+	for _, c := range cases {
+		if got := QuoteIdentifier(c.in); got != c.want {
+			t.Errorf("QuoteIdentifier(%q) = %s, want %s", c.in, got, c.want)
+		}
+	}
+}
+
+func TestAccountValidate(t *testing.T) {
+	valid := []Account{{User: "app"}, {User: "app", Host: "10.0.0.%"}, {User: "o'brien", Host: "localhost"}}
+	for _, a := range valid {
+		if err := a.Validate(); err != nil {
+			t.Errorf("%s: got %v, want no error", a, err)
+		}
+	}
+
+	invalid := []Account{{}, {User: strings.Repeat("a", 33)}, {User: "a\x00b"}, {User: "app", Host: strings.Repeat("h", 256)}, {User: `a\b`}, {User: "app", Host: `10.0.0.\%`}}
+	for _, a := range invalid {
+		if err := a.Validate(); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("%q: got %v, want %v", a, err, ErrInvalidName)
+		}
+	}
+}
+
+func TestGrantValidate(t *testing.T) {
+	valid := []Grant{{Privilege: "select", Database: "app"}, {Privilege: "all"}, {Privilege: "show  view", Database: "app", Table: "t"}}
+	for _, g := range valid {
+		if err := g.Validate(); err != nil {
+			t.Errorf("%s: got %v, want no error", g, err)
+		}
+	}
+
+	invalid := []Grant{
+		{Privilege: "SELECT ON *.* TO 'x'@'%'; --"},
+		{Privilege: "USAGE"},
+		{Privilege: "SELECT", Table: "t"},
+	}
+	for _, g := range invalid {
+		if err := g.Validate(); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("%q: got %v, want %v", g, err, ErrInvalidName)
+		}
+	}
+}
+
+func TestGrantTarget(t *testing.T) {
+	cases := []struct {
+		grant Grant
+		want  string
+	}{
+		{Grant{Privilege: "PROCESS"}, "*.*"},
+		{Grant{Privilege: "SELECT", Database: "app"}, "`app`.*"},
+		{Grant{Privilege: "SELECT", Database: "app", Table: "users"}, "`app`.`users`"},
+	}
+	for _, c := range cases {
+		if got := c.grant.Target(); got != c.want {
+			t.Errorf("got %s, want %s", got, c.want)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+// Package users manages MySQL accounts and their grants without building SQL
+// from unquoted input.
+package users
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	maxUserLength = 32
+	maxHostLength = 255
+)
+
+// Account is a MySQL account, 'user'@'host'. An empty Host is "%", any host.
+type Account struct {
+	User string
+	Host string
+}
+
+func (a Account) host() string {
+	if a.Host == "" {
+		return "%"
+	}
+	return a.Host
+}
+
+// String is the account as it is written in SQL statements.
+func (a Account) String() string {
+	return QuoteString(a.User) + "@" + QuoteString(a.host())
+}
+
+// grantee is the account as information_schema writes it in GRANTEE.
+func (a Account) grantee() string {
+	return "'" + a.User + "'@'" + a.host() + "'"
+}
+
+func (a Account) Validate() error {
+	if err := validName("user", a.User, maxUserLength); err != nil {
+		return err
+	}
+	if err := noBackslash("user", a.User); err != nil {
+		return err
+	}
+	if a.Host == "" {
+		return nil
+	}
+	if err := validName("host", a.Host, maxHostLength); err != nil {
+		return err
+	}
+	return noBackslash("host", a.Host)
+}
+
+// Manager creates and drops accounts and grants privileges to them.
+type Manager struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *Manager {
+	return &Manager{db: db}
+}
+
+// HasUser reports whether an account named user exists, from any host.
+func (m *Manager) HasUser(ctx context.Context, user string) (bool, error) {
+	var count int
+	err := m.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM mysql.user where User = ?", user).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (m *Manager) Exists(ctx context.Context, a Account) (bool, error) {
+	var count int
+	err := m.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM mysql.user where User = ? AND Host = ?", a.User, a.host()).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Create creates the account if it does not exist. An empty password creates
+// it without one. The password of an existing account is left as it is.
+func (m *Manager) Create(ctx context.Context, a Account, password string) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+	if err := ValidatePassword(password); err != nil {
+		return err
+	}
+	_, err := m.db.ExecContext(ctx, createStatement(a, password))
+	return err
+}
+
+// Drop drops the account if it exists.
+func (m *Manager) Drop(ctx context.Context, a Account) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+	_, err := m.db.ExecContext(ctx, "DROP USER IF EXISTS "+a.String())
+	return err
+}
+
+func createStatement(a Account, password string) string {
+	stmt := "CREATE USER IF NOT EXISTS " + a.String()
+	if password != "" {
+		stmt += " IDENTIFIED BY " + QuoteString(password)
+	}
+	return stmt
+}
+
+// Grants returns the privileges of the account, read from information_schema
+// so they need no parsing of SHOW GRANTS. USAGE, which only means the
+// account exists, is left out.
+func (m *Manager) Grants(ctx context.Context, a Account) ([]Grant, error) {
+	grantee := a.grantee()
+	rows, err := m.db.QueryContext(ctx, `SELECT PRIVILEGE_TYPE, '*', '*' FROM information_schema.USER_PRIVILEGES WHERE GRANTEE = ?
+UNION ALL SELECT PRIVILEGE_TYPE, TABLE_SCHEMA, '*' FROM information_schema.SCHEMA_PRIVILEGES WHERE GRANTEE = ?
+UNION ALL SELECT PRIVILEGE_TYPE, TABLE_SCHEMA, TABLE_NAME FROM information_schema.TABLE_PRIVILEGES WHERE GRANTEE = ?`,
+		grantee, grantee, grantee)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var grants []Grant
+	for rows.Next() {
+		var g Grant
+		if err := rows.Scan(&g.Privilege, &g.Database, &g.Table); err != nil {
+			return nil, err
+		}
+		if g = g.Normalize(); g.Privilege != "USAGE" {
+			grants = append(grants, g)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sortGrants(grants)
+	return grants, nil
+}
+
+// Spec is the desired state of an account.
+type Spec struct {
+	Account  Account
+	Password string // only used when the account is created
+	Grants   []Grant
+}
+
+// Plan compares spec with the server and returns what Apply would do.
+func (m *Manager) Plan(ctx context.Context, spec Spec) (*Plan, error) {
+	if err := spec.Account.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ValidatePassword(spec.Password); err != nil {
+		return nil, err
+	}
+	desired := make([]Grant, 0, len(spec.Grants))
+	for _, g := range spec.Grants {
+		g = g.Normalize()
+		if err := g.Validate(); err != nil {
+			return nil, err
+		}
+		desired = append(desired, g)
+	}
+
+	exists, err := m.Exists(ctx, spec.Account)
+	if err != nil {
+		return nil, fmt.Errorf("check account %s: %w", spec.Account, err)
+	}
+	var actual []Grant
+	if exists {
+		if actual, err = m.Grants(ctx, spec.Account); err != nil {
+			return nil, fmt.Errorf("read grants of %s: %w", spec.Account, err)
+		}
+	}
+
+	plan := &Plan{Account: spec.Account, CreateUser: !exists, password: spec.Password}
+	plan.Grant, plan.Revoke = diffGrants(desired, actual)
+	return plan, nil
+}
+
+// Apply runs the statements of the plan in order, stopping at the first
+// failure.
+func (m *Manager) Apply(ctx context.Context, plan *Plan) error {
+	for _, stmt := range plan.statements(false) {
+		if _, err := m.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("%s: %w", redact(stmt, plan.password), err)
+		}
+	}
+	return nil
+}
+
+// Ensure brings the account to spec: Plan then Apply. Running it again is a
+// no-op.
+func (m *Manager) Ensure(ctx context.Context, spec Spec) (*Plan, error) {
+	plan, err := m.Plan(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	return plan, m.Apply(ctx, plan)
+}
+
+// Plan is what it takes to bring an account to its Spec.
+type Plan struct {
+	Account    Account
+	CreateUser bool
+	Grant      []Grant
+	Revoke     []Grant
+
+	password string
+}
+
+func (p *Plan) Empty() bool {
+	return !p.CreateUser && len(p.Grant) == 0 && len(p.Revoke) == 0
+}
+
+// String is the statements of the plan, one per line, with the password
+// redacted.
+func (p *Plan) String() string {
+	return strings.Join(p.statements(true), "\n")
+}
+
+func (p *Plan) statements(redacted bool) []string {
+	var stmts []string
+	if p.CreateUser {
+		stmt := createStatement(p.Account, p.password)
+		if redacted {
+			stmt = redact(stmt, p.password)
+		}
+		stmts = append(stmts, stmt)
+	}
+	for _, g := range groupByTarget(p.Revoke) {
+		stmts = append(stmts, "REVOKE "+strings.Join(g.privileges, ", ")+" ON "+g.target+" FROM "+p.Account.String())
+	}
+	for _, g := range groupByTarget(p.Grant) {
+		stmts = append(stmts, "GRANT "+strings.Join(g.privileges, ", ")+" ON "+g.target+" TO "+p.Account.String())
+	}
+	return stmts
+}
+
+func redact(stmt, password string) string {
+	if password == "" {
+		return stmt
+	}
+	return strings.ReplaceAll(stmt, QuoteString(password), "'<redacted>'")
+}
+
+type targetGrants struct {
+	target     string
+	privileges []string
+}
+
+// groupByTarget turns grants into one statement per database and table, in
+// the order of the sorted grants.
+func groupByTarget(grants []Grant) []targetGrants {
+	var groups []targetGrants
+	index := map[string]int{}
+	for _, g := range grants {
+		target := g.Target()
+		i, ok := index[target]
+		if !ok {
+			i = len(groups)
+			index[target] = i
+			groups = append(groups, targetGrants{target: target})
+		}
+		groups[i].privileges = append(groups[i].privileges, g.Privilege)
+	}
+	return groups
+}
+
+// diffGrants returns the desired grants missing from actual, and the actual
+// ones not desired. information_schema never lists ALL PRIVILEGES, only the
+// privileges it stands for: it is granted unless every one of them is held,
+// and nothing held on its target is revoked.
+func diffGrants(desired, actual []Grant) (grant, revoke []Grant) {
+	type target struct{ database, table string }
+	all := map[target]bool{}
+	for _, g := range desired {
+		if g.Privilege == "ALL PRIVILEGES" {
+			all[target{g.Database, g.Table}] = true
+		}
+	}
+
+	has := func(grants []Grant, g Grant) bool {
+		for _, other := range grants {
+			if other == g {
+				return true
+			}
+		}
+		return false
+	}
+	for _, g := range desired {
+		if all[target{g.Database, g.Table}] && g.Privilege != "ALL PRIVILEGES" {
+			continue
+		}
+		held := true
+		for _, e := range g.Expand() {
+			held = held && has(actual, e)
+		}
+		if !held && !has(grant, g) {
+			grant = append(grant, g)
+		}
+	}
+	for _, g := range actual {
+		if !all[target{g.Database, g.Table}] && !has(desired, g) {
+			revoke = append(revoke, g)
+		}
+	}
+	sortGrants(grant)
+	sortGrants(revoke)
+	return grant, revoke
+}
+
+func sortGrants(grants []Grant) {
+	sort.Slice(grants, func(i, j int) bool {
+		a, b := grants[i], grants[j]
+		if a.Database != b.Database {
+			return a.Database < b.Database
+		}
+		if a.Table != b.Table {
+			return a.Table < b.Table
+		}
+		return a.Privilege < b.Privilege
+	})
+}
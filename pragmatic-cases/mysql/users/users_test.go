@@ -0,0 +1,261 @@
+package users_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"tmp/pragmatic-cases/mysql/users"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var app = users.Account{User: "app", Host: "%"}
+
+const grantsQuery = "SELECT PRIVILEGE_TYPE, '*', '*' FROM information_schema.USER_PRIVILEGES"
+
+func TestCreate(t *testing.T) {
+	t.Run("quotes account and password", func(t *testing.T) {
+		m, mock := newMock(t)
+		mock.ExpectExec(regexp.QuoteMeta(`CREATE USER IF NOT EXISTS 'o''brien'@'%' IDENTIFIED BY 'pa''ss'`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		if err := m.Create(context.Background(), users.Account{User: "o'brien"}, `pa'ss`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("rejects a password with a backslash, whose meaning depends on sql_mode", func(t *testing.T) {
+		m, _ := newMock(t)
+		err := m.Create(context.Background(), app, `pa\ss`)
+		if !errors.Is(err, users.ErrInvalidName) || strings.Contains(err.Error(), `pa\ss`) {
+			t.Errorf("got %v, want %v without the password", err, users.ErrInvalidName)
+		}
+	})
+
+	t.Run("rejects an invalid account without running anything", func(t *testing.T) {
+		m, _ := newMock(t)
+		err := m.Create(context.Background(), users.Account{}, "")
+		if !errors.Is(err, users.ErrInvalidName) {
+			t.Errorf("got %v, want %v", err, users.ErrInvalidName)
+		}
+	})
+}
+
+func TestDrop(t *testing.T) {
+	m, mock := newMock(t)
+	mock.ExpectExec(regexp.QuoteMeta(`DROP USER IF EXISTS 'app'@'localhost'`)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := m.Drop(context.Background(), users.Account{User: "app", Host: "localhost"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHasUser(t *testing.T) {
+	m, mock := newMock(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM mysql.user where User = ?")).
+		WithArgs("x' OR '1'='1").
+		WillReturnRows(sqlmock.NewRows([]string{"cnt"}).AddRow(0))
+
+	has, err := m.HasUser(context.Background(), "x' OR '1'='1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if has {
+		t.Error("want false but got true")
+	}
+}
+
+func TestGrants(t *testing.T) {
+	m, mock := newMock(t)
+	expectGrants(mock, [][3]string{
+		{"USAGE", "*", "*"},
+		{"SELECT", "app", "*"},
+		{"PROCESS", "*", "*"},
+		{"UPDATE", "app", "users"},
+	})
+
+	got, err := m.Grants(context.Background(), app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []users.Grant{
+		{Privilege: "PROCESS", Database: "*", Table: "*"},
+		{Privilege: "SELECT", Database: "app", Table: "*"},
+		{Privilege: "UPDATE", Database: "app", Table: "users"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	ctx := context.Background()
+	spec := users.Spec{
+		Account:  app,
+		Password: "secret",
+		Grants: []users.Grant{
+			{Privilege: "select", Database: "app"},
+			{Privilege: "INSERT", Database: "app"},
+			{Privilege: "PROCESS"},
+		},
+	}
+
+	t.Run("new account is created and granted everything", func(t *testing.T) {
+		m, mock := newMock(t)
+		expectExists(mock, false)
+
+		plan, err := m.Plan(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertStatements(t, plan, []string{
+			`CREATE USER IF NOT EXISTS 'app'@'%' IDENTIFIED BY '<redacted>'`,
+			"GRANT PROCESS ON *.* TO 'app'@'%'",
+			"GRANT INSERT, SELECT ON `app`.* TO 'app'@'%'",
+		})
+	})
+
+	t.Run("existing account gets only the difference", func(t *testing.T) {
+		m, mock := newMock(t)
+		expectExists(mock, true)
+		expectGrants(mock, [][3]string{
+			{"USAGE", "*", "*"},
+			{"SELECT", "app", "*"},
+			{"DELETE", "app", "*"},
+		})
+
+		plan, err := m.Plan(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertStatements(t, plan, []string{
+			"REVOKE DELETE ON `app`.* FROM 'app'@'%'",
+			"GRANT PROCESS ON *.* TO 'app'@'%'",
+			"GRANT INSERT ON `app`.* TO 'app'@'%'",
+		})
+	})
+
+	t.Run("account in its desired state has an empty plan", func(t *testing.T) {
+		m, mock := newMock(t)
+		expectExists(mock, true)
+		expectGrants(mock, [][3]string{{"PROCESS", "*", "*"}, {"SELECT", "app", "*"}, {"INSERT", "app", "*"}})
+
+		plan, err := m.Plan(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !plan.Empty() {
+			t.Errorf("want an empty plan, got\n%s", plan)
+		}
+	})
+
+	t.Run("ALL PRIVILEGES is granted over some of its privileges", func(t *testing.T) {
+		m, mock := newMock(t)
+		expectExists(mock, true)
+		expectGrants(mock, [][3]string{{"SELECT", "app", "*"}})
+
+		plan, err := m.Plan(ctx, users.Spec{Account: app, Grants: []users.Grant{{Privilege: "ALL", Database: "app"}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertStatements(t, plan, []string{"GRANT ALL PRIVILEGES ON `app`.* TO 'app'@'%'"})
+	})
+
+	t.Run("ALL PRIVILEGES is held when all its privileges are", func(t *testing.T) {
+		m, mock := newMock(t)
+		expectExists(mock, true)
+		var held [][3]string
+		for _, g := range (users.Grant{Privilege: "ALL", Database: "app", Table: "events"}).Expand() {
+			held = append(held, [3]string{g.Privilege, g.Database, g.Table})
+		}
+		expectGrants(mock, held)
+
+		plan, err := m.Plan(ctx, users.Spec{Account: app, Grants: []users.Grant{{Privilege: "ALL", Database: "app", Table: "events"}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !plan.Empty() {
+			t.Errorf("want an empty plan, got\n%s", plan)
+		}
+	})
+
+	t.Run("invalid grant", func(t *testing.T) {
+		m, _ := newMock(t)
+		_, err := m.Plan(ctx, users.Spec{Account: app, Grants: []users.Grant{{Privilege: "SELECT ON *.* TO root; --"}}})
+		if !errors.Is(err, users.ErrInvalidName) {
+			t.Errorf("got %v, want %v", err, users.ErrInvalidName)
+		}
+	})
+}
+
+func TestEnsure(t *testing.T) {
+	t.Run("runs the plan with the password", func(t *testing.T) {
+		m, mock := newMock(t)
+		expectExists(mock, false)
+		mock.ExpectExec(regexp.QuoteMeta(`CREATE USER IF NOT EXISTS 'app'@'%' IDENTIFIED BY 'secret'`)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("GRANT SELECT ON `app`.* TO 'app'@'%'")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := m.Ensure(context.Background(), users.Spec{Account: app, Password: "secret", Grants: []users.Grant{{Privilege: "SELECT", Database: "app"}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("failures do not leak the password", func(t *testing.T) {
+		m, mock := newMock(t)
+		expectExists(mock, false)
+		mock.ExpectExec("CREATE USER").WillReturnError(errors.New("access denied"))
+
+		_, err := m.Ensure(context.Background(), users.Spec{Account: app, Password: "secret"})
+		if err == nil || strings.Contains(err.Error(), "secret") {
+			t.Errorf("got %v, want an error without the password", err)
+		}
+	})
+}
+
+func newMock(t *testing.T) (*users.Manager, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+	return users.New(db), mock
+}
+
+func expectExists(mock sqlmock.Sqlmock, exists bool) {
+	count := 0
+	if exists {
+		count = 1
+	}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM mysql.user where User = ? AND Host = ?")).
+		WithArgs(app.User, "%").
+		WillReturnRows(sqlmock.NewRows([]string{"cnt"}).AddRow(count))
+}
+
+func expectGrants(mock sqlmock.Sqlmock, grants [][3]string) {
+	rows := sqlmock.NewRows([]string{"PRIVILEGE_TYPE", "TABLE_SCHEMA", "TABLE_NAME"})
+	for _, g := range grants {
+		rows.AddRow(g[0], g[1], g[2])
+	}
+	grantee := "'app'@'%'"
+	mock.ExpectQuery("^"+regexp.QuoteMeta(grantsQuery)).
+		WithArgs(grantee, grantee, grantee).
+		WillReturnRows(rows)
+}
+
+func assertStatements(t testing.TB, plan *users.Plan, want []string) {
+	t.Helper()
+	got := strings.Split(plan.String(), "\n")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
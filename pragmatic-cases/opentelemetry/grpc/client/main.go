@@ -22,6 +22,7 @@ package main
 import (
 	"context"
 	"flag"
+	"io"
 	"log"
 	"time"
 
@@ -44,8 +45,10 @@ const (
 )
 
 var (
-	addr = flag.String("addr", "localhost:50051", "the address to connect to")
-	name = flag.String("name", defaultName, "Name to greet")
+	addr   = flag.String("addr", "localhost:50051", "the address to connect to")
+	name   = flag.String("name", defaultName, "Name to greet")
+	lang   = flag.String("language", "", "BCP 47 language of the greeting, e.g. fr")
+	stream = flag.Bool("stream", false, "Greet in every language with SayHelloStream")
 )
 
 func NewJaegerTracerProvider(service, environment, url string) (*tracesdk.TracerProvider, error) {
@@ -107,9 +110,30 @@ func main() {
 	// Contact the server and print out its response.
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
-	r, err := c.SayHello(ctx, &pb.HelloRequest{Name: *name})
+	if *stream {
+		greetAll(ctx, c)
+		return
+	}
+	r, err := c.SayHello(ctx, &pb.HelloRequest{Name: *name, Language: *lang})
 	if err != nil {
 		log.Fatalf("could not greet: %v", err)
 	}
 	log.Printf("Greeting: %s", r.GetMessage())
 }
+
+func greetAll(ctx context.Context, c pb.GreeterClient) {
+	stream, err := c.SayHelloStream(ctx, &pb.HelloRequest{Name: *name})
+	if err != nil {
+		log.Fatalf("could not greet: %v", err)
+	}
+	for {
+		r, err := stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			log.Fatalf("could not greet: %v", err)
+		}
+		log.Printf("Greeting (%s): %s", r.GetLanguage(), r.GetMessage())
+	}
+}
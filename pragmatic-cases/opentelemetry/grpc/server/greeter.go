@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"tmp/learn-go-with-tests/01-go-fundamentals/08-dependency-injection/greeter"
+	pb "tmp/pragmatic-cases/opentelemetry/grpc/helloworld"
+
+	"golang.org/x/text/language"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// server is used to implement helloworld.GreeterServer. It only translates
+// between protobuf and the greeter domain service.
+type server struct {
+	pb.UnimplementedGreeterServer
+	greeter greeter.Greeter
+}
+
+func NewServer(g greeter.Greeter) pb.GreeterServer {
+	return &server{greeter: g}
+}
+
+// SayHello implements helloworld.GreeterServer
+func (s *server) SayHello(ctx context.Context, in *pb.HelloRequest) (*pb.HelloReply, error) {
+	var preferred []language.Tag
+	if tag, err := language.Parse(in.GetLanguage()); err == nil {
+		preferred = append(preferred, tag)
+	}
+	g, err := s.greeter.Greet(ctx, in.GetName(), preferred...)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return reply(g), nil
+}
+
+// SayHelloStream implements helloworld.GreeterServer
+func (s *server) SayHelloStream(in *pb.HelloRequest, stream pb.Greeter_SayHelloStreamServer) error {
+	err := s.greeter.GreetAll(stream.Context(), in.GetName(), func(g greeter.Greeting) error {
+		return stream.Send(reply(g))
+	})
+	return toStatus(err)
+}
+
+func reply(g greeter.Greeting) *pb.HelloReply {
+	return &pb.HelloReply{Message: g.Message, Language: g.Language.String()}
+}
+
+func toStatus(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, greeter.ErrInvalidName):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	}
+	if _, ok := status.FromError(err); ok {
+		// already a status, e.g. from stream.Send
+		return err
+	}
+	return status.Error(codes.Internal, err.Error())
+}
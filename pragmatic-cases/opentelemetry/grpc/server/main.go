@@ -26,6 +26,7 @@ import (
 	"log"
 	"net"
 
+	"tmp/learn-go-with-tests/01-go-fundamentals/08-dependency-injection/greeter"
 	pb "tmp/pragmatic-cases/opentelemetry/grpc/helloworld"
 
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
@@ -36,7 +37,6 @@ import (
 	"go.opentelemetry.io/otel/sdk/resource"
 	tracesdk "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
-	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 )
 
@@ -50,20 +50,6 @@ var (
 	port = flag.Int("port", 50051, "The server port")
 )
 
-// server is used to implement helloworld.GreeterServer.
-type server struct {
-	pb.UnimplementedGreeterServer
-}
-
-// SayHello implements helloworld.GreeterServer
-func (s *server) SayHello(ctx context.Context, in *pb.HelloRequest) (*pb.HelloReply, error) {
-	_, span := otel.Tracer(tracerName).Start(ctx, "SayHello",
-		trace.WithAttributes(attribute.String("extra.key", "extra.value")))
-	log.Printf("Received: %v", in.GetName())
-	defer span.End()
-	return &pb.HelloReply{Message: "Hello " + in.GetName()}, nil
-}
-
 func NewJaegerTracerProvider(service, environment, url string) (*tracesdk.TracerProvider, error) {
 	// Create the Jaeger exporter
 	exp, err := jaeger.New(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint(url)))
@@ -111,7 +97,11 @@ func main() {
 		grpc.UnaryInterceptor(otelgrpc.UnaryServerInterceptor()),
 		grpc.StreamInterceptor(otelgrpc.StreamServerInterceptor()),
 	)
-	pb.RegisterGreeterServer(s, &server{})
+	g := greeter.Chain(greeter.Default(),
+		greeter.Logging(log.Default()),
+		greeter.Tracing(otel.Tracer(tracerName)),
+	)
+	pb.RegisterGreeterServer(s, NewServer(g))
 	log.Printf("server listening at %v", lis.Addr())
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net"
+	"testing"
+
+	"tmp/learn-go-with-tests/01-go-fundamentals/08-dependency-injection/greeter"
+	pb "tmp/pragmatic-cases/opentelemetry/grpc/helloworld"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/propagation"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type testEnv struct {
+	client pb.GreeterClient
+	spans  *tracetest.SpanRecorder
+	logs   *bytes.Buffer
+}
+
+// newTestEnv serves the Greeter over an in-memory connection, traced from
+// the client to the domain service like in main.
+func newTestEnv(t testing.TB) testEnv {
+	t.Helper()
+	spans := tracetest.NewSpanRecorder()
+	tp := tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(spans))
+	propagators := propagation.TraceContext{}
+	var logs bytes.Buffer
+
+	g := greeter.Chain(greeter.Default(),
+		greeter.Logging(log.New(&logs, "", 0)),
+		greeter.Tracing(tp.Tracer(tracerName)),
+	)
+	s := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler(
+		otelgrpc.WithTracerProvider(tp), otelgrpc.WithPropagators(propagators))))
+	pb.RegisterGreeterServer(s, NewServer(g))
+
+	lis := bufconn.Listen(1 << 20)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+			otelgrpc.WithTracerProvider(tp), otelgrpc.WithPropagators(propagators))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return testEnv{client: pb.NewGreeterClient(conn), spans: spans, logs: &logs}
+}
+
+func TestSayHello(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	cases := []struct {
+		req                   *pb.HelloRequest
+		wantMessage, wantLang string
+	}{
+		{&pb.HelloRequest{}, "Hello, World!", "en"},
+		{&pb.HelloRequest{Name: "Chris", Language: "pt-BR"}, "Oi, Chris!", "pt-BR"},
+		{&pb.HelloRequest{Name: "Chris", Language: "fr-CA"}, "Bonjour, Chris !", "fr"},
+		{&pb.HelloRequest{Name: "Chris", Language: "not a tag"}, "Hello, Chris!", "en"},
+	}
+
+	for _, c := range cases {
+		got, err := env.client.SayHello(ctx, c.req)
+
+		assertNoError(t, err)
+		if got.GetMessage() != c.wantMessage || got.GetLanguage() != c.wantLang {
+			t.Errorf("SayHello(%v) = %q in %q, want %q in %q", c.req, got.GetMessage(), got.GetLanguage(), c.wantMessage, c.wantLang)
+		}
+	}
+
+	t.Run("invalid names are invalid arguments", func(t *testing.T) {
+		_, err := env.client.SayHello(ctx, &pb.HelloRequest{Name: "bell\a"})
+
+		assertCode(t, err, codes.InvalidArgument)
+	})
+
+	t.Run("the domain span is a child of the RPC spans", func(t *testing.T) {
+		env.spans.Reset()
+
+		_, err := env.client.SayHello(ctx, &pb.HelloRequest{Name: "Chris"})
+		assertNoError(t, err)
+
+		var client, server, domain tracesdk.ReadOnlySpan
+		for _, span := range env.spans.Ended() {
+			switch {
+			case span.Name() == "Greeter.Greet":
+				domain = span
+			case span.SpanKind() == trace.SpanKindClient:
+				client = span
+			case span.SpanKind() == trace.SpanKindServer:
+				server = span
+			}
+		}
+		if client == nil || server == nil || domain == nil {
+			t.Fatalf("missing spans, got %v", env.spans.Ended())
+		}
+		if domain.SpanContext().TraceID() != client.SpanContext().TraceID() {
+			t.Error("the domain span is not in the trace of the client")
+		}
+		if domain.Parent().SpanID() != server.SpanContext().SpanID() {
+			t.Error("the domain span is not a child of the server span")
+		}
+	})
+}
+
+func TestSayHelloStream(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+
+	t.Run("streams every language", func(t *testing.T) {
+		stream, err := env.client.SayHelloStream(ctx, &pb.HelloRequest{Name: "Chris"})
+		assertNoError(t, err)
+
+		var got []string
+		for {
+			reply, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			assertNoError(t, err)
+			got = append(got, reply.GetLanguage()+" "+reply.GetMessage())
+		}
+
+		want := []string{"en Hello, Chris!", "de Hallo, Chris!", "es Hola, Chris!", "fr Bonjour, Chris !", "pt Olá, Chris!", "pt-BR Oi, Chris!"}
+		if len(got) != len(want) {
+			t.Fatalf("got %q want %q", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("got %q want %q", got[i], want[i])
+			}
+		}
+	})
+
+	t.Run("invalid names are invalid arguments", func(t *testing.T) {
+		stream, err := env.client.SayHelloStream(ctx, &pb.HelloRequest{Name: "bell\a"})
+		assertNoError(t, err)
+
+		_, err = stream.Recv()
+
+		assertCode(t, err, codes.InvalidArgument)
+	})
+
+	t.Run("both transports share the logging middleware", func(t *testing.T) {
+		if !bytes.Contains(env.logs.Bytes(), []byte(`GreetAll name="Chris" sent=6`)) {
+			t.Errorf("the stream was not logged:\n%s", env.logs)
+		}
+	})
+}
+
+func assertCode(t testing.TB, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Errorf("got code %v (%v), want %v", got, err, want)
+	}
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+	"tmp/pragmatic-cases/rolesync"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+)
+
+var (
+	configPath string
+	dsn        string
+	dryRun     bool
+)
+
+func init() {
+	flag.StringVar(&configPath, "config", "roles.hcl", "HCL file of the desired roles and users")
+	flag.StringVar(&dsn, "dsn", os.Getenv("DATABASE_DSN"), "data source name of the server, $DATABASE_DSN by default")
+	flag.BoolVar(&dryRun, "dry-run", false, "set for dryrun")
+}
+
+func main() {
+	flag.Parse()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	config, err := rolesync.LoadConfig(configPath, environ())
+	if err != nil {
+		log.Fatalf("failed to load %s: %s", configPath, err)
+	}
+	dialect, err := rolesync.DialectFor(config.Driver)
+	if err != nil {
+		log.Fatal(err)
+	}
+	db, err := sql.Open(config.Driver, dsn)
+	if err != nil {
+		log.Fatalf("failed opening db: %s", err)
+	}
+	defer db.Close()
+
+	r := &rolesync.Reconciler{DB: db, Dialect: dialect}
+	plan, err := r.Plan(ctx, config.Desired())
+	if err != nil {
+		log.Fatalf("failed to plan: %s", err)
+	}
+	if plan.Empty() {
+		fmt.Println("no changes")
+		return
+	}
+	fmt.Println(plan)
+	if dryRun { // only plan
+		return
+	}
+	if err := r.Apply(ctx, plan); err != nil {
+		log.Fatalf("failed to apply: %s", err)
+	}
+	fmt.Printf("applied %d changes\n", len(plan.Changes))
+}
+
+func environ() map[string]string {
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	return env
+}
@@ -0,0 +1,137 @@
+package rolesync
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsimple"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Config is the desired roles and users of a database server, decoded from
+// HCL the way pragmatic-cases/hcl decodes its Config:
+//
+//	driver = "postgres"
+//
+//	role "readonly" {
+//	  privilege "table public.users" {
+//	    privileges = ["SELECT"]
+//	  }
+//	}
+//
+//	user "my_user" {
+//	  password = env.MY_USER_PASSWORD
+//	  roles    = ["readonly"]
+//	}
+type Config struct {
+	Driver string       `hcl:"driver"`
+	Roles  []RoleConfig `hcl:"role,block"`
+	Users  []UserConfig `hcl:"user,block"`
+}
+
+type RoleConfig struct {
+	Name       string            `hcl:"name,label"`
+	Absent     bool              `hcl:"absent,optional"`
+	Roles      []string          `hcl:"roles,optional"`
+	Privileges []PrivilegeConfig `hcl:"privilege,block"`
+}
+
+type UserConfig struct {
+	Name       string            `hcl:"name,label"`
+	Host       string            `hcl:"host,optional"` // MySQL only, "%" by default
+	Password   string            `hcl:"password,optional"`
+	Absent     bool              `hcl:"absent,optional"`
+	Roles      []string          `hcl:"roles,optional"`
+	Privileges []PrivilegeConfig `hcl:"privilege,block"`
+}
+
+// PrivilegeConfig grants privileges on one target, whose syntax is the
+// dialect's: "app.*" for MySQL, "table public.users" for Postgres.
+type PrivilegeConfig struct {
+	On         string   `hcl:"on,label"`
+	Privileges []string `hcl:"privileges"`
+}
+
+// LoadConfig decodes the HCL file at path. env is available to it as the env
+// variable, so passwords need not be written in the file.
+func LoadConfig(path string, env map[string]string) (*Config, error) {
+	var config Config
+	if err := hclsimple.DecodeFile(path, evalContext(env), &config); err != nil {
+		return nil, err
+	}
+	return &config, config.validate()
+}
+
+// DecodeConfig is LoadConfig for HCL already read; filename is only used in
+// error messages.
+func DecodeConfig(filename string, src []byte, env map[string]string) (*Config, error) {
+	var config Config
+	if err := hclsimple.Decode(filename, src, evalContext(env), &config); err != nil {
+		return nil, err
+	}
+	return &config, config.validate()
+}
+
+func evalContext(env map[string]string) *hcl.EvalContext {
+	vars := make(map[string]cty.Value, len(env))
+	for k, v := range env {
+		vars[k] = cty.StringVal(v)
+	}
+	return &hcl.EvalContext{Variables: map[string]cty.Value{"env": cty.ObjectVal(vars)}}
+}
+
+func (c *Config) validate() error {
+	if _, err := DialectFor(c.Driver); err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, r := range c.Roles {
+		if seen[r.Name] {
+			return fmt.Errorf("role %q is declared twice", r.Name)
+		}
+		seen[r.Name] = true
+	}
+	for _, u := range c.Users {
+		if seen[u.Name] {
+			return fmt.Errorf("user %q is declared twice or is also a role", u.Name)
+		}
+		seen[u.Name] = true
+	}
+	return nil
+}
+
+// Desired returns the roles of the config, roles before users so users can
+// be granted them.
+func (c *Config) Desired() []Role {
+	roles := make([]Role, 0, len(c.Roles)+len(c.Users))
+	for _, r := range c.Roles {
+		roles = append(roles, Role{
+			Name:       r.Name,
+			Absent:     r.Absent,
+			MemberOf:   r.Roles,
+			Privileges: privileges(r.Privileges),
+		})
+	}
+	for _, u := range c.Users {
+		roles = append(roles, Role{
+			Name:       u.Name,
+			Host:       u.Host,
+			Login:      true,
+			Password:   u.Password,
+			Absent:     u.Absent,
+			MemberOf:   u.Roles,
+			Privileges: privileges(u.Privileges),
+		})
+	}
+	return roles
+}
+
+func privileges(configs []PrivilegeConfig) []Privilege {
+	var ps []Privilege
+	for _, c := range configs {
+		for _, p := range c.Privileges {
+			ps = append(ps, Privilege{Privilege: p, On: c.On})
+		}
+	}
+	return ps
+}
@@ -0,0 +1,59 @@
+package rolesync_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"tmp/pragmatic-cases/rolesync"
+)
+
+func TestLoadConfig(t *testing.T) {
+	config, err := rolesync.LoadConfig("testdata/postgres.hcl", map[string]string{"MY_USER_PASSWORD": "s3cret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []rolesync.Role{
+		{Name: "readonly", Privileges: []rolesync.Privilege{
+			{Privilege: "CONNECT", On: "database postgres"},
+			{Privilege: "USAGE", On: "schema public"},
+			{Privilege: "SELECT", On: "table public.users"},
+		}},
+		{Name: "my_user", Login: true, Password: "s3cret", MemberOf: []string{"readonly"}},
+		{Name: "old_user", Login: true, Absent: true},
+	}
+	if got := config.Desired(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeConfig(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+	}{
+		{"missing env", `driver = "mysql"
+user "app" { password = env.NOPE }`},
+		{"unknown driver", `driver = "oracle"`},
+		{"duplicate role", `driver = "mysql"
+role "r" {}
+role "r" {}`},
+		{"user named like a role", `driver = "mysql"
+role "r" {}
+user "r" {}`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := rolesync.DecodeConfig("roles.hcl", []byte(c.src), nil); err == nil {
+				t.Error("got no error, want one")
+			}
+		})
+	}
+
+	t.Run("unknown driver is ErrUnknownDriver", func(t *testing.T) {
+		_, err := rolesync.DecodeConfig("roles.hcl", []byte(`driver = "oracle"`), nil)
+		if !errors.Is(err, rolesync.ErrUnknownDriver) {
+			t.Errorf("got %v, want %v", err, rolesync.ErrUnknownDriver)
+		}
+	})
+}
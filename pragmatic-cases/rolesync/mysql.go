@@ -0,0 +1,150 @@
+package rolesync
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"tmp/pragmatic-cases/mysql/users"
+)
+
+// MySQL is the dialect of MySQL 8, where roles are accounts that are granted
+// to other accounts. Targets are "*.*", "db.*" and "db.table". Privileges and
+// quoting are those of tmp/pragmatic-cases/mysql/users.
+type MySQL struct{}
+
+func (MySQL) account(r Role) users.Account {
+	return users.Account{User: r.Name, Host: r.Host}
+}
+
+func (MySQL) Normalize(p Privilege) (Privilege, error) {
+	database, table, ok := strings.Cut(p.On, ".")
+	if !ok {
+		return Privilege{}, fmt.Errorf("%w: target %q, want db.table", users.ErrInvalidName, p.On)
+	}
+	g := users.Grant{Privilege: p.Privilege, Database: database, Table: table}.Normalize()
+	if err := g.Validate(); err != nil {
+		return Privilege{}, err
+	}
+	return Privilege{Privilege: g.Privilege, On: g.Database + "." + g.Table}, nil
+}
+
+func (MySQL) Expand(p Privilege) []Privilege {
+	database, table, _ := strings.Cut(p.On, ".")
+	var ps []Privilege
+	for _, g := range (users.Grant{Privilege: p.Privilege, Database: database, Table: table}).Expand() {
+		ps = append(ps, Privilege{Privilege: g.Privilege, On: g.Database + "." + g.Table})
+	}
+	return ps
+}
+
+// Inspect reads the account from mysql.user, its privileges from
+// information_schema and its roles from mysql.role_edges. MySQL does not
+// tell users from roles, so Login is the one of r.
+func (d MySQL) Inspect(ctx context.Context, db *sql.DB, r Role) (*Role, error) {
+	account := d.account(r)
+	if err := account.Validate(); err != nil {
+		return nil, err
+	}
+	if err := users.ValidatePassword(r.Password); err != nil {
+		return nil, err
+	}
+	m := users.New(db)
+	exists, err := m.Exists(ctx, account)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	live := &Role{Name: r.Name, Host: r.Host, Login: r.Login}
+	grants, err := m.Grants(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range grants {
+		live.Privileges = append(live.Privileges, Privilege{Privilege: g.Privilege, On: g.Database + "." + g.Table})
+	}
+
+	host := account.Host
+	if host == "" {
+		host = "%"
+	}
+	rows, err := db.QueryContext(ctx, "SELECT FROM_USER FROM mysql.role_edges WHERE TO_USER = ? AND TO_HOST = ?", account.User, host)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		live.MemberOf = append(live.MemberOf, name)
+	}
+	return live, rows.Err()
+}
+
+func (d MySQL) Create(r Role) []Change {
+	account := d.account(r).String()
+	if !r.Login {
+		return []Change{{SQL: "CREATE ROLE IF NOT EXISTS " + account}}
+	}
+	c := Change{SQL: "CREATE USER IF NOT EXISTS " + account}
+	if r.Password != "" {
+		c.display = c.SQL + " IDENTIFIED BY '<redacted>'"
+		c.SQL += " IDENTIFIED BY " + users.QuoteString(r.Password)
+	}
+	return []Change{c}
+}
+
+func (d MySQL) Drop(r Role) []Change {
+	if !r.Login {
+		return []Change{{SQL: "DROP ROLE IF EXISTS " + d.account(r).String()}}
+	}
+	return []Change{{SQL: "DROP USER IF EXISTS " + d.account(r).String()}}
+}
+
+func (MySQL) SetLogin(r Role) []Change { return nil }
+
+func (d MySQL) Grant(r Role, ps []Privilege) []Change {
+	var changes []Change
+	for _, group := range byTarget(ps) {
+		changes = append(changes, Change{SQL: "GRANT " + privilegeList(group) + " ON " + d.target(group[0]) + " TO " + d.account(r).String()})
+	}
+	return changes
+}
+
+func (d MySQL) Revoke(r Role, ps []Privilege) []Change {
+	var changes []Change
+	for _, group := range byTarget(ps) {
+		changes = append(changes, Change{SQL: "REVOKE " + privilegeList(group) + " ON " + d.target(group[0]) + " FROM " + d.account(r).String()})
+	}
+	return changes
+}
+
+func (MySQL) target(p Privilege) string {
+	database, table, _ := strings.Cut(p.On, ".")
+	return users.Grant{Database: database, Table: table}.Target()
+}
+
+// GrantRoles also makes the roles of r active when it logs in, which they
+// are not by default.
+func (d MySQL) GrantRoles(r Role, parents []string) []Change {
+	account := d.account(r).String()
+	changes := []Change{{SQL: "GRANT " + d.roles(parents) + " TO " + account}}
+	if r.Login {
+		changes = append(changes, Change{SQL: "SET DEFAULT ROLE ALL TO " + account})
+	}
+	return changes
+}
+
+func (d MySQL) RevokeRoles(r Role, parents []string) []Change {
+	return []Change{{SQL: "REVOKE " + d.roles(parents) + " FROM " + d.account(r).String()}}
+}
+
+func (d MySQL) roles(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = d.account(Role{Name: name}).String()
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,180 @@
+package rolesync_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"tmp/pragmatic-cases/mysql/users"
+	"tmp/pragmatic-cases/rolesync"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// mysqlAccount expects the inspection of an account at host %; privileges
+// are nil when it does not exist.
+func mysqlAccount(mock sqlmock.Sqlmock, name string, privileges [][3]string, memberOf ...string) {
+	count := 0
+	if privileges != nil {
+		count = 1
+	}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM mysql.user where User = ? AND Host = ?")).
+		WithArgs(name, "%").
+		WillReturnRows(sqlmock.NewRows([]string{"cnt"}).AddRow(count))
+	if privileges == nil {
+		return
+	}
+
+	rows := sqlmock.NewRows([]string{"PRIVILEGE_TYPE", "TABLE_SCHEMA", "TABLE_NAME"})
+	for _, p := range privileges {
+		rows.AddRow(p[0], p[1], p[2])
+	}
+	grantee := "'" + name + "'@'%'"
+	mock.ExpectQuery(regexp.QuoteMeta("FROM information_schema.USER_PRIVILEGES")).
+		WithArgs(grantee, grantee, grantee).
+		WillReturnRows(rows)
+
+	edges := sqlmock.NewRows([]string{"FROM_USER"})
+	for _, m := range memberOf {
+		edges.AddRow(m)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT FROM_USER FROM mysql.role_edges WHERE TO_USER = ? AND TO_HOST = ?")).
+		WithArgs(name, "%").
+		WillReturnRows(edges)
+}
+
+func mysqlConfig(t *testing.T) []rolesync.Role {
+	t.Helper()
+	config, err := rolesync.LoadConfig("testdata/mysql.hcl", map[string]string{"APP_PASSWORD": "p'w"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config.Desired()
+}
+
+func TestMySQLPlan(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("nothing exists", func(t *testing.T) {
+		r, mock := newReconciler(t, rolesync.MySQL{})
+		mysqlAccount(mock, "app_read", nil)
+		mysqlAccount(mock, "app", nil)
+		mysqlAccount(mock, "test_user", nil)
+
+		plan, err := r.Plan(ctx, mysqlConfig(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertPlan(t, plan, "CREATE ROLE IF NOT EXISTS 'app_read'@'%';\n"+
+			"CREATE USER IF NOT EXISTS 'app'@'%' IDENTIFIED BY '<redacted>';\n"+
+			"GRANT SELECT ON `app`.* TO 'app_read'@'%';\n"+
+			"GRANT INSERT, UPDATE ON `app`.`events` TO 'app'@'%';\n"+
+			"GRANT 'app_read'@'%' TO 'app'@'%';\n"+
+			"SET DEFAULT ROLE ALL TO 'app'@'%';")
+		if got := plan.Changes[1].SQL; got != `CREATE USER IF NOT EXISTS 'app'@'%' IDENTIFIED BY 'p''w'` {
+			t.Errorf("got %s, want the quoted password", got)
+		}
+	})
+
+	t.Run("drifted accounts", func(t *testing.T) {
+		r, mock := newReconciler(t, rolesync.MySQL{})
+		mysqlAccount(mock, "app_read", [][3]string{{"USAGE", "*", "*"}, {"SELECT", "app", "*"}, {"DELETE", "app", "*"}})
+		mysqlAccount(mock, "app", [][3]string{{"INSERT", "app", "events"}}, "app_read", "app_admin")
+		mysqlAccount(mock, "test_user", [][3]string{{"USAGE", "*", "*"}})
+
+		plan, err := r.Plan(ctx, mysqlConfig(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertPlan(t, plan, "REVOKE DELETE ON `app`.* FROM 'app_read'@'%';\n"+
+			"GRANT UPDATE ON `app`.`events` TO 'app'@'%';\n"+
+			"REVOKE 'app_admin'@'%' FROM 'app'@'%';\n"+
+			"DROP USER IF EXISTS 'test_user'@'%';")
+	})
+
+	t.Run("in sync", func(t *testing.T) {
+		r, mock := newReconciler(t, rolesync.MySQL{})
+		mysqlAccount(mock, "app_read", [][3]string{{"SELECT", "app", "*"}})
+		mysqlAccount(mock, "app", [][3]string{{"INSERT", "app", "events"}, {"UPDATE", "app", "events"}}, "app_read")
+		mysqlAccount(mock, "test_user", nil)
+
+		plan, err := r.Plan(ctx, mysqlConfig(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !plan.Empty() {
+			t.Errorf("want an empty plan, got\n%s", plan)
+		}
+	})
+
+	t.Run("injection in names is quoted", func(t *testing.T) {
+		r, mock := newReconciler(t, rolesync.MySQL{})
+		name := "x'@'%'; DROP USER root; --"
+		mock.ExpectQuery(regexp.QuoteMeta("FROM mysql.user")).WithArgs(name, "%").
+			WillReturnRows(sqlmock.NewRows([]string{"cnt"}).AddRow(0))
+
+		plan, err := r.Plan(ctx, []rolesync.Role{{Name: name, Privileges: []rolesync.Privilege{{Privilege: "SELECT", On: "a`b.*"}}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertPlan(t, plan, "CREATE ROLE IF NOT EXISTS 'x''@''%''; DROP USER root; --'@'%';\n"+
+			"GRANT SELECT ON `a``b`.* TO 'x''@''%''; DROP USER root; --'@'%';")
+	})
+
+	t.Run("ALL PRIVILEGES over some of its privileges", func(t *testing.T) {
+		all := []rolesync.Role{{Name: "app_admin", Privileges: []rolesync.Privilege{{Privilege: "ALL", On: "app.*"}}}}
+
+		r, mock := newReconciler(t, rolesync.MySQL{})
+		mysqlAccount(mock, "app_admin", [][3]string{{"SELECT", "app", "*"}})
+		plan, err := r.Plan(ctx, all)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertPlan(t, plan, "GRANT ALL PRIVILEGES ON `app`.* TO 'app_admin'@'%';")
+
+		r, mock = newReconciler(t, rolesync.MySQL{})
+		var held [][3]string
+		for _, p := range (rolesync.MySQL{}).Expand(rolesync.Privilege{Privilege: "ALL PRIVILEGES", On: "app.*"}) {
+			held = append(held, [3]string{p.Privilege, "app", "*"})
+		}
+		mysqlAccount(mock, "app_admin", held)
+		if plan, err = r.Plan(ctx, all); err != nil {
+			t.Fatal(err)
+		}
+		if !plan.Empty() {
+			t.Errorf("want an empty plan once all are held, got\n%s", plan)
+		}
+	})
+
+	t.Run("password with a backslash", func(t *testing.T) {
+		r, _ := newReconciler(t, rolesync.MySQL{})
+		_, err := r.Plan(ctx, []rolesync.Role{{Name: "app", Login: true, Password: `p\w`}})
+		if !errors.Is(err, users.ErrInvalidName) {
+			t.Errorf("got %v, want %v", err, users.ErrInvalidName)
+		}
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		r, _ := newReconciler(t, rolesync.MySQL{})
+		_, err := r.Plan(ctx, []rolesync.Role{{Name: "r", Privileges: []rolesync.Privilege{{Privilege: "SELECT", On: "app"}}}})
+		if !errors.Is(err, users.ErrInvalidName) {
+			t.Errorf("got %v, want %v", err, users.ErrInvalidName)
+		}
+	})
+}
+
+func TestMySQLApply(t *testing.T) {
+	r, mock := newReconciler(t, rolesync.MySQL{})
+	mysqlAccount(mock, "app", nil)
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE USER IF NOT EXISTS 'app'@'%' IDENTIFIED BY 'secret'`)).WillReturnError(errors.New("access denied"))
+
+	plan, err := r.Plan(context.Background(), []rolesync.Role{{Name: "app", Login: true, Password: "secret"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.Apply(context.Background(), plan)
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("got %v, want an error without the password", err)
+	}
+}
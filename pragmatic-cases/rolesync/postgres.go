@@ -0,0 +1,200 @@
+package rolesync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidPrivilege = errors.New("rolesync: invalid privilege")
+
+// postgresPrivileges are the privileges of each kind of target.
+var postgresPrivileges = map[string][]string{
+	"database": {"CONNECT", "CREATE", "TEMPORARY"},
+	"schema":   {"CREATE", "USAGE"},
+	"table":    {"DELETE", "INSERT", "REFERENCES", "SELECT", "TRIGGER", "TRUNCATE", "UPDATE"},
+}
+
+// Postgres is the dialect of PostgreSQL, where users are roles with LOGIN.
+// Targets are "database app", "schema public" and "table public.users".
+type Postgres struct{}
+
+func (Postgres) Normalize(p Privilege) (Privilege, error) {
+	kind, name, _ := strings.Cut(strings.TrimSpace(p.On), " ")
+	kind, name = strings.ToLower(kind), strings.TrimSpace(name)
+	allowed, ok := postgresPrivileges[kind]
+	if !ok || name == "" {
+		return Privilege{}, fmt.Errorf("%w: target %q, want database, schema or table and a name", ErrInvalidPrivilege, p.On)
+	}
+	if kind == "table" && !strings.Contains(name, ".") {
+		return Privilege{}, fmt.Errorf("%w: table %q needs a schema", ErrInvalidPrivilege, name)
+	}
+
+	privilege := strings.Join(strings.Fields(strings.ToUpper(p.Privilege)), " ")
+	switch privilege {
+	case "ALL":
+		privilege = "ALL PRIVILEGES"
+	case "TEMP":
+		privilege = "TEMPORARY"
+	}
+	if privilege != "ALL PRIVILEGES" && !slices.Contains(allowed, privilege) {
+		return Privilege{}, fmt.Errorf("%w: %q on %s, want one of %s", ErrInvalidPrivilege, p.Privilege, kind, strings.Join(allowed, ", "))
+	}
+	return Privilege{Privilege: privilege, On: kind + " " + name}, nil
+}
+
+func (Postgres) Expand(p Privilege) []Privilege {
+	if p.Privilege != "ALL PRIVILEGES" {
+		return []Privilege{p}
+	}
+	kind, _, _ := strings.Cut(p.On, " ")
+	var ps []Privilege
+	for _, privilege := range postgresPrivileges[kind] {
+		ps = append(ps, Privilege{Privilege: privilege, On: p.On})
+	}
+	return ps
+}
+
+// postgresPrivilegesQuery lists the privileges granted to the role $1 on
+// databases, schemas and tables, in the form of Postgres.Normalize. The ACL of
+// an object lists its owner's privileges too; those come with ownership, not a
+// grant, so they are left out and never revoked.
+const postgresPrivilegesQuery = `SELECT 'database ' || d.datname, a.privilege_type FROM pg_database d CROSS JOIN LATERAL aclexplode(d.datacl) a WHERE a.grantee <> d.datdba AND a.grantee = (SELECT oid FROM pg_roles WHERE rolname = $1)
+UNION ALL SELECT 'schema ' || n.nspname, a.privilege_type FROM pg_namespace n CROSS JOIN LATERAL aclexplode(n.nspacl) a WHERE a.grantee <> n.nspowner AND a.grantee = (SELECT oid FROM pg_roles WHERE rolname = $1)
+UNION ALL SELECT 'table ' || n.nspname || '.' || c.relname, a.privilege_type FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace CROSS JOIN LATERAL aclexplode(c.relacl) a WHERE c.relkind IN ('r', 'v', 'm', 'p') AND a.grantee <> c.relowner AND a.grantee = (SELECT oid FROM pg_roles WHERE rolname = $1)`
+
+// Inspect reads the role from pg_roles, its privileges from the ACLs of
+// databases, schemas and tables, and its roles from pg_auth_members.
+func (Postgres) Inspect(ctx context.Context, db *sql.DB, r Role) (*Role, error) {
+	live := &Role{Name: r.Name}
+	err := db.QueryRowContext(ctx, "SELECT rolcanlogin FROM pg_roles WHERE rolname = $1", r.Name).Scan(&live.Login)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, postgresPrivilegesQuery, r.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p Privilege
+		if err := rows.Scan(&p.On, &p.Privilege); err != nil {
+			return nil, err
+		}
+		live.Privileges = append(live.Privileges, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.QueryContext(ctx, "SELECT r.rolname FROM pg_auth_members m JOIN pg_roles r ON r.oid = m.roleid WHERE m.member = (SELECT oid FROM pg_roles WHERE rolname = $1)", r.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		live.MemberOf = append(live.MemberOf, name)
+	}
+	return live, rows.Err()
+}
+
+func (Postgres) Create(r Role) []Change {
+	c := Change{SQL: "CREATE ROLE " + pgIdentifier(r.Name)}
+	if r.Login {
+		c.SQL += " LOGIN"
+	}
+	if r.Password != "" {
+		c.display = c.SQL + " PASSWORD '<redacted>'"
+		c.SQL += " PASSWORD " + pgLiteral(r.Password)
+	}
+	return []Change{c}
+}
+
+// Drop hands what the role owns in the current database over to the user
+// running the plan, so no table is dropped with it, then revokes what it was
+// granted there, without which DROP ROLE fails. Objects it owns in other
+// databases still make DROP ROLE fail.
+func (Postgres) Drop(r Role) []Change {
+	name := pgIdentifier(r.Name)
+	return []Change{
+		{SQL: "REASSIGN OWNED BY " + name + " TO CURRENT_USER"},
+		{SQL: "DROP OWNED BY " + name},
+		{SQL: "DROP ROLE IF EXISTS " + name},
+	}
+}
+
+func (Postgres) SetLogin(r Role) []Change {
+	if r.Login {
+		return []Change{{SQL: "ALTER ROLE " + pgIdentifier(r.Name) + " LOGIN"}}
+	}
+	return []Change{{SQL: "ALTER ROLE " + pgIdentifier(r.Name) + " NOLOGIN"}}
+}
+
+func (Postgres) Grant(r Role, ps []Privilege) []Change {
+	var changes []Change
+	for _, group := range byTarget(ps) {
+		changes = append(changes, Change{SQL: "GRANT " + privilegeList(group) + " ON " + pgTarget(group[0].On) + " TO " + pgIdentifier(r.Name)})
+	}
+	return changes
+}
+
+func (Postgres) Revoke(r Role, ps []Privilege) []Change {
+	var changes []Change
+	for _, group := range byTarget(ps) {
+		changes = append(changes, Change{SQL: "REVOKE " + privilegeList(group) + " ON " + pgTarget(group[0].On) + " FROM " + pgIdentifier(r.Name)})
+	}
+	return changes
+}
+
+func (Postgres) GrantRoles(r Role, parents []string) []Change {
+	return []Change{{SQL: "GRANT " + pgIdentifiers(parents) + " TO " + pgIdentifier(r.Name)}}
+}
+
+func (Postgres) RevokeRoles(r Role, parents []string) []Change {
+	return []Change{{SQL: "REVOKE " + pgIdentifiers(parents) + " FROM " + pgIdentifier(r.Name)}}
+}
+
+// pgTarget is the ON clause of a normalized target: "table public.users" is
+// TABLE "public"."users".
+func pgTarget(on string) string {
+	kind, name, _ := strings.Cut(on, " ")
+	if kind == "table" {
+		schema, table, _ := strings.Cut(name, ".")
+		return "TABLE " + pgIdentifier(schema) + "." + pgIdentifier(table)
+	}
+	return strings.ToUpper(kind) + " " + pgIdentifier(name)
+}
+
+func pgIdentifier(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+func pgIdentifiers(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = pgIdentifier(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// pgLiteral quotes a string literal like lib/pq's QuoteLiteral: quotes are
+// doubled, and a literal with backslashes is an escape string (E'...') with
+// them escaped.
+func pgLiteral(s string) string {
+	s = strings.ReplaceAll(s, "'", "''")
+	if strings.Contains(s, `\`) {
+		return `E'` + strings.ReplaceAll(s, `\`, `\\`) + `'`
+	}
+	return "'" + s + "'"
+}
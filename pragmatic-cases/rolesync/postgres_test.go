@@ -0,0 +1,233 @@
+package rolesync_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"tmp/pragmatic-cases/rolesync"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func newReconciler(t *testing.T, dialect rolesync.Dialect) (*rolesync.Reconciler, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+	return &rolesync.Reconciler{DB: db, Dialect: dialect}, mock
+}
+
+// pgRole expects the inspection of a role; login is nil when it does not
+// exist.
+func pgRole(mock sqlmock.Sqlmock, name string, login *bool, privileges [][2]string, memberOf ...string) {
+	q := mock.ExpectQuery(regexp.QuoteMeta("SELECT rolcanlogin FROM pg_roles WHERE rolname = $1")).WithArgs(name)
+	if login == nil {
+		q.WillReturnRows(sqlmock.NewRows([]string{"rolcanlogin"}))
+		return
+	}
+	q.WillReturnRows(sqlmock.NewRows([]string{"rolcanlogin"}).AddRow(*login))
+
+	rows := sqlmock.NewRows([]string{"target", "privilege_type"})
+	for _, p := range privileges {
+		rows.AddRow(p[0], p[1])
+	}
+	mock.ExpectQuery("^SELECT 'database ' \\|\\| d.datname").WithArgs(name).WillReturnRows(rows)
+
+	members := sqlmock.NewRows([]string{"rolname"})
+	for _, m := range memberOf {
+		members.AddRow(m)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta("FROM pg_auth_members")).WithArgs(name).WillReturnRows(members)
+}
+
+func ptr(b bool) *bool { return &b }
+
+func pgConfig(t *testing.T) []rolesync.Role {
+	t.Helper()
+	config, err := rolesync.LoadConfig("testdata/postgres.hcl", map[string]string{"MY_USER_PASSWORD": "it's"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config.Desired()
+}
+
+func TestPostgresPlan(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("nothing exists", func(t *testing.T) {
+		r, mock := newReconciler(t, rolesync.Postgres{})
+		pgRole(mock, "readonly", nil, nil)
+		pgRole(mock, "my_user", nil, nil)
+		pgRole(mock, "old_user", nil, nil)
+
+		plan, err := r.Plan(ctx, pgConfig(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertPlan(t, plan, `CREATE ROLE "readonly";
+CREATE ROLE "my_user" LOGIN PASSWORD '<redacted>';
+GRANT CONNECT ON DATABASE "postgres" TO "readonly";
+GRANT USAGE ON SCHEMA "public" TO "readonly";
+GRANT SELECT ON TABLE "public"."users" TO "readonly";
+GRANT "readonly" TO "my_user";`)
+		if got := plan.Changes[1].SQL; got != `CREATE ROLE "my_user" LOGIN PASSWORD 'it''s'` {
+			t.Errorf("got %s, want the quoted password", got)
+		}
+	})
+
+	t.Run("drifted roles", func(t *testing.T) {
+		r, mock := newReconciler(t, rolesync.Postgres{})
+		pgRole(mock, "readonly", ptr(false), [][2]string{
+			{"database postgres", "CONNECT"},
+			{"schema public", "USAGE"},
+			{"schema public", "CREATE"},
+		})
+		pgRole(mock, "my_user", ptr(false), nil, "admin")
+		pgRole(mock, "old_user", ptr(true), nil)
+
+		plan, err := r.Plan(ctx, pgConfig(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertPlan(t, plan, `ALTER ROLE "my_user" LOGIN;
+REVOKE CREATE ON SCHEMA "public" FROM "readonly";
+GRANT SELECT ON TABLE "public"."users" TO "readonly";
+REVOKE "admin" FROM "my_user";
+GRANT "readonly" TO "my_user";
+REASSIGN OWNED BY "old_user" TO CURRENT_USER;
+DROP OWNED BY "old_user";
+DROP ROLE IF EXISTS "old_user";`)
+	})
+
+	t.Run("in sync", func(t *testing.T) {
+		r, mock := newReconciler(t, rolesync.Postgres{})
+		pgRole(mock, "readonly", ptr(false), [][2]string{
+			{"database postgres", "CONNECT"},
+			{"schema public", "USAGE"},
+			{"table public.users", "SELECT"},
+		})
+		pgRole(mock, "my_user", ptr(true), nil, "readonly")
+		pgRole(mock, "old_user", nil, nil)
+
+		plan, err := r.Plan(ctx, pgConfig(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !plan.Empty() {
+			t.Errorf("want an empty plan, got\n%s", plan)
+		}
+	})
+
+	t.Run("ALL PRIVILEGES over some of its privileges", func(t *testing.T) {
+		all := []rolesync.Role{{Name: "owner", Privileges: []rolesync.Privilege{{Privilege: "ALL", On: "table public.users"}}}}
+
+		r, mock := newReconciler(t, rolesync.Postgres{})
+		pgRole(mock, "owner", ptr(false), [][2]string{{"table public.users", "SELECT"}})
+		plan, err := r.Plan(ctx, all)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertPlan(t, plan, `GRANT ALL PRIVILEGES ON TABLE "public"."users" TO "owner";`)
+
+		r, mock = newReconciler(t, rolesync.Postgres{})
+		var held [][2]string
+		for _, privilege := range []string{"DELETE", "INSERT", "REFERENCES", "SELECT", "TRIGGER", "TRUNCATE", "UPDATE", "MAINTAIN"} {
+			held = append(held, [2]string{"table public.users", privilege})
+		}
+		pgRole(mock, "owner", ptr(false), held)
+		if plan, err = r.Plan(ctx, all); err != nil {
+			t.Fatal(err)
+		}
+		if !plan.Empty() {
+			t.Errorf("want an empty plan once all are held, got\n%s", plan)
+		}
+	})
+
+	t.Run("owned table", func(t *testing.T) {
+		r, mock := newReconciler(t, rolesync.Postgres{})
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT rolcanlogin FROM pg_roles WHERE rolname = $1")).WithArgs("owner").
+			WillReturnRows(sqlmock.NewRows([]string{"rolcanlogin"}).AddRow(false))
+		// the owner of public.users has its ACL entries, the query leaves them out
+		mock.ExpectQuery(`a\.grantee <> d\.datdba .* a\.grantee <> n\.nspowner .* a\.grantee <> c\.relowner`).WithArgs("owner").
+			WillReturnRows(sqlmock.NewRows([]string{"target", "privilege_type"}))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM pg_auth_members")).WithArgs("owner").
+			WillReturnRows(sqlmock.NewRows([]string{"rolname"}))
+
+		plan, err := r.Plan(ctx, []rolesync.Role{{Name: "owner"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !plan.Empty() {
+			t.Errorf("want no REVOKE of what the owner holds, got\n%s", plan)
+		}
+	})
+
+	t.Run("invalid privilege", func(t *testing.T) {
+		r, _ := newReconciler(t, rolesync.Postgres{})
+		_, err := r.Plan(ctx, []rolesync.Role{{Name: "r", Privileges: []rolesync.Privilege{{Privilege: "USAGE", On: "table public.users"}}}})
+		if !errors.Is(err, rolesync.ErrInvalidPrivilege) {
+			t.Errorf("got %v, want %v", err, rolesync.ErrInvalidPrivilege)
+		}
+	})
+}
+
+func TestPostgresApply(t *testing.T) {
+	r, mock := newReconciler(t, rolesync.Postgres{})
+	pgRole(mock, "r", nil, nil)
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE ROLE "r" LOGIN PASSWORD E'back\\slash'`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`GRANT "a""b" TO "r"`)).WillReturnError(errors.New(`role "a""b" does not exist`))
+
+	plan, err := r.Plan(context.Background(), []rolesync.Role{{Name: "r", Login: true, Password: `back\slash`, MemberOf: []string{`a"b`}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.Apply(context.Background(), plan)
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("got %v, want the failure of the GRANT", err)
+	}
+}
+
+func TestPostgresNormalize(t *testing.T) {
+	cases := []struct {
+		in   rolesync.Privilege
+		want rolesync.Privilege
+	}{
+		{rolesync.Privilege{Privilege: "select", On: "TABLE public.users"}, rolesync.Privilege{Privilege: "SELECT", On: "table public.users"}},
+		{rolesync.Privilege{Privilege: "temp", On: "database app"}, rolesync.Privilege{Privilege: "TEMPORARY", On: "database app"}},
+		{rolesync.Privilege{Privilege: "all", On: "schema public"}, rolesync.Privilege{Privilege: "ALL PRIVILEGES", On: "schema public"}},
+	}
+	for _, c := range cases {
+		got, err := rolesync.Postgres{}.Normalize(c.in)
+		if err != nil {
+			t.Errorf("%v: %v", c.in, err)
+		} else if got != c.want {
+			t.Errorf("got %v, want %v", got, c.want)
+		}
+	}
+
+	for _, p := range []rolesync.Privilege{
+		{Privilege: "SELECT", On: "users"},
+		{Privilege: "SELECT", On: "table users"},
+		{Privilege: "SELECT; DROP ROLE postgres", On: "table public.users"},
+	} {
+		if _, err := (rolesync.Postgres{}).Normalize(p); !errors.Is(err, rolesync.ErrInvalidPrivilege) {
+			t.Errorf("%v: got %v, want %v", p, err, rolesync.ErrInvalidPrivilege)
+		}
+	}
+}
+
+func assertPlan(t testing.TB, plan *rolesync.Plan, want string) {
+	t.Helper()
+	if got := plan.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
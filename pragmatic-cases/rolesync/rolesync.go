@@ -0,0 +1,262 @@
+// Package rolesync reconciles the roles, users and privileges of a MySQL or
+// Postgres server with a declarative HCL config: it inspects the live
+// server, plans the statements to run and runs them.
+package rolesync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var ErrUnknownDriver = errors.New("rolesync: unknown driver")
+
+// Role is a role, or a user when it can log in.
+type Role struct {
+	Name       string
+	Host       string // MySQL only
+	Login      bool
+	Password   string // only set when the role is created, never compared
+	Absent     bool   // the role is dropped
+	MemberOf   []string
+	Privileges []Privilege
+}
+
+// Privilege is one privilege on a target, in the syntax of the dialect.
+type Privilege struct {
+	Privilege string
+	On        string
+}
+
+func (p Privilege) String() string { return p.Privilege + " ON " + p.On }
+
+// Change is one statement of a plan.
+type Change struct {
+	SQL     string
+	display string // SQL with secrets redacted, when it has any
+}
+
+func (c Change) String() string {
+	if c.display != "" {
+		return c.display
+	}
+	return c.SQL
+}
+
+// Dialect is what differs between servers: inspecting a role and writing the
+// statements that change it.
+type Dialect interface {
+	// Normalize validates p and returns it the way Inspect reports it.
+	Normalize(p Privilege) (Privilege, error)
+	// Expand returns what ALL PRIVILEGES on the target of p is reported as
+	// by Inspect, or p alone for any other privilege.
+	Expand(p Privilege) []Privilege
+	// Inspect returns the live role named like r, or nil when there is none.
+	Inspect(ctx context.Context, db *sql.DB, r Role) (*Role, error)
+
+	Create(r Role) []Change
+	Drop(r Role) []Change
+	// SetLogin is nil when the dialect does not tell users and roles apart.
+	SetLogin(r Role) []Change
+	Grant(r Role, ps []Privilege) []Change
+	Revoke(r Role, ps []Privilege) []Change
+	GrantRoles(r Role, parents []string) []Change
+	RevokeRoles(r Role, parents []string) []Change
+}
+
+// DialectFor returns the dialect of a database/sql driver name.
+func DialectFor(driver string) (Dialect, error) {
+	switch driver {
+	case "mysql":
+		return MySQL{}, nil
+	case "postgres", "pgx":
+		return Postgres{}, nil
+	}
+	return nil, fmt.Errorf("%w %q, want mysql or postgres", ErrUnknownDriver, driver)
+}
+
+// Plan is the statements that bring the server to the desired roles, in the
+// order they run.
+type Plan struct {
+	Changes []Change
+}
+
+func (p *Plan) Empty() bool { return len(p.Changes) == 0 }
+
+// String is the statements of the plan, one per line, with passwords
+// redacted.
+func (p *Plan) String() string {
+	lines := make([]string, len(p.Changes))
+	for i, c := range p.Changes {
+		lines[i] = c.String() + ";"
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Reconciler plans and applies desired roles on DB.
+type Reconciler struct {
+	DB      *sql.DB
+	Dialect Dialect
+}
+
+// Plan inspects every desired role and returns what Apply would run. Roles
+// missing from desired are left alone: only Absent ones are dropped.
+//
+// Roles are created before privileges and memberships are granted, extra
+// privileges and memberships are revoked before missing ones are granted,
+// and drops come last.
+func (r *Reconciler) Plan(ctx context.Context, desired []Role) (*Plan, error) {
+	var create, login, revoke, grant, leave, join []Change
+	var drops [][]Change
+	for _, want := range desired {
+		if want.Name == "" {
+			return nil, errors.New("rolesync: role without a name")
+		}
+		var err error
+		if want.Privileges, err = r.normalize(want.Privileges); err != nil {
+			return nil, fmt.Errorf("role %s: %w", want.Name, err)
+		}
+		live, err := r.Dialect.Inspect(ctx, r.DB, want)
+		if err != nil {
+			return nil, fmt.Errorf("inspect role %s: %w", want.Name, err)
+		}
+
+		if want.Absent {
+			if live != nil {
+				drops = append(drops, r.Dialect.Drop(want))
+			}
+			continue
+		}
+		if live == nil {
+			create = append(create, r.Dialect.Create(want)...)
+			live = &Role{Name: want.Name}
+		} else if live.Login != want.Login {
+			login = append(login, r.Dialect.SetLogin(want)...)
+		}
+
+		granted, revoked := diffPrivileges(want.Privileges, live.Privileges, r.Dialect.Expand)
+		if len(revoked) > 0 {
+			revoke = append(revoke, r.Dialect.Revoke(want, revoked)...)
+		}
+		if len(granted) > 0 {
+			grant = append(grant, r.Dialect.Grant(want, granted)...)
+		}
+
+		joined, left := diffNames(want.MemberOf, live.MemberOf)
+		if len(left) > 0 {
+			leave = append(leave, r.Dialect.RevokeRoles(want, left)...)
+		}
+		if len(joined) > 0 {
+			join = append(join, r.Dialect.GrantRoles(want, joined)...)
+		}
+	}
+
+	// users are dropped before the roles they are members of
+	slices.Reverse(drops)
+	return &Plan{Changes: slices.Concat(create, login, revoke, grant, leave, join, slices.Concat(drops...))}, nil
+}
+
+func (r *Reconciler) normalize(ps []Privilege) ([]Privilege, error) {
+	normalized := make([]Privilege, 0, len(ps))
+	for _, p := range ps {
+		n, err := r.Dialect.Normalize(p)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, n)
+	}
+	return normalized, nil
+}
+
+// Apply runs the changes of the plan in order and stops at the first
+// failure. Role statements are not transactional in MySQL, so a failed plan
+// is best fixed by planning again.
+func (r *Reconciler) Apply(ctx context.Context, plan *Plan) error {
+	for _, c := range plan.Changes {
+		if _, err := r.DB.ExecContext(ctx, c.SQL); err != nil {
+			return fmt.Errorf("%s: %w", c, err)
+		}
+	}
+	return nil
+}
+
+// diffPrivileges returns what to grant and revoke to go from actual to
+// desired. A desired privilege is held when everything expand turns it into
+// is, so ALL PRIVILEGES over part of its privileges is granted again, and
+// the privileges held on its target are left to it.
+func diffPrivileges(desired, actual []Privilege, expand func(Privilege) []Privilege) (grant, revoke []Privilege) {
+	all := map[string]bool{}
+	for _, p := range desired {
+		if p.Privilege == "ALL PRIVILEGES" {
+			all[p.On] = true
+		}
+	}
+
+	for _, p := range desired {
+		if all[p.On] && p.Privilege != "ALL PRIVILEGES" {
+			continue
+		}
+		held := !slices.ContainsFunc(expand(p), func(e Privilege) bool { return !slices.Contains(actual, e) })
+		if !held && !slices.Contains(grant, p) {
+			grant = append(grant, p)
+		}
+	}
+	for _, p := range actual {
+		if !all[p.On] && !slices.Contains(desired, p) {
+			revoke = append(revoke, p)
+		}
+	}
+	sortPrivileges(grant)
+	sortPrivileges(revoke)
+	return grant, revoke
+}
+
+func diffNames(desired, actual []string) (add, remove []string) {
+	for _, name := range desired {
+		if !slices.Contains(actual, name) && !slices.Contains(add, name) {
+			add = append(add, name)
+		}
+	}
+	for _, name := range actual {
+		if !slices.Contains(desired, name) {
+			remove = append(remove, name)
+		}
+	}
+	sort.Strings(add)
+	sort.Strings(remove)
+	return add, remove
+}
+
+func sortPrivileges(ps []Privilege) {
+	sort.Slice(ps, func(i, j int) bool {
+		if ps[i].On != ps[j].On {
+			return ps[i].On < ps[j].On
+		}
+		return ps[i].Privilege < ps[j].Privilege
+	})
+}
+
+// byTarget groups privileges by target, in the order of the sorted
+// privileges, for one GRANT or REVOKE per target.
+func byTarget(ps []Privilege) [][]Privilege {
+	var groups [][]Privilege
+	for i, p := range ps {
+		if i == 0 || p.On != ps[i-1].On {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], p)
+	}
+	return groups
+}
+
+func privilegeList(ps []Privilege) string {
+	names := make([]string, len(ps))
+	for i, p := range ps {
+		names[i] = p.Privilege
+	}
+	return strings.Join(names, ", ")
+}
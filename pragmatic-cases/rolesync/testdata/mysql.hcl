@@ -0,0 +1,22 @@
+driver = "mysql"
+
+role "app_read" {
+  privilege "app.*" {
+    privileges = ["SELECT"]
+  }
+}
+
+user "app" {
+  host     = "%"
+  password = env.APP_PASSWORD
+  roles    = ["app_read"]
+
+  privilege "app.events" {
+    privileges = ["INSERT", "UPDATE"]
+  }
+}
+
+# the user created by ../../mysql
+user "test_user" {
+  absent = true
+}
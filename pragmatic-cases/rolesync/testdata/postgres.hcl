@@ -0,0 +1,23 @@
+driver = "postgres"
+
+role "readonly" {
+  privilege "database postgres" {
+    privileges = ["CONNECT"]
+  }
+  privilege "schema public" {
+    privileges = ["USAGE"]
+  }
+  privilege "table public.users" {
+    privileges = ["SELECT"]
+  }
+}
+
+# the role created by ../../migrate/postgres
+user "my_user" {
+  password = env.MY_USER_PASSWORD
+  roles    = ["readonly"]
+}
+
+user "old_user" {
+  absent = true
+}
@@ -0,0 +1,83 @@
+package blocking
+
+import (
+	"context"
+	"sync"
+)
+
+// Barrier lets a fixed number of parties wait for each other. It is cyclic:
+// once the last party arrives everyone is released and the next generation
+// starts from zero.
+type Barrier struct {
+	mu         sync.Mutex
+	released   *sync.Cond
+	parties    int
+	waiting    int
+	generation uint64
+	closed     bool
+}
+
+// NewBarrier panics if parties < 1.
+func NewBarrier(parties int) *Barrier {
+	if parties < 1 {
+		panic("blocking: barrier needs at least one party")
+	}
+	b := &Barrier{parties: parties}
+	b.released = sync.NewCond(&b.mu)
+	return b
+}
+
+// Wait blocks until all parties of the current generation called Wait. A party
+// whose ctx is done leaves with ctx.Err() and no longer counts as arrived.
+func (b *Barrier) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.waiting++
+	if b.waiting == b.parties {
+		b.waiting = 0
+		b.generation++
+		b.released.Broadcast()
+		return nil
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.released.Broadcast()
+	})
+	defer stop()
+
+	for generation := b.generation; generation == b.generation; {
+		if b.closed {
+			return ErrClosed
+		}
+		if err := ctx.Err(); err != nil {
+			b.waiting--
+			return err
+		}
+		b.released.Wait()
+	}
+	return nil
+}
+
+// Waiting returns how many parties are blocked in the current generation.
+func (b *Barrier) Waiting() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.waiting
+}
+
+// Close releases every waiting party with ErrClosed, as do later calls to Wait.
+func (b *Barrier) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.released.Broadcast()
+}
@@ -0,0 +1,94 @@
+package blocking
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBarrier(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("releases everyone when the last party arrives", func(t *testing.T) {
+		b := NewBarrier(3)
+		var released atomic.Int32
+		var wg sync.WaitGroup
+		for range 2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assertNoError(t, b.Wait(ctx))
+				released.Add(1)
+			}()
+		}
+		waitUntil(t, func() bool { return b.Waiting() == 2 })
+		if released.Load() != 0 {
+			t.Fatal("released before the last party arrived")
+		}
+
+		assertNoError(t, b.Wait(ctx))
+		wg.Wait()
+		if released.Load() != 2 || b.Waiting() != 0 {
+			t.Errorf("released %d, %d still waiting", released.Load(), b.Waiting())
+		}
+	})
+
+	t.Run("is reusable across generations", func(t *testing.T) {
+		const parties, rounds = 4, 50
+		b := NewBarrier(parties)
+		var arrived [rounds]atomic.Int32
+		var wg sync.WaitGroup
+		for range parties {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for r := range rounds {
+					arrived[r].Add(1)
+					assertNoError(t, b.Wait(ctx))
+					// nobody may pass round r before everyone arrived at it
+					if got := arrived[r].Load(); got != parties {
+						t.Errorf("round %d: passed with %d arrived", r, got)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("a cancelled party no longer counts", func(t *testing.T) {
+		b := NewBarrier(2)
+		timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		if err := b.Wait(timeout); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+		}
+
+		done := make(chan error)
+		go func() { done <- b.Wait(ctx) }()
+		waitUntil(t, func() bool { return b.Waiting() == 1 })
+		assertNoError(t, b.Wait(ctx))
+		assertNoError(t, <-done)
+	})
+
+	t.Run("Close wakes every waiter", func(t *testing.T) {
+		b := NewBarrier(4)
+		errs := make(chan error, 3)
+		for range 3 {
+			go func() { errs <- b.Wait(ctx) }()
+		}
+		waitUntil(t, func() bool { return b.Waiting() == 3 })
+
+		b.Close()
+		for range 3 {
+			if err := <-errs; !errors.Is(err, ErrClosed) {
+				t.Errorf("got %v, want %v", err, ErrClosed)
+			}
+		}
+		if err := b.Wait(ctx); !errors.Is(err, ErrClosed) {
+			t.Errorf("Wait after Close: got %v, want %v", err, ErrClosed)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package blocking
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+var ErrClosed = errors.New("blocking: closed")
+
+// Queue is a bounded FIFO queue. Put blocks while it is full and Take while it
+// is empty. Blocked callers are served in arrival order: a goroutine calling
+// Take never overtakes one that was already waiting.
+type Queue[T any] struct {
+	mu       sync.Mutex
+	changed  *sync.Cond // broadcast on every change, waiters recheck their condition
+	items    []T
+	capacity int
+	closed   bool
+	putters  []uint64 // ids of blocked Put calls, in arrival order
+	takers   []uint64
+	nextID   uint64
+}
+
+// NewQueue panics if capacity < 1.
+func NewQueue[T any](capacity int) *Queue[T] {
+	if capacity < 1 {
+		panic("blocking: queue capacity must be positive")
+	}
+	q := &Queue[T]{capacity: capacity, items: make([]T, 0, capacity)}
+	q.changed = sync.NewCond(&q.mu)
+	return q
+}
+
+// Put appends v, waiting for room until ctx is done. It returns ErrClosed once
+// the queue is closed, even if it was waiting before.
+func (q *Queue[T]) Put(ctx context.Context, v T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrClosed
+	}
+	err := q.await(ctx, &q.putters, func() bool { return !q.closed && len(q.items) < q.capacity })
+	if err != nil {
+		return err
+	}
+	q.items = append(q.items, v)
+	q.changed.Broadcast()
+	return nil
+}
+
+// Take removes the oldest item, waiting for one until ctx is done. Items put
+// before Close are still handed out; ErrClosed is returned once none are left.
+func (q *Queue[T]) Take(ctx context.Context) (T, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var zero T
+	if err := q.await(ctx, &q.takers, func() bool { return len(q.items) > 0 }); err != nil {
+		return zero, err
+	}
+	v := q.items[0]
+	q.items[0] = zero
+	q.items = q.items[1:]
+	q.changed.Broadcast()
+	return v, nil
+}
+
+// Close wakes every blocked Put and Take. Closing twice is a no-op.
+func (q *Queue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.changed.Broadcast()
+}
+
+func (q *Queue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// await must be called with q.mu held. It returns nil once ready() holds and
+// every caller that queued up in line before has been served.
+func (q *Queue[T]) await(ctx context.Context, line *[]uint64, ready func() bool) error {
+	if len(*line) == 0 && ready() {
+		return nil
+	}
+	if q.closed {
+		return ErrClosed
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	id := q.nextID
+	q.nextID++
+	*line = append(*line, id)
+	defer q.leave(line, id)
+	// sync.Cond cannot select on ctx.Done(), so cancellation broadcasts instead.
+	stop := context.AfterFunc(ctx, q.broadcast)
+	defer stop()
+
+	for {
+		if (*line)[0] == id && ready() {
+			return nil
+		}
+		if q.closed {
+			return ErrClosed
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		q.changed.Wait()
+	}
+}
+
+// leave removes id from line and wakes the waiter now at its head.
+func (q *Queue[T]) leave(line *[]uint64, id uint64) {
+	*line = slices.DeleteFunc(*line, func(other uint64) bool { return other == id })
+	q.changed.Broadcast()
+}
+
+func (q *Queue[T]) broadcast() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.changed.Broadcast()
+}
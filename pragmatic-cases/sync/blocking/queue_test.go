@@ -0,0 +1,281 @@
+package blocking
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("first in, first out", func(t *testing.T) {
+		q := NewQueue[int](3)
+		for i := range 3 {
+			assertNoError(t, q.Put(ctx, i))
+		}
+		for i := range 3 {
+			assertTake(t, q, i)
+		}
+	})
+
+	t.Run("Put blocks while full", func(t *testing.T) {
+		q := NewQueue[int](1)
+		assertNoError(t, q.Put(ctx, 1))
+
+		done := make(chan error)
+		go func() { done <- q.Put(ctx, 2) }()
+		waitUntil(t, func() bool { return q.waiters(&q.putters) == 1 })
+		if q.Len() != 1 {
+			t.Fatalf("got %d items, want 1", q.Len())
+		}
+
+		assertTake(t, q, 1)
+		assertNoError(t, <-done)
+		assertTake(t, q, 2)
+	})
+
+	t.Run("timeouts", func(t *testing.T) {
+		q := NewQueue[int](1)
+		timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		if _, err := q.Take(timeout); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Take: got %v, want %v", err, context.DeadlineExceeded)
+		}
+
+		assertNoError(t, q.Put(ctx, 1))
+		if err := q.Put(timeout, 2); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Put: got %v, want %v", err, context.DeadlineExceeded)
+		}
+		if q.Len() != 1 || q.waiters(&q.putters) != 0 || q.waiters(&q.takers) != 0 {
+			t.Error("timed out callers must leave no trace")
+		}
+	})
+
+	t.Run("a cancelled waiter does not block the line", func(t *testing.T) {
+		q := NewQueue[int](1)
+		first, cancel := context.WithCancel(ctx)
+		errs := make(chan error)
+		go func() {
+			_, err := q.Take(first)
+			errs <- err
+		}()
+		waitUntil(t, func() bool { return q.waiters(&q.takers) == 1 })
+		got := make(chan int)
+		go func() {
+			v, _ := q.Take(ctx)
+			got <- v
+		}()
+		waitUntil(t, func() bool { return q.waiters(&q.takers) == 2 })
+
+		cancel()
+		if err := <-errs; !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want %v", err, context.Canceled)
+		}
+		assertNoError(t, q.Put(ctx, 7))
+		if v := <-got; v != 7 {
+			t.Errorf("got %d, want 7", v)
+		}
+	})
+
+	t.Run("Close wakes every waiter and drains", func(t *testing.T) {
+		q := NewQueue[int](1)
+		var wg sync.WaitGroup
+		var closedErrs atomic.Int32
+		for range 3 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := q.Take(ctx); errors.Is(err, ErrClosed) {
+					closedErrs.Add(1)
+				}
+			}()
+		}
+		waitUntil(t, func() bool { return q.waiters(&q.takers) == 3 })
+		q.Close()
+		wg.Wait()
+		if closedErrs.Load() != 3 {
+			t.Errorf("got %d ErrClosed, want 3", closedErrs.Load())
+		}
+
+		q = NewQueue[int](1)
+		assertNoError(t, q.Put(ctx, 1))
+		putErr := make(chan error)
+		go func() { putErr <- q.Put(ctx, 2) }()
+		waitUntil(t, func() bool { return q.waiters(&q.putters) == 1 })
+		q.Close()
+		if err := <-putErr; !errors.Is(err, ErrClosed) {
+			t.Errorf("blocked Put: got %v, want %v", err, ErrClosed)
+		}
+		if err := q.Put(ctx, 3); !errors.Is(err, ErrClosed) {
+			t.Errorf("Put after Close: got %v, want %v", err, ErrClosed)
+		}
+		assertTake(t, q, 1)
+		if _, err := q.Take(ctx); !errors.Is(err, ErrClosed) {
+			t.Errorf("Take on a drained closed queue: got %v, want %v", err, ErrClosed)
+		}
+	})
+}
+
+func TestQueueFairness(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("takers are served in arrival order", func(t *testing.T) {
+		q := NewQueue[int](3)
+		got := make([]chan int, 3)
+		for i := range got {
+			got[i] = make(chan int, 1)
+			go func() {
+				v, _ := q.Take(ctx)
+				got[i] <- v
+			}()
+			waitUntil(t, func() bool { return q.waiters(&q.takers) == i+1 })
+		}
+
+		for i := range 3 {
+			assertNoError(t, q.Put(ctx, i))
+		}
+		for i := range got {
+			if v := <-got[i]; v != i {
+				t.Errorf("taker %d got %d", i, v)
+			}
+		}
+	})
+
+	t.Run("putters are served in arrival order", func(t *testing.T) {
+		q := NewQueue[int](1)
+		assertNoError(t, q.Put(ctx, 0))
+		for i := 1; i <= 3; i++ {
+			go q.Put(ctx, i)
+			waitUntil(t, func() bool { return q.waiters(&q.putters) == i })
+		}
+
+		for i := 0; i <= 3; i++ {
+			assertTake(t, q, i)
+		}
+	})
+
+	t.Run("a new taker queues behind the waiting ones", func(t *testing.T) {
+		q := NewQueue[int](2)
+		first := make(chan int, 1)
+		go func() {
+			v, _ := q.Take(ctx)
+			first <- v
+		}()
+		waitUntil(t, func() bool { return q.waiters(&q.takers) == 1 })
+
+		// whether or not the waiting taker already woke up, a Take arriving
+		// now must never get the item
+		assertNoError(t, q.Put(ctx, 1))
+		late, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		if v, err := q.Take(late); err == nil {
+			t.Fatalf("new taker barged in ahead of the waiting one and got %d", v)
+		}
+		if v := <-first; v != 1 {
+			t.Errorf("got %d, want 1", v)
+		}
+	})
+}
+
+// TestQueueStress is meant to be run with -race. Producers, consumers and
+// callers giving up race each other; every item must be taken exactly once
+// and nobody may stay blocked (a lost wake-up would hang until the deadline).
+func TestQueueStress(t *testing.T) {
+	const producers, consumers, items = 8, 8, 500
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	q := NewQueue[int](4)
+	var taken, sum atomic.Int64
+
+	var producing sync.WaitGroup
+	for p := range producers {
+		producing.Add(1)
+		go func() {
+			defer producing.Done()
+			for i := range items {
+				v := p*items + i
+				if i%3 == 0 {
+					// give up after a microsecond once, then block like everyone else
+					attempt, stop := context.WithTimeout(ctx, time.Microsecond)
+					err := q.Put(attempt, v)
+					stop()
+					if err == nil {
+						continue
+					}
+				}
+				if err := q.Put(ctx, v); err != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	var consuming sync.WaitGroup
+	for range consumers {
+		consuming.Add(1)
+		go func() {
+			defer consuming.Done()
+			for {
+				v, err := q.Take(ctx)
+				if err != nil {
+					return
+				}
+				taken.Add(1)
+				sum.Add(int64(v))
+			}
+		}()
+	}
+
+	producing.Wait()
+	q.Close()
+	consuming.Wait()
+
+	if ctx.Err() != nil {
+		t.Fatal("stress test timed out: a waiter was never woken")
+	}
+	n := int64(producers * items)
+	if taken.Load() != n || sum.Load() != n*(n-1)/2 {
+		t.Errorf("took %d items summing to %d, want %d summing to %d", taken.Load(), sum.Load(), n, n*(n-1)/2)
+	}
+}
+
+func (q *Queue[T]) waiters(line *[]uint64) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(*line)
+}
+
+// waitUntil polls cond, failing the test if it does not hold within a second.
+func waitUntil(t testing.TB, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not reached")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func assertTake(t testing.TB, q *Queue[int], want int) {
+	t.Helper()
+	got, err := q.Take(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}